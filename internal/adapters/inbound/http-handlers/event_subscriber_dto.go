@@ -0,0 +1,136 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"time"
+
+	events "template/internal/core/events"
+)
+
+type SubscriberRequest struct {
+	Name                  string   `json:"name"`
+	URL                   string   `json:"url"`
+	EventTypes            []string `json:"eventTypes"`
+	MaxRetries            int      `json:"maxRetries"`
+	BaseRetryDelaySeconds int      `json:"baseRetryDelaySeconds"`
+	MaxRetryDelaySeconds  int      `json:"maxRetryDelaySeconds"`
+}
+
+func (req SubscriberRequest) ToDomain() events.SubscriberRequest {
+	eventTypes := make([]events.EventType, len(req.EventTypes))
+	for i, eventType := range req.EventTypes {
+		eventTypes[i] = events.EventType(eventType)
+	}
+
+	return events.SubscriberRequest{
+		Name:           req.Name,
+		URL:            req.URL,
+		EventTypes:     eventTypes,
+		MaxRetries:     req.MaxRetries,
+		BaseRetryDelay: time.Duration(req.BaseRetryDelaySeconds) * time.Second,
+		MaxRetryDelay:  time.Duration(req.MaxRetryDelaySeconds) * time.Second,
+	}
+}
+
+type SubscriberResponse struct {
+	ID                    string   `json:"id"`
+	Name                  string   `json:"name"`
+	URL                   string   `json:"url"`
+	EventTypes            []string `json:"eventTypes"`
+	MaxRetries            int      `json:"maxRetries"`
+	BaseRetryDelaySeconds int      `json:"baseRetryDelaySeconds"`
+	MaxRetryDelaySeconds  int      `json:"maxRetryDelaySeconds"`
+	Active                bool     `json:"active"`
+	CreatedAt             string   `json:"createdAt"`
+	UpdatedAt             string   `json:"updatedAt"`
+}
+
+func SubscriberToResponse(s events.Subscriber) SubscriberResponse {
+	eventTypes := make([]string, len(s.EventTypes))
+	for i, eventType := range s.EventTypes {
+		eventTypes[i] = string(eventType)
+	}
+
+	return SubscriberResponse{
+		ID:                    s.ID,
+		Name:                  s.Name,
+		URL:                   s.URL,
+		EventTypes:            eventTypes,
+		MaxRetries:            s.MaxRetries,
+		BaseRetryDelaySeconds: int(s.BaseRetryDelay / time.Second),
+		MaxRetryDelaySeconds:  int(s.MaxRetryDelay / time.Second),
+		Active:                s.Active,
+		CreatedAt:             s.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:             s.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+type DeliveryResponse struct {
+	ID             string          `json:"id"`
+	SubscriberID   string          `json:"subscriberId"`
+	EventID        string          `json:"eventId"`
+	EventType      string          `json:"eventType"`
+	Source         string          `json:"source"`
+	SourceEventID  string          `json:"sourceEventId"`
+	OccurredAt     string          `json:"occurredAt"`
+	Data           json.RawMessage `json:"data"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode int             `json:"lastStatusCode,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+	DeliveredAt    *string         `json:"deliveredAt,omitempty"`
+	CreatedAt      string          `json:"createdAt"`
+	UpdatedAt      string          `json:"updatedAt"`
+}
+
+func DeliveryToResponse(d events.Delivery) DeliveryResponse {
+	resp := DeliveryResponse{
+		ID:             d.ID,
+		SubscriberID:   d.SubscriberID,
+		EventID:        d.Event.ID,
+		EventType:      string(d.Event.Type),
+		Source:         d.Event.Source,
+		SourceEventID:  d.Event.SourceEventID,
+		OccurredAt:     d.Event.OccurredAt.Format(time.RFC3339),
+		Data:           d.Event.Data,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		CreatedAt:      d.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      d.UpdatedAt.Format(time.RFC3339),
+	}
+
+	if d.LastError != nil {
+		resp.LastError = *d.LastError
+	}
+
+	if d.DeliveredAt != nil {
+		deliveredAt := d.DeliveredAt.Format(time.RFC3339)
+		resp.DeliveredAt = &deliveredAt
+	}
+
+	return resp
+}
+
+type DeliveryAttemptResponse struct {
+	Attempt     int    `json:"attempt"`
+	StatusCode  int    `json:"statusCode,omitempty"`
+	Error       string `json:"error,omitempty"`
+	DurationMs  int64  `json:"durationMs"`
+	AttemptedAt string `json:"attemptedAt"`
+}
+
+func DeliveryAttemptToResponse(a events.DeliveryAttempt) DeliveryAttemptResponse {
+	resp := DeliveryAttemptResponse{
+		Attempt:     a.Attempt,
+		StatusCode:  a.StatusCode,
+		DurationMs:  a.Duration.Milliseconds(),
+		AttemptedAt: a.AttemptedAt.Format(time.RFC3339),
+	}
+
+	if a.Error != nil {
+		resp.Error = *a.Error
+	}
+
+	return resp
+}
@@ -0,0 +1,17 @@
+package httphandlers
+
+import "github.com/go-chi/chi/v5"
+
+func AcceptEventSubscriberEndpoints(r *chi.Mux, handler EventSubscriberHandler) {
+
+	// TODO: authentication middleware belong here
+
+	r.Route("/admin/events", func(r chi.Router) {
+		r.Post("/subscribers", handler.CreateSubscriberHandler)
+		r.Get("/subscribers", handler.GetSubscribersHandler)
+		r.Delete("/subscribers/{subscriberId}", handler.DeactivateSubscriberHandler)
+		r.Get("/deliveries", handler.GetDeliveriesHandler)
+		r.Get("/deliveries/{deliveryId}/attempts", handler.GetDeliveryAttemptsHandler)
+		r.Post("/deliveries/{deliveryId}/redeliver", handler.RedeliverHandler)
+	})
+}
@@ -0,0 +1,134 @@
+package httphandlers
+
+import (
+	"net/http"
+	"strconv"
+	events "template/internal/core/events"
+	"template/packages/common-go"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type EventSubscriberHandler interface {
+	CreateSubscriberHandler(w http.ResponseWriter, r *http.Request)
+	GetSubscribersHandler(w http.ResponseWriter, r *http.Request)
+	DeactivateSubscriberHandler(w http.ResponseWriter, r *http.Request)
+	GetDeliveriesHandler(w http.ResponseWriter, r *http.Request)
+	GetDeliveryAttemptsHandler(w http.ResponseWriter, r *http.Request)
+	RedeliverHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type eventSubscriberHandler struct {
+	subscribers events.SubscriberManager
+}
+
+func NewEventSubscriberHandler(subscribers events.SubscriberManager) EventSubscriberHandler {
+	return &eventSubscriberHandler{
+		subscribers: subscribers,
+	}
+}
+
+func (h *eventSubscriberHandler) CreateSubscriberHandler(w http.ResponseWriter, r *http.Request) {
+	var req SubscriberRequest
+	if err := common.ReadJSON(r, &req); err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	subscriber, err := h.subscribers.CreateSubscriber(r.Context(), req.ToDomain())
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusCreated, SubscriberToResponse(*subscriber))
+}
+
+func (h *eventSubscriberHandler) GetSubscribersHandler(w http.ResponseWriter, r *http.Request) {
+	subscribers, err := h.subscribers.GetSubscribers(r.Context())
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	response := make([]SubscriberResponse, len(subscribers))
+	for i, subscriber := range subscribers {
+		response[i] = SubscriberToResponse(subscriber)
+	}
+
+	common.WriteJSON(w, http.StatusOK, response)
+}
+
+func (h *eventSubscriberHandler) DeactivateSubscriberHandler(w http.ResponseWriter, r *http.Request) {
+	err := h.subscribers.DeactivateSubscriber(r.Context(), chi.URLParam(r, "subscriberId"))
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, "Subscriber deactivated successfully")
+}
+
+func (h *eventSubscriberHandler) GetDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseDeliveryFilter(r)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	deliveries, err := h.subscribers.GetDeliveries(r.Context(), filter)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	response := make([]DeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		response[i] = DeliveryToResponse(delivery)
+	}
+
+	common.WriteJSON(w, http.StatusOK, response)
+}
+
+func (h *eventSubscriberHandler) GetDeliveryAttemptsHandler(w http.ResponseWriter, r *http.Request) {
+	attempts, err := h.subscribers.GetDeliveryAttempts(r.Context(), chi.URLParam(r, "deliveryId"))
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	response := make([]DeliveryAttemptResponse, len(attempts))
+	for i, attempt := range attempts {
+		response[i] = DeliveryAttemptToResponse(attempt)
+	}
+
+	common.WriteJSON(w, http.StatusOK, response)
+}
+
+func (h *eventSubscriberHandler) RedeliverHandler(w http.ResponseWriter, r *http.Request) {
+	err := h.subscribers.Redeliver(r.Context(), chi.URLParam(r, "deliveryId"))
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusAccepted, "Event delivery queued")
+}
+
+func parseDeliveryFilter(r *http.Request) (events.DeliveryFilter, error) {
+	query := r.URL.Query()
+	filter := events.DeliveryFilter{
+		SubscriberID: query.Get("subscriberId"),
+		Status:       events.DeliveryStatus(query.Get("status")),
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return filter, errInvalidQueryParam.WithMessage("limit must be a non-negative integer")
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
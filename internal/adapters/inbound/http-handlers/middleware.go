@@ -1 +1,31 @@
 package httphandlers
+
+import (
+	"net/http"
+	"template/packages/common-go"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type contextKey string
+
+var errUnauthenticated = common.AppError{
+	Code:    "UNAUTHENTICATED",
+	Message: "authentication required",
+	Status:  http.StatusUnauthorized,
+}
+
+// requestUserID returns the user a request acts on: the {userId} URL
+// parameter on admin routes, otherwise the authenticated user.
+func requestUserID(r *http.Request) (string, error) {
+	if userID := chi.URLParam(r, "userId"); userID != "" {
+		return userID, nil
+	}
+
+	userID := common.UserIDFromContext(r.Context())
+	if userID == "" {
+		return "", errUnauthenticated
+	}
+
+	return userID, nil
+}
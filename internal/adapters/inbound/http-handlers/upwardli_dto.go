@@ -1,8 +1,13 @@
 package httphandlers
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
+	banking "template/internal/core/banking"
+	cards "template/internal/core/cards"
+	transfers "template/internal/core/transfers"
 	webhooks "template/internal/core/webhooks"
 )
 
@@ -38,3 +43,476 @@ func WebhookToResponse(w webhooks.Webhook) WebhookResponse {
 
 	return resp
 }
+
+type WebhookChangeResponse struct {
+	Before WebhookResponse `json:"before"`
+	After  WebhookResponse `json:"after"`
+}
+
+type ReconcileReportResponse struct {
+	DryRun    bool                    `json:"dryRun"`
+	Updated   []WebhookChangeResponse `json:"updated"`
+	Removed   []WebhookResponse       `json:"removed"`
+	Adopted   []WebhookResponse       `json:"adopted"`
+	Missing   []string                `json:"missing"`
+	Recreated []string                `json:"recreated"`
+	Errors    map[string]string       `json:"errors"`
+}
+
+func ReconcileReportToResponse(r webhooks.ReconcileReport) ReconcileReportResponse {
+	resp := ReconcileReportResponse{
+		DryRun:    r.DryRun,
+		Updated:   make([]WebhookChangeResponse, len(r.Updated)),
+		Removed:   make([]WebhookResponse, len(r.Removed)),
+		Adopted:   make([]WebhookResponse, len(r.Adopted)),
+		Missing:   make([]string, len(r.Missing)),
+		Recreated: make([]string, len(r.Recreated)),
+		Errors:    r.Errors,
+	}
+
+	for i, change := range r.Updated {
+		resp.Updated[i] = WebhookChangeResponse{
+			Before: WebhookToResponse(change.Before),
+			After:  WebhookToResponse(change.After),
+		}
+	}
+	for i, webhook := range r.Removed {
+		resp.Removed[i] = WebhookToResponse(webhook)
+	}
+	for i, webhook := range r.Adopted {
+		resp.Adopted[i] = WebhookToResponse(webhook)
+	}
+	for i, topic := range r.Missing {
+		resp.Missing[i] = string(topic)
+	}
+	for i, topic := range r.Recreated {
+		resp.Recreated[i] = string(topic)
+	}
+
+	return resp
+}
+
+type SyncPlanResponse struct {
+	Create []string          `json:"create"`
+	Delete []WebhookResponse `json:"delete"`
+	Keep   []WebhookResponse `json:"keep"`
+}
+
+type SyncResultResponse struct {
+	DryRun  bool              `json:"dryRun"`
+	Plan    SyncPlanResponse  `json:"plan"`
+	Created []string          `json:"created"`
+	Deleted []string          `json:"deleted"`
+	Errors  map[string]string `json:"errors"`
+}
+
+func SyncResultToResponse(r webhooks.SyncResult) SyncResultResponse {
+	resp := SyncResultResponse{
+		DryRun: r.DryRun,
+		Plan: SyncPlanResponse{
+			Create: make([]string, len(r.Plan.Create)),
+			Delete: make([]WebhookResponse, len(r.Plan.Delete)),
+			Keep:   make([]WebhookResponse, len(r.Plan.Keep)),
+		},
+		Created: make([]string, len(r.Created)),
+		Deleted: r.Deleted,
+		Errors:  r.Errors,
+	}
+
+	for i, topic := range r.Plan.Create {
+		resp.Plan.Create[i] = string(topic)
+	}
+	for i, webhook := range r.Plan.Delete {
+		resp.Plan.Delete[i] = WebhookToResponse(webhook)
+	}
+	for i, webhook := range r.Plan.Keep {
+		resp.Plan.Keep[i] = WebhookToResponse(webhook)
+	}
+	for i, topic := range r.Created {
+		resp.Created[i] = string(topic)
+	}
+
+	return resp
+}
+
+type SubscriptionHealthResponse struct {
+	Webhook          WebhookResponse `json:"webhook"`
+	Healthy          bool            `json:"healthy"`
+	Issues           []string        `json:"issues"`
+	PreviousFailures int64           `json:"previousFailures"`
+}
+
+type HealthReportResponse struct {
+	Provider      string                       `json:"provider"`
+	CheckedAt     time.Time                    `json:"checkedAt"`
+	Total         int                          `json:"total"`
+	Unhealthy     int                          `json:"unhealthy"`
+	Subscriptions []SubscriptionHealthResponse `json:"subscriptions"`
+}
+
+func HealthReportToResponse(r webhooks.HealthReport) HealthReportResponse {
+	resp := HealthReportResponse{
+		Provider:      string(r.Provider),
+		CheckedAt:     r.CheckedAt,
+		Total:         r.Total,
+		Unhealthy:     r.Unhealthy,
+		Subscriptions: make([]SubscriptionHealthResponse, len(r.Subscriptions)),
+	}
+
+	for i, health := range r.Subscriptions {
+		issues := make([]string, len(health.Issues))
+		for j, issue := range health.Issues {
+			issues[j] = string(issue)
+		}
+
+		resp.Subscriptions[i] = SubscriptionHealthResponse{
+			Webhook:          WebhookToResponse(health.Webhook),
+			Healthy:          len(health.Issues) == 0,
+			Issues:           issues,
+			PreviousFailures: health.PreviousFailures,
+		}
+	}
+
+	return resp
+}
+
+type DeadLetterResponse struct {
+	EventID        string            `json:"eventId"`
+	Topic          string            `json:"topic"`
+	Payload        json.RawMessage   `json:"payload"`
+	Headers        map[string]string `json:"headers"`
+	Attempts       int               `json:"attempts"`
+	LastError      string            `json:"lastError"`
+	FailedAt       string            `json:"failedAt"`
+	ReplayCount    int               `json:"replayCount"`
+	LastReplayedAt *string           `json:"lastReplayedAt,omitempty"`
+	ResolvedAt     *string           `json:"resolvedAt,omitempty"`
+}
+
+func DeadLetterToResponse(d webhooks.DeadLetter) DeadLetterResponse {
+	resp := DeadLetterResponse{
+		EventID:     d.Event.ID,
+		Topic:       string(d.Event.Topic),
+		Payload:     json.RawMessage(d.Event.Payload),
+		Headers:     d.Event.Headers,
+		Attempts:    d.Event.Attempts,
+		FailedAt:    d.FailedAt.Format(time.RFC3339),
+		ReplayCount: d.ReplayCount,
+	}
+
+	if d.Event.LastError != nil {
+		resp.LastError = *d.Event.LastError
+	}
+
+	if d.LastReplayedAt != nil {
+		lastReplayedAt := d.LastReplayedAt.Format(time.RFC3339)
+		resp.LastReplayedAt = &lastReplayedAt
+	}
+
+	if d.ResolvedAt != nil {
+		resolvedAt := d.ResolvedAt.Format(time.RFC3339)
+		resp.ResolvedAt = &resolvedAt
+	}
+
+	return resp
+}
+
+type ReplayResultResponse struct {
+	Replayed []string          `json:"replayed"`
+	Failed   map[string]string `json:"failed"`
+}
+
+func ReplayResultToResponse(r webhooks.ReplayResult) ReplayResultResponse {
+	return ReplayResultResponse{
+		Replayed: r.Replayed,
+		Failed:   r.Failed,
+	}
+}
+
+type AddressPayload struct {
+	Line1 string `json:"line1,omitempty"`
+	Line2 string `json:"line2,omitempty"`
+	City  string `json:"city,omitempty"`
+	State string `json:"state,omitempty"`
+	Zip   string `json:"zip,omitempty"`
+}
+
+// ConsumerRequest creates a consumer, or updates the non-empty fields of one.
+// Tax details are only accepted on creation.
+type ConsumerRequest struct {
+	FirstName     string         `json:"firstName,omitempty"`
+	LastName      string         `json:"lastName,omitempty"`
+	Email         string         `json:"email,omitempty"`
+	PhoneNumber   string         `json:"phoneNumber,omitempty"`
+	DateOfBirth   string         `json:"dateOfBirth,omitempty"`
+	TaxIDType     string         `json:"taxIdType,omitempty"`
+	TaxIdentifier string         `json:"taxIdentifier,omitempty"`
+	Address       AddressPayload `json:"address"`
+}
+
+func (req ConsumerRequest) ToDomain() banking.Consumer {
+	return banking.Consumer{
+		TaxIDType:     req.TaxIDType,
+		TaxIdentifier: req.TaxIdentifier,
+		Profile: banking.ConsumerProfile{
+			FirstName:   req.FirstName,
+			LastName:    req.LastName,
+			Email:       req.Email,
+			PhoneNumber: req.PhoneNumber,
+			DateOfBirth: req.DateOfBirth,
+			Address: banking.Address{
+				Line1: req.Address.Line1,
+				Line2: req.Address.Line2,
+				City:  req.Address.City,
+				State: req.Address.State,
+				Zip:   req.Address.Zip,
+			},
+		},
+	}
+}
+
+// ConsumerResponse never includes the tax identifier.
+type ConsumerResponse struct {
+	ID          string         `json:"id"`
+	ExternalID  string         `json:"externalId"`
+	IsActive    bool           `json:"isActive"`
+	KYCStatus   string         `json:"kycStatus"`
+	FirstName   string         `json:"firstName"`
+	LastName    string         `json:"lastName"`
+	Email       string         `json:"email"`
+	PhoneNumber string         `json:"phoneNumber,omitempty"`
+	DateOfBirth string         `json:"dateOfBirth,omitempty"`
+	Address     AddressPayload `json:"address"`
+}
+
+func ConsumerToResponse(c banking.Consumer) ConsumerResponse {
+	return ConsumerResponse{
+		ID:          c.ID,
+		ExternalID:  c.ExternalID,
+		IsActive:    c.IsActive,
+		KYCStatus:   string(c.KYCStatus),
+		FirstName:   c.Profile.FirstName,
+		LastName:    c.Profile.LastName,
+		Email:       c.Profile.Email,
+		PhoneNumber: c.Profile.PhoneNumber,
+		DateOfBirth: c.Profile.DateOfBirth,
+		Address: AddressPayload{
+			Line1: c.Profile.Address.Line1,
+			Line2: c.Profile.Address.Line2,
+			City:  c.Profile.Address.City,
+			State: c.Profile.Address.State,
+			Zip:   c.Profile.Address.Zip,
+		},
+	}
+}
+
+type EmbeddedSessionRequest struct {
+	Component string `json:"component"`
+}
+
+type EmbeddedSessionResponse struct {
+	Component string `json:"component"`
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
+func EmbeddedSessionToResponse(s banking.EmbeddedSession) EmbeddedSessionResponse {
+	return EmbeddedSessionResponse{
+		Component: string(s.Component),
+		URL:       s.URL,
+		ExpiresAt: s.ExpiresAt.Format(time.RFC3339),
+	}
+}
+
+type KYCTransitionResponse struct {
+	ConsumerID    string `json:"consumerId"`
+	From          string `json:"from"`
+	To            string `json:"to"`
+	SourceEventID string `json:"sourceEventId"`
+	Accepted      bool   `json:"accepted"`
+	Reason        string `json:"reason,omitempty"`
+	OccurredAt    string `json:"occurredAt"`
+	CreatedAt     string `json:"createdAt"`
+}
+
+func KYCTransitionToResponse(t banking.KYCTransition) KYCTransitionResponse {
+	return KYCTransitionResponse{
+		ConsumerID:    t.ConsumerID,
+		From:          string(t.From),
+		To:            string(t.To),
+		SourceEventID: t.SourceEventID,
+		Accepted:      t.Accepted,
+		Reason:        t.Reason,
+		OccurredAt:    t.OccurredAt.Format(time.RFC3339),
+		CreatedAt:     t.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+type CardResponse struct {
+	ID              string  `json:"id"`
+	ConsumerID      string  `json:"consumerId"`
+	LastFour        string  `json:"lastFour"`
+	Type            string  `json:"type"`
+	Status          string  `json:"status"`
+	ExpirationMonth int     `json:"expirationMonth"`
+	ExpirationYear  int     `json:"expirationYear"`
+	ClosedAt        *string `json:"closedAt,omitempty"`
+	UpdatedAt       string  `json:"updatedAt"`
+}
+
+func CardToResponse(c cards.Card) CardResponse {
+	resp := CardResponse{
+		ID:              c.ID,
+		ConsumerID:      c.ConsumerID,
+		LastFour:        c.LastFour,
+		Type:            string(c.Type),
+		Status:          string(c.Status),
+		ExpirationMonth: c.ExpirationMonth,
+		ExpirationYear:  c.ExpirationYear,
+		UpdatedAt:       c.UpdatedAt.Format(time.RFC3339),
+	}
+
+	if c.ClosedAt != nil {
+		closedAt := c.ClosedAt.Format(time.RFC3339)
+		resp.ClosedAt = &closedAt
+	}
+
+	return resp
+}
+
+type CardTransactionResponse struct {
+	ID                   string `json:"id"`
+	Amount               int64  `json:"amount"`
+	Currency             string `json:"currency"`
+	MerchantName         string `json:"merchantName"`
+	MerchantCategoryCode string `json:"merchantCategoryCode"`
+	Description          string `json:"description"`
+	SettledAt            string `json:"settledAt"`
+}
+
+type CardTransactionsResponse struct {
+	Transactions []CardTransactionResponse `json:"transactions"`
+	// SettledTotals sums settled amounts per currency, in cents.
+	SettledTotals map[string]int64 `json:"settledTotals"`
+}
+
+func CardTransactionsToResponse(transactions []cards.Transaction) CardTransactionsResponse {
+	resp := CardTransactionsResponse{
+		Transactions:  make([]CardTransactionResponse, len(transactions)),
+		SettledTotals: map[string]int64{},
+	}
+
+	for i, t := range transactions {
+		resp.Transactions[i] = CardTransactionResponse{
+			ID:                   t.ID,
+			Amount:               t.Amount,
+			Currency:             t.Currency,
+			MerchantName:         t.MerchantName,
+			MerchantCategoryCode: t.MerchantCategoryCode,
+			Description:          t.Description,
+			SettledAt:            t.SettledAt.Format(time.RFC3339),
+		}
+		resp.SettledTotals[t.Currency] += t.Amount
+	}
+
+	return resp
+}
+
+type TransferResponse struct {
+	ID                   string `json:"id"`
+	ProviderTransferID   string `json:"providerTransferId,omitempty"`
+	ConsumerID           string `json:"consumerId"`
+	Type                 string `json:"type"`
+	Direction            string `json:"direction"`
+	Status               string `json:"status"`
+	Amount               int64  `json:"amount"`
+	Currency             string `json:"currency"`
+	SourceAccountID      string `json:"sourceAccountId,omitempty"`
+	DestinationAccountID string `json:"destinationAccountId,omitempty"`
+	Description          string `json:"description,omitempty"`
+	FailureReason        string `json:"failureReason,omitempty"`
+	StatusUpdatedAt      string `json:"statusUpdatedAt"`
+	CreatedAt            string `json:"createdAt"`
+}
+
+func TransferToResponse(t transfers.Transfer) TransferResponse {
+	return TransferResponse{
+		ID:                   t.ID,
+		ProviderTransferID:   t.ProviderTransferID,
+		ConsumerID:           t.ConsumerID,
+		Type:                 string(t.Type),
+		Direction:            string(t.Direction),
+		Status:               string(t.Status),
+		Amount:               t.Amount,
+		Currency:             t.Currency,
+		SourceAccountID:      t.SourceAccountID,
+		DestinationAccountID: t.DestinationAccountID,
+		Description:          t.Description,
+		FailureReason:        t.FailureReason,
+		StatusUpdatedAt:      t.StatusUpdatedAt.Format(time.RFC3339),
+		CreatedAt:            t.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func TransfersToResponse(ts []transfers.Transfer) []TransferResponse {
+	response := make([]TransferResponse, len(ts))
+	for i, t := range ts {
+		response[i] = TransferToResponse(t)
+	}
+
+	return response
+}
+
+type InitiateTransferRequest struct {
+	ConsumerID            string `json:"consumerId"`
+	Type                  string `json:"type"`
+	Direction             string `json:"direction"`
+	Amount                int64  `json:"amount"`
+	Currency              string `json:"currency"`
+	CounterpartyAccountID string `json:"counterpartyAccountId,omitempty"`
+	Description           string `json:"description,omitempty"`
+}
+
+func (req InitiateTransferRequest) ToDomain(userID, idempotencyKey string) transfers.InitiateRequest {
+	currency := strings.ToUpper(req.Currency)
+	if currency == "" {
+		currency = "USD"
+	}
+
+	return transfers.InitiateRequest{
+		UserID:                userID,
+		ConsumerID:            req.ConsumerID,
+		Type:                  transfers.Type(strings.ToLower(req.Type)),
+		Direction:             transfers.Direction(strings.ToLower(req.Direction)),
+		Amount:                req.Amount,
+		Currency:              currency,
+		CounterpartyAccountID: req.CounterpartyAccountID,
+		Description:           req.Description,
+		IdempotencyKey:        idempotencyKey,
+	}
+}
+
+type TransferTransitionResponse struct {
+	From          string `json:"from"`
+	To            string `json:"to"`
+	SourceEventID string `json:"sourceEventId"`
+	Accepted      bool   `json:"accepted"`
+	Reason        string `json:"reason,omitempty"`
+	OccurredAt    string `json:"occurredAt"`
+}
+
+func TransferTransitionToResponse(t transfers.StatusTransition) TransferTransitionResponse {
+	return TransferTransitionResponse{
+		From:          string(t.From),
+		To:            string(t.To),
+		SourceEventID: t.SourceEventID,
+		Accepted:      t.Accepted,
+		Reason:        t.Reason,
+		OccurredAt:    t.OccurredAt.Format(time.RFC3339),
+	}
+}
+
+type TransferDetailResponse struct {
+	TransferResponse
+	History []TransferTransitionResponse `json:"history"`
+}
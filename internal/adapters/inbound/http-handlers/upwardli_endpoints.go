@@ -11,6 +11,15 @@ func AcceptUpwardliEndpoints(r *chi.Mux, handler UpwardliHandler) {
 		r.Post("/webhooks/all", handler.CreateAllWebhooksHandler)
 		r.Get("/webhooks", handler.GetWebhooksHandler)
 		r.Delete("/webhooks/{id}", handler.DeleteWebhookHandler)
+		r.Post("/consumer", handler.CreateConsumerHandler)
+		r.Get("/consumer", handler.GetConsumerHandler)
+		r.Patch("/consumer", handler.UpdateConsumerHandler)
+		r.Delete("/consumer", handler.CloseConsumerHandler)
+		r.Post("/embedded-sessions", handler.CreateEmbeddedSessionHandler)
+		r.Get("/cards", handler.GetUserCardsHandler)
+		r.Get("/cards/{cardId}/transactions", handler.GetUserCardTransactionsHandler)
+		r.Get("/transfers", handler.GetUserTransfersHandler)
+		r.Post("/transfers", handler.InitiateTransferHandler)
 	})
 
 	r.Route("/admin/users/{userId}/upwardli", func(r chi.Router) {
@@ -18,6 +27,30 @@ func AcceptUpwardliEndpoints(r *chi.Mux, handler UpwardliHandler) {
 		r.Post("/webhooks/all", handler.CreateAllWebhooksHandler)
 		r.Get("/webhooks", handler.GetWebhooksHandler)
 		r.Delete("/webhooks/{id}", handler.DeleteWebhookHandler)
+		r.Post("/consumer", handler.CreateConsumerHandler)
+		r.Get("/consumer", handler.GetConsumerHandler)
+		r.Patch("/consumer", handler.UpdateConsumerHandler)
+		r.Delete("/consumer", handler.CloseConsumerHandler)
+		r.Get("/cards", handler.GetUserCardsHandler)
+		r.Get("/cards/{cardId}/transactions", handler.GetUserCardTransactionsHandler)
+		r.Get("/transfers", handler.GetUserTransfersHandler)
+		r.Post("/transfers", handler.InitiateTransferHandler)
+	})
+
+	r.Get("/admin/webhooks/health", handler.GetWebhookHealthHandler)
+
+	r.Route("/admin/upwardli", func(r chi.Router) {
+		r.Post("/webhooks/sync", handler.SyncWebhooksHandler)
+		r.Post("/webhooks/reconcile", handler.ReconcileWebhooksHandler)
+		r.Get("/webhook-events/dead-letters", handler.GetDeadLettersHandler)
+		r.Get("/webhook-events/dead-letters/{eventId}", handler.GetDeadLetterHandler)
+		r.Post("/webhook-events/dead-letters/replay", handler.ReplayDeadLettersHandler)
+		r.Post("/webhook-events/dead-letters/{eventId}/replay", handler.ReplayDeadLetterHandler)
+		r.Get("/consumers/{consumerId}/kyc-history", handler.GetConsumerKYCHistoryHandler)
+		r.Get("/consumers/{consumerId}/cards", handler.GetConsumerCardsHandler)
+		r.Get("/cards/{cardId}/transactions", handler.GetCardTransactionsHandler)
+		r.Get("/consumers/{consumerId}/transfers", handler.GetConsumerTransfersHandler)
+		r.Get("/transfers/{transferId}", handler.GetTransferHandler)
 	})
 
 }
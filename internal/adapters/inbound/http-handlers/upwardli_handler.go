@@ -1,65 +1,109 @@
 package httphandlers
 
 import (
-	"io"
 	"net/http"
-	"strings"
+	"strconv"
 	webhookprocessors "template/internal/adapters/inbound/webhook-processors"
 	"template/internal/config"
+	banking "template/internal/core/banking"
+	cards "template/internal/core/cards"
+	transfers "template/internal/core/transfers"
 	webhooks "template/internal/core/webhooks"
 	"template/packages/common-go"
+	"time"
+
+	"github.com/go-chi/chi/v5"
 )
 
+var errInvalidQueryParam = common.AppError{
+	Code:   "INVALID_INPUT",
+	Status: http.StatusBadRequest,
+}
+
 type UpwardliHandler interface {
 	CreateAllWebhooksHandler(w http.ResponseWriter, r *http.Request)
+	SyncWebhooksHandler(w http.ResponseWriter, r *http.Request)
 	CreateWebhookHandler(w http.ResponseWriter, r *http.Request)
 	GetWebhooksHandler(w http.ResponseWriter, r *http.Request)
 	DeleteWebhookHandler(w http.ResponseWriter, r *http.Request)
-	ProcessWebhookHandler(w http.ResponseWriter, r *http.Request)
+	GetDeadLettersHandler(w http.ResponseWriter, r *http.Request)
+	GetDeadLetterHandler(w http.ResponseWriter, r *http.Request)
+	ReplayDeadLetterHandler(w http.ResponseWriter, r *http.Request)
+	ReplayDeadLettersHandler(w http.ResponseWriter, r *http.Request)
+	ReconcileWebhooksHandler(w http.ResponseWriter, r *http.Request)
+	GetWebhookHealthHandler(w http.ResponseWriter, r *http.Request)
+	CreateConsumerHandler(w http.ResponseWriter, r *http.Request)
+	GetConsumerHandler(w http.ResponseWriter, r *http.Request)
+	UpdateConsumerHandler(w http.ResponseWriter, r *http.Request)
+	CloseConsumerHandler(w http.ResponseWriter, r *http.Request)
+	CreateEmbeddedSessionHandler(w http.ResponseWriter, r *http.Request)
+	GetConsumerKYCHistoryHandler(w http.ResponseWriter, r *http.Request)
+	GetConsumerCardsHandler(w http.ResponseWriter, r *http.Request)
+	GetCardTransactionsHandler(w http.ResponseWriter, r *http.Request)
+	GetUserCardsHandler(w http.ResponseWriter, r *http.Request)
+	GetUserCardTransactionsHandler(w http.ResponseWriter, r *http.Request)
+	GetUserTransfersHandler(w http.ResponseWriter, r *http.Request)
+	InitiateTransferHandler(w http.ResponseWriter, r *http.Request)
+	GetConsumerTransfersHandler(w http.ResponseWriter, r *http.Request)
+	GetTransferHandler(w http.ResponseWriter, r *http.Request)
 }
 
 type upwardliHandler struct {
 	webhooksService webhooks.Service
+	webhookHealth   webhooks.HealthMonitor
 	cfg             config.Config
-	processor       webhooks.Processor
+	deadLetters     webhooks.DeadLetterManager
+	consumers       banking.ConsumerManager
+	cards           cards.Service
+	transfers       transfers.Service
 }
 
-func NewUpwardliHandler(cfg config.Config, service webhooks.Service, processor webhooks.Processor) UpwardliHandler {
+func NewUpwardliHandler(
+	cfg config.Config,
+	service webhooks.Service,
+	webhookHealth webhooks.HealthMonitor,
+	deadLetters webhooks.DeadLetterManager,
+	consumers banking.ConsumerManager,
+	cardsService cards.Service,
+	transfersService transfers.Service,
+) UpwardliHandler {
 	return &upwardliHandler{
 		webhooksService: service,
+		webhookHealth:   webhookHealth,
 		cfg:             cfg,
-		processor:       processor,
+		deadLetters:     deadLetters,
+		consumers:       consumers,
+		cards:           cardsService,
+		transfers:       transfersService,
 	}
 }
 
+// CreateAllWebhooksHandler registers every topic we handle by converging on
+// the desired state, so calling it again doesn't register duplicates.
 func (h *upwardliHandler) CreateAllWebhooksHandler(w http.ResponseWriter, r *http.Request) {
-	err := h.webhooksService.CreateWebhooks(r.Context(), h.cfg.Upwardli().WebhookURL, []webhooks.SubscriptionTopic{
-		webhookprocessors.SubscriptionTopicConsumerCreated,
-		webhookprocessors.SubscriptionTopicConsumerUpdated,
-		webhookprocessors.SubscriptionTopicConsumerClosed,
-		webhookprocessors.SubscriptionTopicConsumerKYCStarted,
-		webhookprocessors.SubscriptionTopicConsumerKYCPending,
-		webhookprocessors.SubscriptionTopicConsumerKYCCompleted,
-		webhookprocessors.SubscriptionTopicConsumerKYCNeedsReview,
-		webhookprocessors.SubscriptionTopicConsumerKYCApproved,
-		webhookprocessors.SubscriptionTopicConsumerKYCFailed,
-		webhookprocessors.SubscriptionTopicPaymentCardCreated,
-		webhookprocessors.SubscriptionTopicPaymentCardUpdated,
-		webhookprocessors.SubscriptionTopicPaymentCardClosed,
-		webhookprocessors.SubscriptionTopicPaymentCardTransactionSettlement,
-		webhookprocessors.SubscriptionTopicACHSent,
-		webhookprocessors.SubscriptionTopicACHReceived,
-		webhookprocessors.SubscriptionTopicACHFailed,
-		webhookprocessors.SubscriptionTopicPaymentTransferCreated,
-		webhookprocessors.SubscriptionTopicPaymentTransferCompleted,
-		webhookprocessors.SubscriptionTopicPaymentTransferFailed,
-	})
+	result, err := h.webhooksService.Sync(r.Context(), webhookprocessors.UpwardliDesiredState(h.cfg.Upwardli().WebhookURL), false)
 	if err != nil {
 		common.WriteError(w, err)
 		return
 	}
 
-	common.WriteJSON(w, http.StatusOK, "Webhooks created successfully")
+	common.WriteJSON(w, http.StatusOK, SyncResultToResponse(result))
+}
+
+func (h *upwardliHandler) SyncWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := parseBoolParam(r, "dryRun")
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	result, err := h.webhooksService.Sync(r.Context(), webhookprocessors.UpwardliDesiredState(h.cfg.Upwardli().WebhookURL), dryRun)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, SyncResultToResponse(result))
 }
 
 func (h *upwardliHandler) CreateWebhookHandler(w http.ResponseWriter, r *http.Request) {
@@ -101,23 +145,401 @@ func (h *upwardliHandler) DeleteWebhookHandler(w http.ResponseWriter, r *http.Re
 	common.WriteJSON(w, http.StatusOK, "Webhook deleted successfully")
 }
 
-func (h *upwardliHandler) ProcessWebhookHandler(w http.ResponseWriter, r *http.Request) {
-	body, err := io.ReadAll(r.Body)
+func (h *upwardliHandler) GetDeadLettersHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseDeadLetterFilter(r)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	deadLetters, err := h.deadLetters.GetDeadLetters(r.Context(), filter)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	response := make([]DeadLetterResponse, len(deadLetters))
+	for i, deadLetter := range deadLetters {
+		response[i] = DeadLetterToResponse(deadLetter)
+	}
+
+	common.WriteJSON(w, http.StatusOK, response)
+}
+
+func (h *upwardliHandler) GetDeadLetterHandler(w http.ResponseWriter, r *http.Request) {
+	deadLetter, err := h.deadLetters.GetDeadLetter(r.Context(), chi.URLParam(r, "eventId"))
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, DeadLetterToResponse(*deadLetter))
+}
+
+func (h *upwardliHandler) ReplayDeadLetterHandler(w http.ResponseWriter, r *http.Request) {
+	err := h.deadLetters.ReplayDeadLetter(r.Context(), chi.URLParam(r, "eventId"))
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, "Webhook event replayed successfully")
+}
+
+func (h *upwardliHandler) ReplayDeadLettersHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseDeadLetterFilter(r)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	result, err := h.deadLetters.ReplayDeadLetters(r.Context(), filter)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, ReplayResultToResponse(result))
+}
+
+func (h *upwardliHandler) ReconcileWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := parseBoolParam(r, "dryRun")
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+	recreateMissing, err := parseBoolParam(r, "recreateMissing")
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	report, err := h.webhooksService.Reconcile(r.Context(), webhooks.ReconcileOptions{
+		DryRun:          dryRun,
+		Endpoint:        h.cfg.Upwardli().WebhookURL,
+		RequiredTopics:  webhookprocessors.UpwardliSubscriptionTopics,
+		RecreateMissing: recreateMissing,
+	})
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, ReconcileReportToResponse(report))
+}
+
+func (h *upwardliHandler) GetWebhookHealthHandler(w http.ResponseWriter, r *http.Request) {
+	refresh, err := parseBoolParam(r, "refresh")
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	report := h.webhookHealth.LastReport()
+	if report == nil || refresh {
+		checked, err := h.webhookHealth.Check(r.Context())
+		if err != nil {
+			common.WriteError(w, err)
+			return
+		}
+		report = &checked
+	}
+
+	common.WriteJSON(w, http.StatusOK, HealthReportToResponse(*report))
+}
+
+func (h *upwardliHandler) CreateConsumerHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	var req ConsumerRequest
+	if err := common.ReadJSON(r, &req); err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	consumer, err := h.consumers.CreateUserConsumer(r.Context(), userID, req.ToDomain())
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusCreated, ConsumerToResponse(*consumer))
+}
+
+func (h *upwardliHandler) GetConsumerHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	consumer, err := h.consumers.GetUserConsumer(r.Context(), userID)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, ConsumerToResponse(*consumer))
+}
+
+func (h *upwardliHandler) UpdateConsumerHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	var req ConsumerRequest
+	if err := common.ReadJSON(r, &req); err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	consumer, err := h.consumers.UpdateUserConsumer(r.Context(), userID, req.ToDomain().Profile)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, ConsumerToResponse(*consumer))
+}
+
+func (h *upwardliHandler) CloseConsumerHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	if err := h.consumers.CloseUserConsumer(r.Context(), userID); err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, "Consumer closed successfully")
+}
+
+func (h *upwardliHandler) CreateEmbeddedSessionHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	var req EmbeddedSessionRequest
+	if err := common.ReadJSON(r, &req); err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	session, err := h.consumers.CreateEmbeddedSession(r.Context(), userID, banking.EmbeddedComponent(req.Component))
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusCreated, EmbeddedSessionToResponse(*session))
+}
+
+func (h *upwardliHandler) GetConsumerKYCHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	transitions, err := h.consumers.GetKYCHistory(r.Context(), chi.URLParam(r, "consumerId"))
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	response := make([]KYCTransitionResponse, len(transitions))
+	for i, transition := range transitions {
+		response[i] = KYCTransitionToResponse(transition)
+	}
+
+	common.WriteJSON(w, http.StatusOK, response)
+}
+
+func (h *upwardliHandler) GetConsumerCardsHandler(w http.ResponseWriter, r *http.Request) {
+	consumerCards, err := h.cards.GetConsumerCards(r.Context(), chi.URLParam(r, "consumerId"))
 	if err != nil {
 		common.WriteError(w, err)
 		return
 	}
 
-	headers := make(map[string]string)
-	for key, values := range r.Header {
-		headers[key] = strings.Join(values, ",")
+	response := make([]CardResponse, len(consumerCards))
+	for i, card := range consumerCards {
+		response[i] = CardToResponse(card)
 	}
 
-	err = h.processor.Process(r.Context(), body, headers)
+	common.WriteJSON(w, http.StatusOK, response)
+}
+
+func (h *upwardliHandler) GetCardTransactionsHandler(w http.ResponseWriter, r *http.Request) {
+	transactions, err := h.cards.GetCardTransactions(r.Context(), chi.URLParam(r, "cardId"))
 	if err != nil {
 		common.WriteError(w, err)
 		return
 	}
 
-	common.WriteJSON(w, http.StatusOK, "Webhook processed successfully")
+	common.WriteJSON(w, http.StatusOK, CardTransactionsToResponse(transactions))
+}
+
+func (h *upwardliHandler) GetUserCardsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	userCards, err := h.cards.GetUserCards(r.Context(), userID)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	response := make([]CardResponse, len(userCards))
+	for i, card := range userCards {
+		response[i] = CardToResponse(card)
+	}
+
+	common.WriteJSON(w, http.StatusOK, response)
+}
+
+func (h *upwardliHandler) GetUserCardTransactionsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	transactions, err := h.cards.GetUserCardTransactions(r.Context(), userID, chi.URLParam(r, "cardId"))
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, CardTransactionsToResponse(transactions))
+}
+
+func (h *upwardliHandler) GetUserTransfersHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	userTransfers, err := h.transfers.GetUserTransfers(r.Context(), userID)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, TransfersToResponse(userTransfers))
+}
+
+func (h *upwardliHandler) InitiateTransferHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	var req InitiateTransferRequest
+	if err := common.ReadJSON(r, &req); err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	transfer, err := h.transfers.InitiateTransfer(r.Context(), req.ToDomain(
+		userID,
+		r.Header.Get("Idempotency-Key"),
+	))
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusAccepted, TransferToResponse(*transfer))
+}
+
+func (h *upwardliHandler) GetConsumerTransfersHandler(w http.ResponseWriter, r *http.Request) {
+	consumerTransfers, err := h.transfers.GetConsumerTransfers(r.Context(), chi.URLParam(r, "consumerId"))
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, TransfersToResponse(consumerTransfers))
+}
+
+func (h *upwardliHandler) GetTransferHandler(w http.ResponseWriter, r *http.Request) {
+	transfer, err := h.transfers.GetTransfer(r.Context(), chi.URLParam(r, "transferId"))
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	history, err := h.transfers.GetTransferHistory(r.Context(), transfer.ID)
+	if err != nil {
+		common.WriteError(w, err)
+		return
+	}
+
+	response := TransferDetailResponse{
+		TransferResponse: TransferToResponse(*transfer),
+		History:          make([]TransferTransitionResponse, len(history)),
+	}
+	for i, transition := range history {
+		response.History[i] = TransferTransitionToResponse(transition)
+	}
+
+	common.WriteJSON(w, http.StatusOK, response)
+}
+
+func parseBoolParam(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errInvalidQueryParam.WithMessagef("invalid %s: %s", name, value)
+	}
+
+	return parsed, nil
+}
+
+func parseDeadLetterFilter(r *http.Request) (webhooks.DeadLetterFilter, error) {
+	query := r.URL.Query()
+	filter := webhooks.DeadLetterFilter{
+		Topic: webhooks.SubscriptionTopic(query.Get("topic")),
+	}
+
+	if value := query.Get("failedAfter"); value != "" {
+		failedAfter, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, errInvalidQueryParam.WithMessage("failedAfter must be an RFC3339 timestamp")
+		}
+		filter.FailedAfter = &failedAfter
+	}
+
+	if value := query.Get("failedBefore"); value != "" {
+		failedBefore, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, errInvalidQueryParam.WithMessage("failedBefore must be an RFC3339 timestamp")
+		}
+		filter.FailedBefore = &failedBefore
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return filter, errInvalidQueryParam.WithMessage("limit must be a non-negative integer")
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
 }
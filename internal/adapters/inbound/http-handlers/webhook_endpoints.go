@@ -0,0 +1,13 @@
+package httphandlers
+
+import "github.com/go-chi/chi/v5"
+
+func AcceptWebhookEndpoints(r *chi.Mux, handler WebhookHandler) {
+
+	// Public: providers are authenticated by their signature, so these routes
+	// stay outside the /me and /admin authentication
+	r.Group(func(r chi.Router) {
+		r.Use(handler.VerifyWebhook)
+		r.Post("/webhooks/{provider}", handler.ReceiveWebhookHandler)
+	})
+}
@@ -0,0 +1,90 @@
+package httphandlers
+
+import (
+	"context"
+	"net/http"
+	webhooks "template/internal/core/webhooks"
+	"template/internal/logger"
+	"template/packages/common-go"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+const webhookProviderContextKey contextKey = "webhookProvider"
+
+var errUnverifiedWebhook = common.AppError{
+	Code:    "INTERNAL_ERROR",
+	Message: "webhook was not verified",
+	Status:  http.StatusInternalServerError,
+}
+
+type WebhookHandler interface {
+	// VerifyWebhook limits the body size and checks the signature with the
+	// {provider}'s verifier before calling next.
+	VerifyWebhook(next http.Handler) http.Handler
+	ReceiveWebhookHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type webhookHandler struct {
+	logger    logger.Logger
+	providers webhooks.ProviderRegistry
+}
+
+func NewWebhookHandler(logger logger.Logger, providers webhooks.ProviderRegistry) WebhookHandler {
+	return &webhookHandler{
+		logger:    logger,
+		providers: providers,
+	}
+}
+
+func (h *webhookHandler) VerifyWebhook(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		registration, err := h.providers.Lookup(webhooks.Provider(chi.URLParam(r, "provider")))
+		if err != nil {
+			common.WriteError(w, err)
+			return
+		}
+
+		maxBodyBytes := registration.MaxBodyBytes
+		if maxBodyBytes <= 0 {
+			maxBodyBytes = webhooks.DefaultMaxBodyBytes
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+
+		ctx := context.WithValue(r.Context(), webhookProviderContextKey, registration)
+		webhooks.WithWebhookVerification(registration.Verifier, h.logger)(next).ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ReceiveWebhookHandler stores a delivery verified by VerifyWebhook in the
+// provider's inbox.
+func (h *webhookHandler) ReceiveWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	registration, ok := r.Context().Value(webhookProviderContextKey).(webhooks.ProviderRegistration)
+	delivery, verified := webhooks.DeliveryFromContext(r.Context())
+	if !ok || !verified {
+		h.logger.Error("webhook receiver is mounted without VerifyWebhook", zap.String("path", r.URL.Path))
+		common.WriteError(w, errUnverifiedWebhook)
+		return
+	}
+
+	created, err := registration.Inbox.Receive(r.Context(), delivery.Body, delivery.Headers)
+	if err != nil {
+		// The delivery was verified but not stored, so the provider's retry
+		// must not be rejected as a replay
+		if releaseErr := registration.Verifier.Release(r.Context(), delivery.Body, delivery.Headers); releaseErr != nil {
+			h.logger.Error("failed to release webhook delivery",
+				zap.Error(releaseErr),
+				zap.String("provider", chi.URLParam(r, "provider")))
+		}
+		common.WriteError(w, err)
+		return
+	}
+
+	if !created {
+		common.WriteJSON(w, http.StatusOK, "Webhook already received")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusAccepted, "Webhook received successfully")
+}
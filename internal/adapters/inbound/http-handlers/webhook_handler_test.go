@@ -0,0 +1,415 @@
+package httphandlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	httphandlers "template/internal/adapters/inbound/http-handlers"
+	webhookprocessors "template/internal/adapters/inbound/webhook-processors"
+	webhooks "template/internal/core/webhooks"
+	"template/internal/logger"
+	webhookSDK "template/packages/webhook-go"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	testSecret       = "whsec_test"
+	testMaxBodyBytes = 1024
+)
+
+type fakeProcessor struct{}
+
+func (p *fakeProcessor) ParseEvent(body []byte) (webhooks.Event, error) {
+	var payload struct {
+		ID    string `json:"id"`
+		Topic string `json:"topic"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return webhooks.Event{}, err
+	}
+	return webhooks.Event{ID: payload.ID, Topic: webhooks.SubscriptionTopic(payload.Topic)}, nil
+}
+
+func (p *fakeProcessor) Process(ctx context.Context, body []byte, headers map[string]string) error {
+	return nil
+}
+
+type fakeSubscriptionClient struct{}
+
+func (c *fakeSubscriptionClient) GetAllWebhooks(ctx context.Context) ([]webhooks.Webhook, error) {
+	return nil, nil
+}
+
+func (c *fakeSubscriptionClient) CreateWebhook(ctx context.Context, endpoint string, topic string) (*webhooks.Webhook, error) {
+	return nil, nil
+}
+
+func (c *fakeSubscriptionClient) GetWebhook(ctx context.Context, webhookID string) (*webhooks.Webhook, error) {
+	return nil, nil
+}
+
+func (c *fakeSubscriptionClient) DeleteWebhook(ctx context.Context, webhookID string) error {
+	return nil
+}
+
+// fakeInbox stores event IDs in memory and reports repeats as duplicates.
+type fakeInbox struct {
+	mu        sync.Mutex
+	processor fakeProcessor
+	received  map[string]bool
+	// failures is the number of deliveries to fail before storing any
+	failures int
+	calls    int
+}
+
+func (i *fakeInbox) Receive(ctx context.Context, body []byte, headers map[string]string) (bool, error) {
+	event, err := i.processor.ParseEvent(body)
+	if err != nil {
+		return false, err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.calls++
+	if i.failures > 0 {
+		i.failures--
+		return false, fmt.Errorf("failed to store event %s", event.ID)
+	}
+	if i.received[event.ID] {
+		return false, nil
+	}
+	i.received[event.ID] = true
+	return true, nil
+}
+
+func (i *fakeInbox) Resume(ctx context.Context) error {
+	return nil
+}
+
+func (i *fakeInbox) Sweep(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func (i *fakeInbox) Process(ctx context.Context, event webhooks.Event) error {
+	return nil
+}
+
+func (i *fakeInbox) Fail(ctx context.Context, event webhooks.Event, cause error) error {
+	return nil
+}
+
+func (i *fakeInbox) Defer(ctx context.Context, event webhooks.Event, cause error) error {
+	return nil
+}
+
+func newTestServer(t *testing.T) (*httptest.Server, *fakeInbox) {
+	t.Helper()
+
+	inbox := &fakeInbox{received: map[string]bool{}}
+	providers := webhooks.NewProviderRegistry()
+	err := providers.Register(webhooks.ProviderUpwardli, webhooks.ProviderRegistration{
+		Verifier: webhookprocessors.NewUpwardliVerifier(
+			&logger.NoOpLogger{},
+			[]webhookSDK.Secret{{ID: "default", Value: testSecret}},
+			webhookSDK.DefaultTolerance,
+			webhookSDK.NewMemoryNonceStore(0),
+		),
+		Processor:    &fakeProcessor{},
+		Client:       &fakeSubscriptionClient{},
+		Inbox:        inbox,
+		MaxBodyBytes: testMaxBodyBytes,
+	})
+	if err != nil {
+		t.Fatalf("failed to register provider: %v", err)
+	}
+
+	r := chi.NewRouter()
+	httphandlers.AcceptWebhookEndpoints(r, httphandlers.NewWebhookHandler(&logger.NoOpLogger{}, providers))
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	return server, inbox
+}
+
+func sign(secret string, timestamp time.Time, body string) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	signature := webhookSDK.NewVerifier(secret).Sign([]byte(ts + "." + body))
+	return fmt.Sprintf("t=%s,v1=%s", ts, signature)
+}
+
+func post(t *testing.T, url, body, signature string) int {
+	t.Helper()
+
+	status, _ := postForError(t, url, body, signature)
+	return status
+}
+
+// postForError returns the status and, for error responses, the error code.
+func postForError(t *testing.T, url, body, signature string) (int, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if signature != "" {
+		req.Header.Set("Upwardli-Signature", signature)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var appErr struct {
+		Code string `json:"code"`
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		if err := json.NewDecoder(resp.Body).Decode(&appErr); err != nil {
+			t.Fatalf("failed to decode error response: %v", err)
+		}
+	}
+
+	return resp.StatusCode, appErr.Code
+}
+
+func TestReceiveWebhook(t *testing.T) {
+	body := `{"id":"evt_1","topic":"payment.created"}`
+	oversized := `{"id":"evt_big","padding":"` + strings.Repeat("x", testMaxBodyBytes) + `"}`
+
+	tests := []struct {
+		name      string
+		provider  string
+		body      string
+		signature func(body string) string
+		want      int
+	}{
+		{
+			name:      "valid signature",
+			provider:  "upwardli",
+			body:      body,
+			signature: func(body string) string { return sign(testSecret, time.Now(), body) },
+			want:      http.StatusAccepted,
+		},
+		{
+			name:      "wrong secret",
+			provider:  "upwardli",
+			body:      body,
+			signature: func(body string) string { return sign("whsec_other", time.Now(), body) },
+			want:      http.StatusUnauthorized,
+		},
+		{
+			name:      "missing signature",
+			provider:  "upwardli",
+			body:      body,
+			signature: func(body string) string { return "" },
+			want:      http.StatusUnauthorized,
+		},
+		{
+			name:      "stale timestamp",
+			provider:  "upwardli",
+			body:      body,
+			signature: func(body string) string { return sign(testSecret, time.Now().Add(-time.Hour), body) },
+			want:      http.StatusUnauthorized,
+		},
+		{
+			name:      "body too large",
+			provider:  "upwardli",
+			body:      oversized,
+			signature: func(body string) string { return sign(testSecret, time.Now(), body) },
+			want:      http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:      "unknown provider",
+			provider:  "unknown",
+			body:      body,
+			signature: func(body string) string { return sign(testSecret, time.Now(), body) },
+			want:      http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newTestServer(t)
+
+			got := post(t, server.URL+"/webhooks/"+tt.provider, tt.body, tt.signature(tt.body))
+			if got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReceiveWebhookRejectedBeforeInbox(t *testing.T) {
+	body := `{"id":"evt_1","topic":"payment.created"}`
+	oversized := `{"id":"evt_big","padding":"` + strings.Repeat("x", testMaxBodyBytes) + `"}`
+
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		status    int
+		code      string
+	}{
+		{
+			name:      "body too large",
+			body:      oversized,
+			signature: sign(testSecret, time.Now(), oversized),
+			status:    http.StatusRequestEntityTooLarge,
+			code:      "PAYLOAD_TOO_LARGE",
+		},
+		{
+			name:      "wrong secret",
+			body:      body,
+			signature: sign("whsec_other", time.Now(), body),
+			status:    http.StatusUnauthorized,
+			code:      "INVALID_SIGNATURE",
+		},
+		{
+			name:      "tampered body",
+			body:      `{"id":"evt_2","topic":"payment.created"}`,
+			signature: sign(testSecret, time.Now(), body),
+			status:    http.StatusUnauthorized,
+			code:      "INVALID_SIGNATURE",
+		},
+		{
+			name:      "malformed signature",
+			body:      body,
+			signature: "v1",
+			status:    http.StatusUnauthorized,
+			code:      "INVALID_SIGNATURE_FORMAT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, inbox := newTestServer(t)
+
+			status, code := postForError(t, server.URL+"/webhooks/upwardli", tt.body, tt.signature)
+			if status != tt.status {
+				t.Errorf("status = %d, want %d", status, tt.status)
+			}
+			if code != tt.code {
+				t.Errorf("code = %q, want %q", code, tt.code)
+			}
+			if inbox.calls != 0 {
+				t.Errorf("inbox was called %d times, want 0", inbox.calls)
+			}
+		})
+	}
+}
+
+func TestReceiveWebhookReplay(t *testing.T) {
+	server, _ := newTestServer(t)
+	body := `{"id":"evt_1","topic":"payment.created"}`
+	signature := sign(testSecret, time.Now(), body)
+
+	if got := post(t, server.URL+"/webhooks/upwardli", body, signature); got != http.StatusAccepted {
+		t.Fatalf("first delivery status = %d, want %d", got, http.StatusAccepted)
+	}
+	if got := post(t, server.URL+"/webhooks/upwardli", body, signature); got != http.StatusConflict {
+		t.Errorf("replayed delivery status = %d, want %d", got, http.StatusConflict)
+	}
+}
+
+func TestReceiveWebhookDuplicateEvent(t *testing.T) {
+	server, inbox := newTestServer(t)
+	body := `{"id":"evt_1","topic":"payment.created"}`
+
+	if got := post(t, server.URL+"/webhooks/upwardli", body, sign(testSecret, time.Now(), body)); got != http.StatusAccepted {
+		t.Fatalf("first delivery status = %d, want %d", got, http.StatusAccepted)
+	}
+
+	// A provider retry is signed again, so it passes verification and is
+	// deduplicated by the inbox
+	retry := sign(testSecret, time.Now().Add(time.Second), body)
+	if got := post(t, server.URL+"/webhooks/upwardli", body, retry); got != http.StatusOK {
+		t.Errorf("duplicate delivery status = %d, want %d", got, http.StatusOK)
+	}
+	if len(inbox.received) != 1 {
+		t.Errorf("inbox received %d events, want 1", len(inbox.received))
+	}
+}
+
+func TestReceiveWebhookRetryAfterStoreFailure(t *testing.T) {
+	server, inbox := newTestServer(t)
+	inbox.failures = 1
+	body := `{"id":"evt_1","topic":"payment.created"}`
+	signature := sign(testSecret, time.Now(), body)
+
+	if got := post(t, server.URL+"/webhooks/upwardli", body, signature); got != http.StatusInternalServerError {
+		t.Fatalf("failed delivery status = %d, want %d", got, http.StatusInternalServerError)
+	}
+
+	// The failed delivery's nonce is released, so the same request is not a
+	// replay
+	if got := post(t, server.URL+"/webhooks/upwardli", body, signature); got != http.StatusAccepted {
+		t.Errorf("retried delivery status = %d, want %d", got, http.StatusAccepted)
+	}
+}
+
+func TestReceiveWebhookRoutesByProvider(t *testing.T) {
+	secrets := map[webhooks.Provider]string{
+		webhooks.ProviderUpwardli: testSecret,
+		webhooks.ProviderApril:    "whsec_april",
+	}
+	inboxes := map[webhooks.Provider]*fakeInbox{}
+
+	providers := webhooks.NewProviderRegistry()
+	for provider, secret := range secrets {
+		inboxes[provider] = &fakeInbox{received: map[string]bool{}}
+		err := providers.Register(provider, webhooks.ProviderRegistration{
+			Verifier: webhookprocessors.NewUpwardliVerifier(
+				&logger.NoOpLogger{},
+				[]webhookSDK.Secret{{ID: "default", Value: secret}},
+				webhookSDK.DefaultTolerance,
+				webhookSDK.NewMemoryNonceStore(0),
+			),
+			Processor:    &fakeProcessor{},
+			Client:       &fakeSubscriptionClient{},
+			Inbox:        inboxes[provider],
+			MaxBodyBytes: testMaxBodyBytes,
+		})
+		if err != nil {
+			t.Fatalf("failed to register provider %s: %v", provider, err)
+		}
+	}
+
+	r := chi.NewRouter()
+	httphandlers.AcceptWebhookEndpoints(r, httphandlers.NewWebhookHandler(&logger.NoOpLogger{}, providers))
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	body := `{"id":"evt_1","topic":"payment.created"}`
+
+	// Each provider verifies with its own secrets
+	if got := post(t, server.URL+"/webhooks/april", body, sign(testSecret, time.Now(), body)); got != http.StatusUnauthorized {
+		t.Errorf("april delivery signed for upwardli status = %d, want %d", got, http.StatusUnauthorized)
+	}
+
+	for provider, secret := range secrets {
+		if got := post(t, server.URL+"/webhooks/"+string(provider), body, sign(secret, time.Now(), body)); got != http.StatusAccepted {
+			t.Errorf("%s delivery status = %d, want %d", provider, got, http.StatusAccepted)
+		}
+	}
+
+	// The same event ID is stored once per provider
+	for provider, inbox := range inboxes {
+		if len(inbox.received) != 1 {
+			t.Errorf("%s inbox received %d events, want 1", provider, len(inbox.received))
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	events "template/internal/core/events"
+	"template/internal/logger"
+	"template/packages/cronjob-go"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type eventDeliveryJob struct {
+	logger     logger.Logger
+	delivery   events.Delivery
+	handler    events.DeliveryHandler
+	policy     events.RetryPolicy
+	retryCount int
+}
+
+func (j *eventDeliveryJob) Execute(ctx context.Context) error {
+	return j.handler.Deliver(ctx, j.currentDelivery())
+}
+
+// OnFailure marks the delivery as failed once the subscriber's retries are
+// exhausted.
+func (j *eventDeliveryJob) OnFailure(ctx context.Context, err error) {
+	delivery := j.currentDelivery()
+	delivery.Attempts++
+
+	if failErr := j.handler.Fail(ctx, delivery, err); failErr != nil {
+		j.logger.Error("failed to fail event delivery",
+			zap.Error(failErr),
+			zap.String("deliveryID", delivery.ID))
+	}
+}
+
+// OnDropped defers the delivery to the next sweep when its retry could not
+// be queued.
+func (j *eventDeliveryJob) OnDropped(ctx context.Context, err error) {
+	delivery := j.currentDelivery()
+
+	if deferErr := j.handler.Defer(ctx, delivery, err); deferErr != nil {
+		j.logger.Error("failed to defer event delivery",
+			zap.Error(deferErr),
+			zap.String("deliveryID", delivery.ID))
+	}
+}
+
+// currentDelivery returns the delivery as of the current attempt. The retry
+// count starts at the delivery's stored attempts, so resumed deliveries keep
+// their retry budget and backoff.
+func (j *eventDeliveryJob) currentDelivery() events.Delivery {
+	delivery := j.delivery
+	delivery.Attempts = j.retryCount
+	return delivery
+}
+
+// RetryDelay backs off with the subscriber's delays instead of the
+// scheduler's.
+func (j *eventDeliveryJob) RetryDelay(retryCount int) time.Duration {
+	return cronjob.ExponentialBackoff(j.policy.BaseDelay, j.policy.MaxDelay, retryCount)
+}
+
+func (j *eventDeliveryJob) GetID() string {
+	return fmt.Sprintf("event-delivery-%s", j.delivery.ID)
+}
+
+func (j *eventDeliveryJob) GetRetryCount() int {
+	return j.retryCount
+}
+
+func (j *eventDeliveryJob) IncrementRetry() {
+	j.retryCount++
+}
+
+func (j *eventDeliveryJob) GetMaxRetries() int {
+	return j.policy.MaxRetries
+}
+
+type eventDeliveryDispatcher struct {
+	logger    logger.Logger
+	scheduler cronjob.Scheduler
+}
+
+func NewEventDeliveryDispatcher(logger logger.Logger, scheduler cronjob.Scheduler) events.Dispatcher {
+	return &eventDeliveryDispatcher{
+		logger:    logger,
+		scheduler: scheduler,
+	}
+}
+
+func (d *eventDeliveryDispatcher) Dispatch(ctx context.Context, delivery events.Delivery, policy events.RetryPolicy, handler events.DeliveryHandler) error {
+	return d.scheduler.ScheduleJob(&eventDeliveryJob{
+		logger:     d.logger,
+		delivery:   delivery,
+		handler:    handler,
+		policy:     policy,
+		retryCount: delivery.Attempts,
+	})
+}
@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"context"
+	events "template/internal/core/events"
+	"template/internal/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const eventDeliverySweepTimeout = time.Minute
+
+// EventDeliverySweepJob returns a cron job that re-dispatches the event
+// deliveries the hub could not queue.
+func EventDeliverySweepJob(hub events.Hub) func(logger logger.Logger) {
+	return func(logger logger.Logger) {
+		ctx, cancel := context.WithTimeout(context.Background(), eventDeliverySweepTimeout)
+		defer cancel()
+
+		dispatched, err := hub.Sweep(ctx)
+		if err != nil {
+			logger.Error("failed to sweep deferred event deliveries", zap.Error(err))
+		}
+
+		if dispatched > 0 {
+			logger.Info("dispatched deferred event deliveries", zap.Int("count", dispatched))
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+	transfers "template/internal/core/transfers"
+	"template/internal/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const transferResubmitTimeout = 5 * time.Minute
+
+// TransferResubmitJob returns a cron job that resubmits the initiated
+// transfers Upwardli never acknowledged.
+func TransferResubmitJob(service transfers.Service) func(logger logger.Logger) {
+	return func(logger logger.Logger) {
+		ctx, cancel := context.WithTimeout(context.Background(), transferResubmitTimeout)
+		defer cancel()
+
+		submitted, err := service.ResubmitTransfers(ctx)
+		if err != nil {
+			logger.Error("failed to resubmit transfers", zap.Error(err))
+			return
+		}
+
+		if submitted > 0 {
+			logger.Info("resubmitted transfers", zap.Int("count", submitted))
+		}
+	}
+}
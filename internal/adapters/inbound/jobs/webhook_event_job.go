@@ -0,0 +1,96 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	webhooks "template/internal/core/webhooks"
+	"template/internal/logger"
+	"template/packages/cronjob-go"
+
+	"go.uber.org/zap"
+)
+
+type webhookEventJob struct {
+	logger     logger.Logger
+	event      webhooks.Event
+	handler    webhooks.EventHandler
+	retryCount int
+	maxRetries int
+}
+
+func (j *webhookEventJob) Execute(ctx context.Context) error {
+	return j.handler.Process(ctx, j.currentEvent())
+}
+
+// OnFailure moves the event to the dead-letter store once retries are exhausted.
+func (j *webhookEventJob) OnFailure(ctx context.Context, err error) {
+	event := j.currentEvent()
+	event.Attempts++
+
+	if failErr := j.handler.Fail(ctx, event, err); failErr != nil {
+		j.logger.Error("failed to dead-letter webhook event",
+			zap.Error(failErr),
+			zap.String("eventID", event.ID))
+	}
+}
+
+// OnDropped defers the event to the next sweep when its retry could not be
+// queued.
+func (j *webhookEventJob) OnDropped(ctx context.Context, err error) {
+	event := j.currentEvent()
+
+	if deferErr := j.handler.Defer(ctx, event, err); deferErr != nil {
+		j.logger.Error("failed to defer webhook event",
+			zap.Error(deferErr),
+			zap.String("eventID", event.ID))
+	}
+}
+
+// currentEvent returns the event as of the current attempt. The retry count
+// starts at the event's stored attempts, so resumed events keep their retry
+// budget and backoff.
+func (j *webhookEventJob) currentEvent() webhooks.Event {
+	event := j.event
+	event.Attempts = j.retryCount
+	return event
+}
+
+func (j *webhookEventJob) GetID() string {
+	return fmt.Sprintf("webhook-event-%s-%s", j.event.Provider, j.event.ID)
+}
+
+func (j *webhookEventJob) GetRetryCount() int {
+	return j.retryCount
+}
+
+func (j *webhookEventJob) IncrementRetry() {
+	j.retryCount++
+}
+
+func (j *webhookEventJob) GetMaxRetries() int {
+	return j.maxRetries
+}
+
+type webhookEventDispatcher struct {
+	logger     logger.Logger
+	scheduler  cronjob.Scheduler
+	maxRetries int
+}
+
+func NewWebhookEventDispatcher(logger logger.Logger, scheduler cronjob.Scheduler, maxRetries int) webhooks.Dispatcher {
+	return &webhookEventDispatcher{
+		logger:     logger,
+		scheduler:  scheduler,
+		maxRetries: maxRetries,
+	}
+}
+
+func (d *webhookEventDispatcher) Dispatch(ctx context.Context, event webhooks.Event, handler webhooks.EventHandler) error {
+	return d.scheduler.ScheduleJob(&webhookEventJob{
+		logger:     d.logger,
+		event:      event,
+		handler:    handler,
+		retryCount: event.Attempts,
+		maxRetries: d.maxRetries,
+	})
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+	webhooks "template/internal/core/webhooks"
+	"template/internal/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const webhookEventSweepTimeout = time.Minute
+
+// WebhookEventSweepJob returns a cron job that re-dispatches the webhook
+// events the inbox could not queue.
+func WebhookEventSweepJob(inbox webhooks.Inbox, provider webhooks.Provider) func(logger logger.Logger) {
+	return func(logger logger.Logger) {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookEventSweepTimeout)
+		defer cancel()
+
+		dispatched, err := inbox.Sweep(ctx)
+		if err != nil {
+			logger.Error("failed to sweep deferred webhook events",
+				zap.Error(err),
+				zap.String("provider", string(provider)))
+		}
+
+		if dispatched > 0 {
+			logger.Info("dispatched deferred webhook events",
+				zap.String("provider", string(provider)),
+				zap.Int("count", dispatched))
+		}
+	}
+}
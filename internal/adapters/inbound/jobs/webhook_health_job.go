@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+	webhooks "template/internal/core/webhooks"
+	"template/internal/logger"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const webhookHealthTimeout = time.Minute
+
+// WebhookHealthJob returns a cron job that checks the health of the webhook
+// subscriptions. Unhealthy subscriptions are alerted on by the monitor.
+func WebhookHealthJob(monitor webhooks.HealthMonitor) func(logger logger.Logger) {
+	return func(logger logger.Logger) {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookHealthTimeout)
+		defer cancel()
+
+		_, err := monitor.Check(ctx)
+		if errors.Is(err, webhooks.ErrHealthCheckLocked) {
+			logger.Info("skipped webhook health check, another instance holds the lock")
+			return
+		}
+		if err != nil {
+			logger.Error("failed to check webhook subscription health", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"context"
+	webhooks "template/internal/core/webhooks"
+	"template/internal/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const webhookNonceCleanupTimeout = time.Minute
+
+// WebhookNonceCleanupJob returns a cron job that deletes the provider's
+// expired webhook nonces.
+func WebhookNonceCleanupJob(repo webhooks.NonceRepository, provider webhooks.Provider) func(logger logger.Logger) {
+	return func(logger logger.Logger) {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookNonceCleanupTimeout)
+		defer cancel()
+
+		deleted, err := repo.DeleteExpiredWebhookNonces(ctx, provider)
+		if err != nil {
+			logger.Error("failed to delete expired webhook nonces",
+				zap.Error(err),
+				zap.String("provider", string(provider)))
+			return
+		}
+
+		logger.Info("deleted expired webhook nonces",
+			zap.String("provider", string(provider)),
+			zap.Int64("count", deleted))
+	}
+}
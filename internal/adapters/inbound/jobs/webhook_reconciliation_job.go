@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"context"
+	webhooks "template/internal/core/webhooks"
+	"template/internal/logger"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const webhookReconciliationTimeout = 5 * time.Minute
+
+// WebhookReconciliationJob returns a cron job that reconciles the provider's
+// webhook registrations with the local ones.
+func WebhookReconciliationJob(manager webhooks.WebhookManager, options webhooks.ReconcileOptions) func(logger logger.Logger) {
+	return func(logger logger.Logger) {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookReconciliationTimeout)
+		defer cancel()
+
+		report, err := manager.Reconcile(ctx, options)
+		if errors.Is(err, webhooks.ErrSubscriptionsLocked) {
+			logger.Info("skipped webhook reconciliation, another instance holds the lock")
+			return
+		}
+		if err != nil {
+			logger.Error("failed to reconcile webhook subscriptions", zap.Error(err))
+			return
+		}
+
+		for key, reason := range report.Errors {
+			logger.Warn("failed to reconcile webhook subscription",
+				zap.String("subscription", key),
+				zap.String("reason", reason))
+		}
+	}
+}
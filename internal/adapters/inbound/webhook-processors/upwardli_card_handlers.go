@@ -0,0 +1,103 @@
+package webhookprocessors
+
+import (
+	"context"
+	httpclients "template/internal/adapters/outbound/http-clients"
+	cards "template/internal/core/cards"
+	events "template/internal/core/events"
+	webhooks "template/internal/core/webhooks"
+	"template/internal/logger"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+type upwardliCardHandlers struct {
+	logger    logger.Logger
+	cards     cards.Service
+	publisher events.Publisher
+}
+
+func RegisterUpwardliCardHandlers(
+	registry webhooks.TopicRegistry,
+	l logger.Logger,
+	cardsService cards.Service,
+	publisher events.Publisher,
+) error {
+	h := &upwardliCardHandlers{
+		logger:    l,
+		cards:     cardsService,
+		publisher: publisher,
+	}
+
+	handlers := map[webhooks.SubscriptionTopic]webhooks.TopicHandler{
+		SubscriptionTopicPaymentCardCreated:               webhooks.NewTopicHandler(h.handleCardSaved),
+		SubscriptionTopicPaymentCardUpdated:               webhooks.NewTopicHandler(h.handleCardSaved),
+		SubscriptionTopicPaymentCardClosed:                webhooks.NewTopicHandler(h.handleCardClosed),
+		SubscriptionTopicPaymentCardTransactionSettlement: webhooks.NewTopicHandler(h.handleTransactionSettled),
+	}
+
+	for topic, handler := range handlers {
+		if err := registry.Register(topic, handler); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *upwardliCardHandlers) handleCardSaved(ctx context.Context, event webhooks.Event, dto httpclients.UpwardliPaymentCardDTO) error {
+	update := dto.ToDomain()
+
+	card, err := h.cards.SaveCard(ctx, update)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save card %s", update.ID)
+	}
+
+	if card.Status == cards.CardStatusClosed && update.Status != cards.CardStatusClosed {
+		h.logger.Info("ignoring update of closed upwardli payment card",
+			zap.String("eventID", event.ID),
+			zap.String("topic", string(event.Topic)),
+			zap.String("cardID", card.ID))
+		return nil
+	}
+
+	h.logger.Info("saved upwardli payment card",
+		zap.String("eventID", event.ID),
+		zap.String("topic", string(event.Topic)),
+		zap.String("cardID", card.ID))
+
+	return publishUpwardliEvent(ctx, h.publisher, event, httpclients.CardToEventData(card))
+}
+
+func (h *upwardliCardHandlers) handleCardClosed(ctx context.Context, event webhooks.Event, dto httpclients.UpwardliPaymentCardDTO) error {
+	card := dto.ToDomain()
+
+	if err := h.cards.CloseCard(ctx, card); err != nil {
+		return errors.Wrapf(err, "failed to close card %s", card.ID)
+	}
+
+	h.logger.Info("closed upwardli payment card",
+		zap.String("eventID", event.ID),
+		zap.String("cardID", card.ID))
+
+	data := httpclients.CardToEventData(card)
+	data.Status = string(cards.CardStatusClosed)
+
+	return publishUpwardliEvent(ctx, h.publisher, event, data)
+}
+
+func (h *upwardliCardHandlers) handleTransactionSettled(ctx context.Context, event webhooks.Event, dto httpclients.UpwardliCardTransactionDTO) error {
+	transaction := dto.ToDomain()
+
+	if err := h.cards.RecordSettlement(ctx, transaction); err != nil {
+		return errors.Wrapf(err, "failed to record settlement for transaction %s", transaction.ID)
+	}
+
+	h.logger.Info("recorded upwardli card settlement",
+		zap.String("eventID", event.ID),
+		zap.String("transactionID", transaction.ID),
+		zap.String("cardID", transaction.CardID))
+
+	return publishUpwardliEvent(ctx, h.publisher, event, httpclients.CardTransactionToEventData(transaction))
+}
@@ -0,0 +1,132 @@
+package webhookprocessors
+
+import (
+	"context"
+	httpclients "template/internal/adapters/outbound/http-clients"
+	banking "template/internal/core/banking"
+	events "template/internal/core/events"
+	webhooks "template/internal/core/webhooks"
+	"template/internal/logger"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var upwardliKYCTopicStatuses = map[webhooks.SubscriptionTopic]banking.KYCStatus{
+	SubscriptionTopicConsumerKYCStarted:     banking.KYCStatusStarted,
+	SubscriptionTopicConsumerKYCPending:     banking.KYCStatusPending,
+	SubscriptionTopicConsumerKYCCompleted:   banking.KYCStatusCompleted,
+	SubscriptionTopicConsumerKYCNeedsReview: banking.KYCStatusNeedsReview,
+	SubscriptionTopicConsumerKYCApproved:    banking.KYCStatusApproved,
+	SubscriptionTopicConsumerKYCFailed:      banking.KYCStatusFailed,
+}
+
+type upwardliConsumerHandlers struct {
+	logger    logger.Logger
+	consumers banking.ConsumerManager
+	publisher events.Publisher
+}
+
+func RegisterUpwardliConsumerHandlers(
+	registry webhooks.TopicRegistry,
+	l logger.Logger,
+	consumers banking.ConsumerManager,
+	publisher events.Publisher,
+) error {
+	h := &upwardliConsumerHandlers{
+		logger:    l,
+		consumers: consumers,
+		publisher: publisher,
+	}
+
+	handlers := map[webhooks.SubscriptionTopic]webhooks.TopicHandler{
+		SubscriptionTopicConsumerCreated: webhooks.NewTopicHandler(h.handleConsumerSaved),
+		SubscriptionTopicConsumerUpdated: webhooks.NewTopicHandler(h.handleConsumerSaved),
+		SubscriptionTopicConsumerClosed:  webhooks.NewTopicHandler(h.handleConsumerClosed),
+	}
+
+	for topic := range upwardliKYCTopicStatuses {
+		handlers[topic] = webhooks.NewTopicHandler(h.handleConsumerKYC)
+	}
+
+	for topic, handler := range handlers {
+		if err := registry.Register(topic, handler); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *upwardliConsumerHandlers) handleConsumerSaved(ctx context.Context, event webhooks.Event, dto httpclients.UpwardliConsumerDTO) error {
+	consumer, err := h.consumers.SaveConsumer(ctx, dto.ToDomain())
+	if err != nil {
+		return errors.Wrapf(err, "failed to save consumer %s", dto.ID)
+	}
+
+	if consumer.Deleted {
+		h.logger.Info("ignoring update of closed upwardli consumer",
+			zap.String("eventID", event.ID),
+			zap.String("topic", string(event.Topic)),
+			zap.String("consumerID", consumer.ID))
+		return nil
+	}
+
+	h.logger.Info("saved upwardli consumer",
+		zap.String("eventID", event.ID),
+		zap.String("topic", string(event.Topic)),
+		zap.String("consumerID", consumer.ID))
+
+	return publishUpwardliEvent(ctx, h.publisher, event, httpclients.ConsumerToEventData(consumer))
+}
+
+func (h *upwardliConsumerHandlers) handleConsumerClosed(ctx context.Context, event webhooks.Event, dto httpclients.UpwardliConsumerDTO) error {
+	consumer := dto.ToDomain()
+
+	if err := h.consumers.CloseConsumer(ctx, consumer); err != nil {
+		return errors.Wrapf(err, "failed to close consumer %s", consumer.ID)
+	}
+
+	h.logger.Info("closed upwardli consumer",
+		zap.String("eventID", event.ID),
+		zap.String("consumerID", consumer.ID))
+
+	data := httpclients.ConsumerToEventData(consumer)
+	data.IsActive = false
+
+	return publishUpwardliEvent(ctx, h.publisher, event, data)
+}
+
+func (h *upwardliConsumerHandlers) handleConsumerKYC(ctx context.Context, event webhooks.Event, dto httpclients.UpwardliConsumerDTO) error {
+	status, ok := upwardliKYCTopicStatuses[event.Topic]
+	if !ok {
+		return errors.Errorf("topic %s is not a KYC topic", event.Topic)
+	}
+
+	occurredAt := time.Now()
+	if event.OccurredAt != nil {
+		occurredAt = *event.OccurredAt
+	}
+
+	consumer := dto.ToDomain()
+	transition, err := h.consumers.TransitionKYC(ctx, consumer, status, event.ID, occurredAt)
+	if err != nil {
+		return errors.Wrapf(err, "failed to transition KYC status for consumer %s", dto.ID)
+	}
+
+	h.logger.Info("processed upwardli consumer KYC event",
+		zap.String("eventID", event.ID),
+		zap.String("consumerID", dto.ID),
+		zap.String("from", string(transition.From)),
+		zap.String("to", string(transition.To)),
+		zap.Bool("accepted", transition.Accepted))
+
+	// Out-of-order transitions don't change the consumer, so subscribers
+	// aren't told about them
+	if !transition.Accepted {
+		return nil
+	}
+
+	return publishUpwardliEvent(ctx, h.publisher, event, httpclients.KYCTransitionToEventData(consumer, transition))
+}
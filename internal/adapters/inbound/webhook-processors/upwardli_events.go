@@ -0,0 +1,60 @@
+package webhookprocessors
+
+import (
+	"context"
+	events "template/internal/core/events"
+	webhooks "template/internal/core/webhooks"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// upwardliEventTypes maps the Upwardli topics that are republished to
+// internal subscribers to their domain event type.
+var upwardliEventTypes = map[webhooks.SubscriptionTopic]events.EventType{
+	SubscriptionTopicConsumerCreated:                  events.EventTypeConsumerCreated,
+	SubscriptionTopicConsumerUpdated:                  events.EventTypeConsumerUpdated,
+	SubscriptionTopicConsumerClosed:                   events.EventTypeConsumerClosed,
+	SubscriptionTopicConsumerKYCStarted:               events.EventTypeConsumerKYCUpdated,
+	SubscriptionTopicConsumerKYCPending:               events.EventTypeConsumerKYCUpdated,
+	SubscriptionTopicConsumerKYCCompleted:             events.EventTypeConsumerKYCUpdated,
+	SubscriptionTopicConsumerKYCNeedsReview:           events.EventTypeConsumerKYCUpdated,
+	SubscriptionTopicConsumerKYCApproved:              events.EventTypeConsumerKYCUpdated,
+	SubscriptionTopicConsumerKYCFailed:                events.EventTypeConsumerKYCUpdated,
+	SubscriptionTopicPaymentCardCreated:               events.EventTypeCardCreated,
+	SubscriptionTopicPaymentCardUpdated:               events.EventTypeCardUpdated,
+	SubscriptionTopicPaymentCardClosed:                events.EventTypeCardClosed,
+	SubscriptionTopicPaymentCardTransactionSettlement: events.EventTypeCardTransactionSettled,
+	SubscriptionTopicACHSent:                          events.EventTypeTransferUpdated,
+	SubscriptionTopicACHReceived:                      events.EventTypeTransferUpdated,
+	SubscriptionTopicACHFailed:                        events.EventTypeTransferUpdated,
+	SubscriptionTopicPaymentTransferCreated:           events.EventTypeTransferUpdated,
+	SubscriptionTopicPaymentTransferCompleted:         events.EventTypeTransferUpdated,
+	SubscriptionTopicPaymentTransferFailed:            events.EventTypeTransferUpdated,
+}
+
+// publishUpwardliEvent republishes a processed Upwardli webhook with data as
+// the event payload. A failure fails the webhook so it is retried; the event
+// keeps its ID, so subscribers that already got it aren't sent it again.
+func publishUpwardliEvent(ctx context.Context, publisher events.Publisher, event webhooks.Event, data any) error {
+	eventType, ok := upwardliEventTypes[event.Topic]
+	if !ok {
+		return errors.Errorf("topic %s has no domain event type", event.Topic)
+	}
+
+	occurredAt := time.Now()
+	if event.OccurredAt != nil {
+		occurredAt = *event.OccurredAt
+	}
+
+	published, err := events.NewEvent(eventType, string(webhooks.ProviderUpwardli), event.ID, occurredAt, data)
+	if err != nil {
+		return err
+	}
+
+	if err := publisher.Publish(ctx, published); err != nil {
+		return errors.Wrapf(err, "failed to publish %s event", eventType)
+	}
+
+	return nil
+}
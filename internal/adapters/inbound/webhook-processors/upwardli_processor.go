@@ -3,11 +3,12 @@ package webhookprocessors
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	httpclients "template/internal/adapters/outbound/http-clients"
 	webhooks "template/internal/core/webhooks"
 	"template/internal/logger"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 const (
@@ -32,6 +33,39 @@ const (
 	SubscriptionTopicPaymentTransferFailed            webhooks.SubscriptionTopic = "Payment.Transfer.Failed"
 )
 
+// UpwardliSubscriptionTopics are the topics we subscribe to at Upwardli.
+var UpwardliSubscriptionTopics = []webhooks.SubscriptionTopic{
+	SubscriptionTopicConsumerCreated,
+	SubscriptionTopicConsumerUpdated,
+	SubscriptionTopicConsumerClosed,
+	SubscriptionTopicConsumerKYCStarted,
+	SubscriptionTopicConsumerKYCPending,
+	SubscriptionTopicConsumerKYCCompleted,
+	SubscriptionTopicConsumerKYCNeedsReview,
+	SubscriptionTopicConsumerKYCApproved,
+	SubscriptionTopicConsumerKYCFailed,
+	SubscriptionTopicPaymentCardCreated,
+	SubscriptionTopicPaymentCardUpdated,
+	SubscriptionTopicPaymentCardClosed,
+	SubscriptionTopicPaymentCardTransactionSettlement,
+	SubscriptionTopicACHSent,
+	SubscriptionTopicACHReceived,
+	SubscriptionTopicACHFailed,
+	SubscriptionTopicPaymentTransferCreated,
+	SubscriptionTopicPaymentTransferCompleted,
+	SubscriptionTopicPaymentTransferFailed,
+}
+
+// UpwardliDesiredState is the set of Upwardli subscriptions that should
+// deliver to endpoint.
+func UpwardliDesiredState(endpoint string) webhooks.DesiredState {
+	return webhooks.DesiredState{
+		Provider: webhooks.ProviderUpwardli,
+		Endpoint: endpoint,
+		Topics:   UpwardliSubscriptionTopics,
+	}
+}
+
 type upwardliWebhookEventRequest struct {
 	ID              string                     `json:"id"`
 	CreatedAt       *time.Time                 `json:"created_at"`
@@ -39,40 +73,68 @@ type upwardliWebhookEventRequest struct {
 	PartnerID       string                     `json:"partner_id"`
 	Resources       []string                   `json:"resources"`
 	LastAttemptedAt *time.Time                 `json:"last_attempted_at"`
-	ResourcePath    string                     `json:"-"`
+}
+
+// resourcePath returns the path of the entity the event refers to. An event
+// without one can never be processed, so it is skipped.
+func (r upwardliWebhookEventRequest) resourcePath() (string, error) {
+	if len(r.Resources) == 0 || r.Resources[0] == "" {
+		return "", webhooks.Skip(errors.Errorf("webhook event %s has no resources", r.ID))
+	}
+	return r.Resources[0], nil
 }
 
 type upwardliProcessor struct {
-	client httpclients.UpwardliPartnerClient
+	client   httpclients.UpwardliPartnerClient
+	registry webhooks.TopicRegistry
 }
 
-func NewUpwardliProcessor(l logger.Logger, client httpclients.UpwardliPartnerClient) webhooks.Processor {
+func NewUpwardliProcessor(l logger.Logger, client httpclients.UpwardliPartnerClient, registry webhooks.TopicRegistry) webhooks.Processor {
 	return &upwardliProcessor{
-		client: client,
+		client:   client,
+		registry: registry,
 	}
 }
 
+func (p *upwardliProcessor) ParseEvent(body []byte) (webhooks.Event, error) {
+	var req upwardliWebhookEventRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return webhooks.Event{}, err
+	}
+
+	return webhooks.Event{
+		ID:    req.ID,
+		Topic: req.EventName,
+	}, nil
+}
+
 func (p *upwardliProcessor) Process(ctx context.Context, body []byte, headers map[string]string) error {
 	var req upwardliWebhookEventRequest
 	if err := json.Unmarshal(body, &req); err != nil {
 		return err
 	}
 
-	entityInfo, err := p.client.GetEntityInfo(ctx, req.ResourcePath)
+	handler, err := p.registry.Lookup(req.EventName)
 	if err != nil {
 		return err
 	}
 
-	switch req.EventName {
-	case SubscriptionTopicConsumerCreated:
-		var dto httpclients.UpwardliConsumerDTO
-		if err := json.Unmarshal(entityInfo, &dto); err != nil {
-			return err
-		}
+	resourcePath, err := req.resourcePath()
+	if err != nil {
+		return err
+	}
 
-		consumer := dto.ToDomain()
-		fmt.Println(consumer)
-		return nil
+	entityInfo, err := p.client.GetEntityInfo(ctx, resourcePath)
+	if err != nil {
+		return err
 	}
-	return nil
+
+	return handler.Handle(ctx, webhooks.Event{
+		ID:         req.ID,
+		Topic:      req.EventName,
+		Payload:    body,
+		Headers:    headers,
+		Provider:   webhooks.ProviderUpwardli,
+		OccurredAt: req.CreatedAt,
+	}, entityInfo)
 }
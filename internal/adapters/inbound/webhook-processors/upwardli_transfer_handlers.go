@@ -0,0 +1,93 @@
+package webhookprocessors
+
+import (
+	"context"
+	httpclients "template/internal/adapters/outbound/http-clients"
+	events "template/internal/core/events"
+	transfers "template/internal/core/transfers"
+	webhooks "template/internal/core/webhooks"
+	"template/internal/logger"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+type upwardliTransferTopic struct {
+	transferType transfers.Type
+	status       transfers.Status
+	// direction is used when the payload does not carry one.
+	direction transfers.Direction
+}
+
+var upwardliTransferTopics = map[webhooks.SubscriptionTopic]upwardliTransferTopic{
+	SubscriptionTopicACHSent:                  {transfers.TypeACH, transfers.StatusSent, transfers.DirectionDebit},
+	SubscriptionTopicACHReceived:              {transfers.TypeACH, transfers.StatusCompleted, transfers.DirectionCredit},
+	SubscriptionTopicACHFailed:                {transfers.TypeACH, transfers.StatusFailed, ""},
+	SubscriptionTopicPaymentTransferCreated:   {transfers.TypePayment, transfers.StatusPending, ""},
+	SubscriptionTopicPaymentTransferCompleted: {transfers.TypePayment, transfers.StatusCompleted, ""},
+	SubscriptionTopicPaymentTransferFailed:    {transfers.TypePayment, transfers.StatusFailed, ""},
+}
+
+type upwardliTransferHandlers struct {
+	logger    logger.Logger
+	transfers transfers.Service
+	publisher events.Publisher
+}
+
+func RegisterUpwardliTransferHandlers(
+	registry webhooks.TopicRegistry,
+	l logger.Logger,
+	transfersService transfers.Service,
+	publisher events.Publisher,
+) error {
+	h := &upwardliTransferHandlers{
+		logger:    l,
+		transfers: transfersService,
+		publisher: publisher,
+	}
+
+	for topic := range upwardliTransferTopics {
+		if err := registry.Register(topic, webhooks.NewTopicHandler(h.handleTransfer)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *upwardliTransferHandlers) handleTransfer(ctx context.Context, event webhooks.Event, dto httpclients.UpwardliTransferDTO) error {
+	topic, ok := upwardliTransferTopics[event.Topic]
+	if !ok {
+		return errors.Errorf("topic %s is not a transfer topic", event.Topic)
+	}
+
+	transfer := dto.ToDomain(topic.transferType)
+	if transfer.Direction == "" {
+		transfer.Direction = topic.direction
+	}
+
+	occurredAt := time.Now()
+	if event.OccurredAt != nil {
+		occurredAt = *event.OccurredAt
+	}
+
+	transition, err := h.transfers.TransitionTransfer(ctx, transfer, topic.status, event.ID, occurredAt)
+	if err != nil {
+		return errors.Wrapf(err, "failed to transition transfer %s", dto.ID)
+	}
+
+	h.logger.Info("processed upwardli transfer event",
+		zap.String("eventID", event.ID),
+		zap.String("topic", string(event.Topic)),
+		zap.String("transferID", transition.TransferID),
+		zap.String("from", string(transition.From)),
+		zap.String("to", string(transition.To)),
+		zap.Bool("accepted", transition.Accepted))
+
+	if !transition.Accepted {
+		return nil
+	}
+
+	return publishUpwardliEvent(ctx, h.publisher, event, httpclients.TransferTransitionToEventData(transfer, transition))
+}
@@ -0,0 +1,74 @@
+package webhookprocessors
+
+import (
+	"context"
+	webhooks "template/internal/core/webhooks"
+	"template/internal/logger"
+	webhookSDK "template/packages/webhook-go"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const upwardliSignatureHeader = "Upwardli-Signature"
+
+type upwardliVerifier struct {
+	logger   logger.Logger
+	keyring  *webhookSDK.Keyring
+	verifier *webhookSDK.SignatureVerifier
+}
+
+// NewUpwardliVerifier checks the Upwardli-Signature header, formatted as
+// t=...,v1=..., where v1 is the hex HMAC-SHA256 of the timestamp and body
+// joined by a dot. Any active secret is accepted so the secret can be rotated
+// without rejecting deliveries. Deliveries signed more than tolerance ago, or
+// that were already accepted, are rejected.
+func NewUpwardliVerifier(
+	logger logger.Logger,
+	secrets []webhookSDK.Secret,
+	tolerance time.Duration,
+	nonces webhookSDK.NonceStore,
+) webhooks.Verifier {
+	keyring := webhookSDK.NewSecretKeyring(secrets, func(secret string) webhookSDK.WebhookVerifier {
+		return webhookSDK.NewHMACSHA256Hex(secret)
+	})
+
+	return &upwardliVerifier{
+		logger:  logger,
+		keyring: keyring,
+		verifier: webhookSDK.NewSignatureVerifier(
+			webhookSDK.TimestampedFormat(upwardliSignatureHeader),
+			keyring,
+			webhookSDK.WithTolerance(tolerance),
+			webhookSDK.WithNonceStore(nonces),
+		),
+	}
+}
+
+func (v *upwardliVerifier) Verify(ctx context.Context, body []byte, headers map[string]string) error {
+	active := v.keyring.Active()
+	if len(active) == 0 {
+		return webhooks.ErrInvalidSignature.WithMessage("no active webhook signing secret is configured")
+	}
+
+	verified, err := v.verifier.VerifyContext(ctx, body, webhookSDK.MapHeaders(headers))
+	if err != nil {
+		return webhooks.VerificationError(err)
+	}
+
+	// A match on a key other than the primary means Upwardli still signs with
+	// an older secret, which must stay active until this stops showing up
+	if verified.KeyID != active[0].ID {
+		v.logger.Info("verified upwardli webhook with a non-primary secret",
+			zap.String("keyID", verified.KeyID),
+			zap.String("primaryKeyID", active[0].ID))
+	} else {
+		v.logger.Debug("verified upwardli webhook", zap.String("keyID", verified.KeyID))
+	}
+
+	return nil
+}
+
+func (v *upwardliVerifier) Release(ctx context.Context, body []byte, headers map[string]string) error {
+	return v.verifier.Forget(ctx, body, webhookSDK.MapHeaders(headers))
+}
@@ -0,0 +1,92 @@
+package httpclients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	events "template/internal/core/events"
+	webhookSDK "template/packages/webhook-go"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	EventSignatureHeader = "Event-Signature"
+	EventIDHeader        = "Event-Id"
+	EventTypeHeader      = "Event-Type"
+
+	eventSubscriberTimeout = 10 * time.Second
+	// maxErrorBodyBytes bounds how much of a failed response is kept in the
+	// delivery log.
+	maxErrorBodyBytes = 512
+)
+
+type eventSubscriberClient struct {
+	httpClient *http.Client
+	format     webhookSDK.HeaderFormat
+	signer     webhookSDK.WebhookSigner
+	configured bool
+}
+
+// NewEventSubscriberClient posts events to internal subscribers, signed with
+// secret in the Event-Signature header. The header is formatted as
+// t=...,v1=..., where v1 is the hex HMAC-SHA256 of the timestamp and body
+// joined by a dot, so subscribers can check it with webhook-go. Without a
+// secret nothing is sent.
+func NewEventSubscriberClient(secret string) events.Sender {
+	return &eventSubscriberClient{
+		httpClient: &http.Client{
+			Timeout: eventSubscriberTimeout,
+		},
+		format:     webhookSDK.TimestampedFormat(EventSignatureHeader),
+		signer:     webhookSDK.NewHMACSHA256Hex(secret),
+		configured: secret != "",
+	}
+}
+
+func (c *eventSubscriberClient) Configured() bool {
+	return c.configured
+}
+
+func (c *eventSubscriberClient) Send(ctx context.Context, subscriber events.Subscriber, event events.Event) (int, error) {
+	if !c.configured {
+		return 0, errors.New("INTER_SERVICE_SECRET is not set, events can't be signed")
+	}
+
+	body, err := json.Marshal(EventToDTO(event))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to marshal event")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscriber.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create event request")
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventSignatureHeader, webhookSDK.Sign(c.format, c.signer, timestamp, body, nil))
+	req.Header.Set(EventIDHeader, event.ID)
+	req.Header.Set(EventTypeHeader, string(event.Type))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to post event")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return resp.StatusCode, errors.Errorf("subscriber responded with HTTP %d: %s", resp.StatusCode, respBody)
+	}
+
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,148 @@
+package httpclients
+
+import (
+	"encoding/json"
+	banking "template/internal/core/banking"
+	cards "template/internal/core/cards"
+	events "template/internal/core/events"
+	transfers "template/internal/core/transfers"
+	"time"
+)
+
+// EventDTO is the body posted to internal subscribers. Data holds one of the
+// *EventDataDTO types below, depending on Type.
+type EventDTO struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	Source        string          `json:"source"`
+	SourceEventID string          `json:"sourceEventId"`
+	OccurredAt    time.Time       `json:"occurredAt"`
+	Data          json.RawMessage `json:"data"`
+}
+
+func EventToDTO(event events.Event) EventDTO {
+	return EventDTO{
+		ID:            event.ID,
+		Type:          string(event.Type),
+		Source:        event.Source,
+		SourceEventID: event.SourceEventID,
+		OccurredAt:    event.OccurredAt,
+		Data:          event.Data,
+	}
+}
+
+// ConsumerEventDataDTO is the data of consumer.* events. Personal details
+// are left out; subscribers read them through the API when they need them.
+type ConsumerEventDataDTO struct {
+	ID         string `json:"id"`
+	ExternalID string `json:"externalId"`
+	IsActive   bool   `json:"isActive"`
+	KYCStatus  string `json:"kycStatus"`
+}
+
+func ConsumerToEventData(consumer banking.Consumer) ConsumerEventDataDTO {
+	return ConsumerEventDataDTO{
+		ID:         consumer.ID,
+		ExternalID: consumer.ExternalID,
+		IsActive:   consumer.IsActive,
+		KYCStatus:  string(consumer.KYCStatus),
+	}
+}
+
+type ConsumerKYCEventDataDTO struct {
+	ConsumerID        string `json:"consumerId"`
+	ExternalID        string `json:"externalId"`
+	KYCStatus         string `json:"kycStatus"`
+	PreviousKYCStatus string `json:"previousKycStatus"`
+}
+
+func KYCTransitionToEventData(consumer banking.Consumer, transition banking.KYCTransition) ConsumerKYCEventDataDTO {
+	return ConsumerKYCEventDataDTO{
+		ConsumerID:        consumer.ID,
+		ExternalID:        consumer.ExternalID,
+		KYCStatus:         string(transition.To),
+		PreviousKYCStatus: string(transition.From),
+	}
+}
+
+type CardEventDataDTO struct {
+	ID              string     `json:"id"`
+	ConsumerID      string     `json:"consumerId"`
+	LastFour        string     `json:"lastFour"`
+	Type            string     `json:"type"`
+	Status          string     `json:"status"`
+	ExpirationMonth int        `json:"expirationMonth"`
+	ExpirationYear  int        `json:"expirationYear"`
+	ClosedAt        *time.Time `json:"closedAt,omitempty"`
+}
+
+func CardToEventData(card cards.Card) CardEventDataDTO {
+	return CardEventDataDTO{
+		ID:              card.ID,
+		ConsumerID:      card.ConsumerID,
+		LastFour:        card.LastFour,
+		Type:            string(card.Type),
+		Status:          string(card.Status),
+		ExpirationMonth: card.ExpirationMonth,
+		ExpirationYear:  card.ExpirationYear,
+		ClosedAt:        card.ClosedAt,
+	}
+}
+
+// CardTransactionEventDataDTO amounts are in cents.
+type CardTransactionEventDataDTO struct {
+	ID                   string    `json:"id"`
+	CardID               string    `json:"cardId"`
+	ConsumerID           string    `json:"consumerId"`
+	Amount               int64     `json:"amount"`
+	Currency             string    `json:"currency"`
+	MerchantName         string    `json:"merchantName"`
+	MerchantCategoryCode string    `json:"merchantCategoryCode"`
+	Description          string    `json:"description"`
+	SettledAt            time.Time `json:"settledAt"`
+}
+
+func CardTransactionToEventData(transaction cards.Transaction) CardTransactionEventDataDTO {
+	return CardTransactionEventDataDTO{
+		ID:                   transaction.ID,
+		CardID:               transaction.CardID,
+		ConsumerID:           transaction.ConsumerID,
+		Amount:               transaction.Amount,
+		Currency:             transaction.Currency,
+		MerchantName:         transaction.MerchantName,
+		MerchantCategoryCode: transaction.MerchantCategoryCode,
+		Description:          transaction.Description,
+		SettledAt:            transaction.SettledAt,
+	}
+}
+
+// TransferEventDataDTO amounts are in cents.
+type TransferEventDataDTO struct {
+	ID                 string `json:"id"`
+	ProviderTransferID string `json:"providerTransferId"`
+	ConsumerID         string `json:"consumerId"`
+	Type               string `json:"type"`
+	Direction          string `json:"direction"`
+	Status             string `json:"status"`
+	PreviousStatus     string `json:"previousStatus"`
+	Amount             int64  `json:"amount"`
+	Currency           string `json:"currency"`
+	Description        string `json:"description"`
+	FailureReason      string `json:"failureReason,omitempty"`
+}
+
+func TransferTransitionToEventData(transfer transfers.Transfer, transition transfers.StatusTransition) TransferEventDataDTO {
+	return TransferEventDataDTO{
+		ID:                 transition.TransferID,
+		ProviderTransferID: transfer.ProviderTransferID,
+		ConsumerID:         transfer.ConsumerID,
+		Type:               string(transfer.Type),
+		Direction:          string(transfer.Direction),
+		Status:             string(transition.To),
+		PreviousStatus:     string(transition.From),
+		Amount:             transfer.Amount,
+		Currency:           transfer.Currency,
+		Description:        transfer.Description,
+		FailureReason:      transfer.FailureReason,
+	}
+}
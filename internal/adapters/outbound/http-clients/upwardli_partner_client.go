@@ -2,21 +2,27 @@ package httpclients
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
 	banking "template/internal/core/banking"
+	transfers "template/internal/core/transfers"
 	webhooks "template/internal/core/webhooks"
+	"template/internal/logger"
 	apiClient "template/packages/api-client-go"
-	"template/packages/common-go"
 
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
 )
 
 type UpwardliPartnerClient interface {
 	webhooks.SubscriptionClient
+	transfers.PaymentClient
+	banking.ConsumerClient
 
 	GetEntityInfo(ctx context.Context, path string) ([]byte, error)
 }
@@ -24,6 +30,8 @@ type UpwardliPartnerClient interface {
 type UpwardliPartnerClientConfig struct {
 	banking.Config
 	Scope *string
+	// Logger receives circuit breaker state changes. Optional.
+	Logger logger.Logger
 }
 
 type partnerClient struct {
@@ -109,19 +117,13 @@ func (p *partnerTokenProvider) GetToken(ctx context.Context) (string, error) {
 		Scope:        p.Scope,
 	}
 
-	respBody, err := p.Client.Request(ctx, "/auth/token/",
-		apiClient.WithMethod(apiClient.MethodPost),
-		apiClient.WithBody(requestBody),
+	resp, err := apiClient.Post[partnerTokenRequest, partnerTokenResponse](ctx, p.Client, "/auth/token/", requestBody,
 		apiClient.WithSubURL(p.AuthURL),
 	)
 	if err != nil {
 		return "", fmt.Errorf("requesting partner token: %w", err)
 	}
-
-	var tokenResponse partnerTokenResponse
-	if err := json.Unmarshal(respBody, &tokenResponse); err != nil {
-		return "", fmt.Errorf("parsing partner token response: %w", err)
-	}
+	tokenResponse := resp.Body
 
 	p.token = tokenResponse.AccessToken
 	p.expiresAt = time.Now().Add(time.Duration(tokenResponse.ExpiresIn-60) * time.Second)
@@ -142,7 +144,17 @@ func NewUpwardliPartnerClient(cfg UpwardliPartnerClientConfig) (UpwardliPartnerC
 		return nil, errors.Wrap(err, "failed to initialize partner token provider")
 	}
 
-	newClient, err := apiClient.NewClient(cfg.BaseURL, apiClient.NewTokenAuthenticator(tokenProvider))
+	newClient, err := apiClient.NewClient(
+		cfg.BaseURL,
+		apiClient.NewTokenAuthenticator(tokenProvider),
+		apiClient.WithRetryPolicy(apiClient.DefaultRetryPolicy()),
+		apiClient.WithCircuitBreaker(upwardliBreakerConfig(cfg.Logger)),
+		apiClient.WithRateLimit(apiClient.RateLimit{
+			RequestsPerSecond: cfg.RateLimit,
+			Burst:             cfg.RateBurst,
+		}),
+		apiClient.WithMaxInFlight(cfg.MaxInFlight),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -152,38 +164,77 @@ func NewUpwardliPartnerClient(cfg UpwardliPartnerClientConfig) (UpwardliPartnerC
 	}, nil
 }
 
-func (c *partnerClient) GetAllWebhooks(ctx context.Context) ([]webhooks.Webhook, error) {
-	resp, err := c.client.Request(ctx, "/webhooks/registrations", apiClient.WithMethod(apiClient.MethodGet))
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get webhooks")
+func upwardliBreakerConfig(l logger.Logger) apiClient.BreakerConfig {
+	cfg := apiClient.DefaultBreakerConfig()
+	if l == nil {
+		return cfg
 	}
 
-	var webhooksResp struct {
-		Results []webhooks.Webhook `external:"results"`
+	cfg.OnStateChange = func(host string, from, to apiClient.BreakerState) {
+		fields := []zap.Field{
+			zap.String("host", host),
+			zap.String("from", string(from)),
+			zap.String("to", string(to)),
+		}
+		if to == apiClient.BreakerOpen {
+			l.Error("upwardli circuit breaker opened", fields...)
+			return
+		}
+		l.Info("upwardli circuit breaker state changed", fields...)
 	}
-	if err := common.UnmarshalExternal(resp, &webhooksResp); err != nil {
-		return nil, errors.Wrap(err, "error parsing webhook response")
+
+	return cfg
+}
+
+// upwardliPaginator follows the next links of Upwardli list responses.
+func upwardliPaginator[T any]() apiClient.Paginator[UpwardliResultsDTO[T], T] {
+	return apiClient.NextURLPaginator(
+		func(page UpwardliResultsDTO[T]) []T { return page.Results },
+		func(page UpwardliResultsDTO[T]) string { return page.Next },
+	)
+}
+
+func (c *partnerClient) GetAllWebhooks(ctx context.Context) ([]webhooks.Webhook, error) {
+	ws := []webhooks.Webhook{}
+	for dto, err := range apiClient.Paginate(ctx, c.client, "/webhooks/registrations", upwardliPaginator[UpwardliWebhookDTO]()) {
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get webhooks")
+		}
+		ws = append(ws, dto.ToDomain())
 	}
 
-	webhooks := make([]webhooks.Webhook, len(webhooksResp.Results))
-	copy(webhooks, webhooksResp.Results)
+	return ws, nil
+}
 
-	return webhooks, nil
+func (c *partnerClient) GetWebhook(ctx context.Context, webhookID string) (*webhooks.Webhook, error) {
+	if webhookID == "" {
+		return nil, errors.New("webhook ID is required")
+	}
+
+	resp, err := apiClient.Get[UpwardliWebhookDTO](ctx, c.client, fmt.Sprintf("/webhooks/registrations/%s", webhookID))
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get webhook")
+	}
+
+	webhook := resp.Body.ToDomain()
+	return &webhook, nil
 }
 
 func (c *partnerClient) CreateWebhook(ctx context.Context, endpoint string, topic string) (*webhooks.Webhook, error) {
-	resp, err := c.client.Request(ctx, "/webhooks/registrations",
-		apiClient.WithMethod(apiClient.MethodPost),
-		apiClient.WithBody(map[string]string{"endpoint": endpoint, "webhook_name": topic}))
+	// The key only lives for this call, so it is the attempts below that
+	// can't register the webhook twice
+	resp, err := apiClient.Post[map[string]string, UpwardliWebhookDTO](ctx, c.client, "/webhooks/registrations",
+		map[string]string{"endpoint": endpoint, "webhook_name": topic},
+		apiClient.WithIdempotencyKey(uuid.New().String()))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create webhook")
 	}
 
-	var respBody webhooks.Webhook
-	if err := common.UnmarshalExternal(resp, &respBody); err != nil {
-		return nil, errors.Wrap(err, "error parsing webhook creation response")
-	}
-	return &respBody, nil
+	webhook := resp.Body.ToDomain()
+	return &webhook, nil
 }
 
 func (c *partnerClient) DeleteWebhook(ctx context.Context, webhookID string) error {
@@ -208,3 +259,190 @@ func (c *partnerClient) GetEntityInfo(ctx context.Context, path string) ([]byte,
 	}
 	return resp, nil
 }
+
+func (c *partnerClient) CreateACHTransfer(ctx context.Context, transfer transfers.Transfer) (*transfers.Transfer, error) {
+	return c.createTransfer(ctx, "/payments/ach", transfer)
+}
+
+func (c *partnerClient) CreatePaymentTransfer(ctx context.Context, transfer transfers.Transfer) (*transfers.Transfer, error) {
+	return c.createTransfer(ctx, "/payments/transfers", transfer)
+}
+
+func (c *partnerClient) GetExternalAccount(ctx context.Context, id string) (*transfers.ExternalAccount, error) {
+	if id == "" {
+		return nil, errors.New("external account ID is required")
+	}
+
+	resp, err := apiClient.Get[UpwardliExternalAccountDTO](ctx, c.client, fmt.Sprintf("/payments/external-accounts/%s", id))
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get external account")
+	}
+
+	account := resp.Body.ToDomain()
+	return &account, nil
+}
+
+func (c *partnerClient) createTransfer(ctx context.Context, path string, transfer transfers.Transfer) (*transfers.Transfer, error) {
+	if transfer.ID == "" {
+		return nil, errors.New("transfer ID is required")
+	}
+
+	// Our idempotency keys are only unique per consumer, the transfer ID is
+	// unique at Upwardli too
+	resp, err := apiClient.Post[UpwardliCreateTransferRequestDTO, UpwardliTransferDTO](ctx, c.client, path,
+		UpwardliCreateTransferRequestFromDomain(transfer),
+		apiClient.WithIdempotencyKey(transfer.ID))
+	if isRejected(err) {
+		return nil, errors.Wrap(transfers.ErrTransferRejected, err.Error())
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create transfer")
+	}
+	if resp.Body.ID == "" {
+		return nil, errors.New("transfer creation response has no ID")
+	}
+
+	created := resp.Body.ToDomain(transfer.Type)
+	return &created, nil
+}
+
+func (c *partnerClient) CreateConsumer(ctx context.Context, consumer banking.Consumer) (*banking.Consumer, error) {
+	resp, err := apiClient.Post[UpwardliConsumerDTO, UpwardliConsumerDTO](ctx, c.client, "/consumers",
+		UpwardliConsumerDTOFromDomain(consumer))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create consumer")
+	}
+
+	created := resp.Body.ToDomain()
+	return &created, nil
+}
+
+func (c *partnerClient) GetConsumer(ctx context.Context, id string) (*banking.Consumer, error) {
+	if id == "" {
+		return nil, errors.New("consumer ID is required")
+	}
+
+	resp, err := apiClient.Get[UpwardliConsumerDTO](ctx, c.client, fmt.Sprintf("/consumers/%s", id))
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get consumer")
+	}
+
+	consumer := resp.Body.ToDomain()
+	return &consumer, nil
+}
+
+func (c *partnerClient) GetConsumerByExternalID(ctx context.Context, externalID string) (*banking.Consumer, error) {
+	if externalID == "" {
+		return nil, errors.New("external ID is required")
+	}
+
+	resp, err := apiClient.Get[UpwardliResultsDTO[UpwardliConsumerDTO]](ctx, c.client, "/consumers",
+		apiClient.WithQueryParams(url.Values{"external_id": []string{externalID}}))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get consumer by external ID")
+	}
+
+	if len(resp.Body.Results) == 0 {
+		return nil, nil
+	}
+
+	// A consumer that was closed and recreated is listed twice, prefer the
+	// open one
+	for _, result := range resp.Body.Results {
+		if result.ClosedAt == nil {
+			consumer := result.ToDomain()
+			return &consumer, nil
+		}
+	}
+
+	consumer := resp.Body.Results[0].ToDomain()
+	return &consumer, nil
+}
+
+func (c *partnerClient) UpdateConsumer(ctx context.Context, id string, profile banking.ConsumerProfile) (*banking.Consumer, error) {
+	if id == "" {
+		return nil, errors.New("consumer ID is required")
+	}
+
+	resp, err := apiClient.Do[UpwardliConsumerDTO](ctx, c.client, fmt.Sprintf("/consumers/%s", id),
+		apiClient.WithMethod(apiClient.MethodPatch),
+		apiClient.WithBody(UpwardliConsumerDTOFromProfile(profile)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update consumer")
+	}
+
+	updated := resp.Body.ToDomain()
+	return &updated, nil
+}
+
+func (c *partnerClient) CloseConsumer(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("consumer ID is required")
+	}
+
+	_, err := c.client.Request(ctx, fmt.Sprintf("/consumers/%s", id), apiClient.WithMethod(apiClient.MethodDelete))
+	if err != nil {
+		return errors.Wrap(err, "failed to close consumer")
+	}
+
+	return nil
+}
+
+func (c *partnerClient) CreateEmbeddedSession(
+	ctx context.Context,
+	consumerID string,
+	component banking.EmbeddedComponent,
+	ttl time.Duration,
+) (*banking.EmbeddedSession, error) {
+	if consumerID == "" {
+		return nil, errors.New("consumer ID is required")
+	}
+
+	resp, err := apiClient.Post[UpwardliEmbeddedSessionRequestDTO, UpwardliEmbeddedSessionDTO](ctx, c.client, "/embedded/sessions",
+		UpwardliEmbeddedSessionRequestDTO{
+			ConsumerID: consumerID,
+			Component:  string(component),
+			ExpiresIn:  int(ttl.Seconds()),
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create embedded session")
+	}
+	dto := resp.Body
+	if dto.Token == "" {
+		return nil, errors.New("embedded session response has no token")
+	}
+
+	session := dto.ToDomain()
+	if session.ExpiresAt.IsZero() {
+		session.ExpiresAt = time.Now().Add(ttl)
+	}
+
+	return &session, nil
+}
+
+// isRejected reports whether Upwardli refused a request for good. Timeouts,
+// conflicts and rate limits may succeed when retried.
+func isRejected(err error) bool {
+	var httpErr *apiClient.HTTPError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+
+	switch httpErr.StatusCode {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+		return false
+	}
+
+	return httpErr.StatusCode >= http.StatusBadRequest && httpErr.StatusCode < http.StatusInternalServerError
+}
+
+func isNotFound(err error) bool {
+	var httpErr *apiClient.HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound
+}
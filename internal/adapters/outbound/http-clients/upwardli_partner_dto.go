@@ -1,11 +1,21 @@
 package httpclients
 
 import (
+	"strings"
 	banking "template/internal/core/banking"
+	cards "template/internal/core/cards"
+	transfers "template/internal/core/transfers"
 	webhooks "template/internal/core/webhooks"
 	"time"
 )
 
+// UpwardliResultsDTO is the envelope of Upwardli list responses. Next is
+// the URL of the following page, empty on the last one.
+type UpwardliResultsDTO[T any] struct {
+	Results []T    `json:"results"`
+	Next    string `json:"next"`
+}
+
 type UpwardliWebhookDTO struct {
 	ID          string     `json:"id"`
 	WebhookName string     `json:"webhook_name"`
@@ -28,7 +38,8 @@ func (dto UpwardliWebhookDTO) ToDomain() webhooks.Webhook {
 		Failures:    dto.Failures,
 		LastFailure: dto.LastFailure,
 
-		Provider: webhooks.ProviderUpwardli,
+		Provider:       webhooks.ProviderUpwardli,
+		RegistrationID: dto.RegistrationID,
 	}
 }
 
@@ -51,16 +62,214 @@ type UpwardliConsumerDTO struct {
 	AddressState  string   `json:"address_state,omitempty"`
 	AddressZip    string   `json:"address_zip,omitempty"`
 	CreditLines   []string `json:"credit_lines,omitempty"`
+	// ClosedAt is set once the consumer is closed. Closed consumers are
+	// still listed.
+	ClosedAt *time.Time `json:"closed_at,omitempty"`
 }
 
 func (dto UpwardliConsumerDTO) ToDomain() banking.Consumer {
+	kycStatus := banking.KYCStatus(strings.ToLower(dto.KYCStatus))
+	if !banking.IsValidKYCStatus(kycStatus) {
+		kycStatus = banking.KYCStatusNone
+	}
+
 	return banking.Consumer{
 		ID:            dto.ID,
 		PCID:          dto.PCID,
 		ExternalID:    dto.ExternalID,
 		IsActive:      dto.IsActive,
-		KYCStatus:     dto.KYCStatus,
+		KYCStatus:     kycStatus,
+		Deleted:       dto.ClosedAt != nil,
 		TaxIDType:     dto.TaxIDType,
 		TaxIdentifier: dto.TaxIdentifier,
+		Profile: banking.ConsumerProfile{
+			FirstName:   dto.FirstName,
+			LastName:    dto.LastName,
+			Email:       dto.Email,
+			PhoneNumber: dto.PhoneNumber,
+			DateOfBirth: dto.DateOfBirth,
+			Address: banking.Address{
+				Line1: dto.AddressLine1,
+				Line2: dto.AddressLine2,
+				City:  dto.AddressCity,
+				State: dto.AddressState,
+				Zip:   dto.AddressZip,
+			},
+		},
+	}
+}
+
+func UpwardliConsumerDTOFromDomain(c banking.Consumer) UpwardliConsumerDTO {
+	dto := UpwardliConsumerDTOFromProfile(c.Profile)
+	dto.ID = c.ID
+	dto.ExternalID = c.ExternalID
+	dto.TaxIDType = c.TaxIDType
+	dto.TaxIdentifier = c.TaxIdentifier
+	return dto
+}
+
+// UpwardliConsumerDTOFromProfile leaves empty profile fields out of the
+// payload, so it can be used for partial updates.
+func UpwardliConsumerDTOFromProfile(p banking.ConsumerProfile) UpwardliConsumerDTO {
+	return UpwardliConsumerDTO{
+		FirstName:    p.FirstName,
+		LastName:     p.LastName,
+		Email:        p.Email,
+		PhoneNumber:  p.PhoneNumber,
+		DateOfBirth:  p.DateOfBirth,
+		AddressLine1: p.Address.Line1,
+		AddressLine2: p.Address.Line2,
+		AddressCity:  p.Address.City,
+		AddressState: p.Address.State,
+		AddressZip:   p.Address.Zip,
+	}
+}
+
+type UpwardliEmbeddedSessionRequestDTO struct {
+	ConsumerID string `json:"consumer_id"`
+	Component  string `json:"component"`
+	ExpiresIn  int    `json:"expires_in"`
+}
+
+type UpwardliEmbeddedSessionDTO struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (dto UpwardliEmbeddedSessionDTO) ToDomain() banking.EmbeddedSession {
+	return banking.EmbeddedSession{
+		Token:     dto.Token,
+		ExpiresAt: dto.ExpiresAt,
+	}
+}
+
+type UpwardliPaymentCardDTO struct {
+	ID              string     `json:"id"`
+	ConsumerID      string     `json:"consumer_id"`
+	LastFour        string     `json:"last_four"`
+	CardType        string     `json:"card_type"`
+	Status          string     `json:"status"`
+	ExpirationMonth int        `json:"expiration_month"`
+	ExpirationYear  int        `json:"expiration_year"`
+	ClosedAt        *time.Time `json:"closed_at,omitempty"`
+}
+
+func (dto UpwardliPaymentCardDTO) ToDomain() cards.Card {
+	return cards.Card{
+		ID:              dto.ID,
+		ConsumerID:      dto.ConsumerID,
+		LastFour:        dto.LastFour,
+		Type:            cards.CardType(strings.ToLower(dto.CardType)),
+		Status:          cards.CardStatus(strings.ToLower(dto.Status)),
+		ExpirationMonth: dto.ExpirationMonth,
+		ExpirationYear:  dto.ExpirationYear,
+		ClosedAt:        dto.ClosedAt,
+	}
+}
+
+// UpwardliCardTransactionDTO is a settled card transaction. Amount is in cents.
+type UpwardliCardTransactionDTO struct {
+	ID                   string    `json:"id"`
+	PaymentCardID        string    `json:"payment_card_id"`
+	ConsumerID           string    `json:"consumer_id"`
+	Amount               int64     `json:"amount"`
+	Currency             string    `json:"currency"`
+	MerchantName         string    `json:"merchant_name"`
+	MerchantCategoryCode string    `json:"merchant_category_code"`
+	Description          string    `json:"description"`
+	SettledAt            time.Time `json:"settled_at"`
+}
+
+func (dto UpwardliCardTransactionDTO) ToDomain() cards.Transaction {
+	currency := strings.ToUpper(dto.Currency)
+	if currency == "" {
+		currency = "USD"
+	}
+
+	return cards.Transaction{
+		ID:                   dto.ID,
+		CardID:               dto.PaymentCardID,
+		ConsumerID:           dto.ConsumerID,
+		Amount:               dto.Amount,
+		Currency:             currency,
+		MerchantName:         dto.MerchantName,
+		MerchantCategoryCode: dto.MerchantCategoryCode,
+		Description:          dto.Description,
+		SettledAt:            dto.SettledAt,
+	}
+}
+
+// UpwardliTransferDTO is the payload of ACH.* and Payment.Transfer.* events.
+// Amount is in cents.
+type UpwardliTransferDTO struct {
+	ID string `json:"id"`
+	// ExternalID is our transfer ID for transfers we initiated.
+	ExternalID           string `json:"external_id,omitempty"`
+	ConsumerID           string `json:"consumer_id"`
+	Direction            string `json:"direction"`
+	Amount               int64  `json:"amount"`
+	Currency             string `json:"currency"`
+	SourceAccountID      string `json:"source_account_id"`
+	DestinationAccountID string `json:"destination_account_id"`
+	Description          string `json:"description"`
+	FailureReason        string `json:"failure_reason"`
+}
+
+func (dto UpwardliTransferDTO) ToDomain(transferType transfers.Type) transfers.Transfer {
+	currency := strings.ToUpper(dto.Currency)
+	if currency == "" {
+		currency = "USD"
+	}
+
+	return transfers.Transfer{
+		ID:                   dto.ExternalID,
+		ProviderTransferID:   dto.ID,
+		ConsumerID:           dto.ConsumerID,
+		Type:                 transferType,
+		Direction:            transfers.Direction(strings.ToLower(dto.Direction)),
+		Amount:               dto.Amount,
+		Currency:             currency,
+		SourceAccountID:      dto.SourceAccountID,
+		DestinationAccountID: dto.DestinationAccountID,
+		Description:          dto.Description,
+		FailureReason:        dto.FailureReason,
+	}
+}
+
+type UpwardliExternalAccountDTO struct {
+	ID         string `json:"id"`
+	ConsumerID string `json:"consumer_id"`
+	Status     string `json:"status"`
+}
+
+func (dto UpwardliExternalAccountDTO) ToDomain() transfers.ExternalAccount {
+	return transfers.ExternalAccount{
+		ID:         dto.ID,
+		ConsumerID: dto.ConsumerID,
+		Status:     strings.ToLower(dto.Status),
+	}
+}
+
+type UpwardliCreateTransferRequestDTO struct {
+	ExternalID           string `json:"external_id"`
+	ConsumerID           string `json:"consumer_id"`
+	Direction            string `json:"direction"`
+	Amount               int64  `json:"amount"`
+	Currency             string `json:"currency"`
+	SourceAccountID      string `json:"source_account_id"`
+	DestinationAccountID string `json:"destination_account_id"`
+	Description          string `json:"description,omitempty"`
+}
+
+func UpwardliCreateTransferRequestFromDomain(t transfers.Transfer) UpwardliCreateTransferRequestDTO {
+	return UpwardliCreateTransferRequestDTO{
+		ExternalID:           t.ID,
+		ConsumerID:           t.ConsumerID,
+		Direction:            string(t.Direction),
+		Amount:               t.Amount,
+		Currency:             t.Currency,
+		SourceAccountID:      t.SourceAccountID,
+		DestinationAccountID: t.DestinationAccountID,
+		Description:          t.Description,
 	}
 }
@@ -0,0 +1,106 @@
+-- name: CreateEventsDelivery :execrows
+INSERT INTO events.deliveries (
+        id,
+        subscriber_id,
+        event_id,
+        event_type,
+        source,
+        source_event_id,
+        occurred_at,
+        payload,
+        status
+    )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY
+UPDATE id = id;
+-- name: GetEventsDeliveryById :one
+SELECT id,
+    subscriber_id,
+    event_id,
+    event_type,
+    source,
+    source_event_id,
+    occurred_at,
+    payload,
+    status,
+    attempts,
+    last_status_code,
+    last_error,
+    delivered_at,
+    created_at,
+    updated_at
+FROM events.deliveries
+WHERE id = ?;
+-- name: ListEventsDeliveries :many
+SELECT id,
+    subscriber_id,
+    event_id,
+    event_type,
+    source,
+    source_event_id,
+    occurred_at,
+    payload,
+    status,
+    attempts,
+    last_status_code,
+    last_error,
+    delivered_at,
+    created_at,
+    updated_at
+FROM events.deliveries
+WHERE (
+        sqlc.narg('subscriber_id') IS NULL
+        OR subscriber_id = sqlc.narg('subscriber_id')
+    )
+    AND (
+        sqlc.narg('status') IS NULL
+        OR status = sqlc.narg('status')
+    )
+ORDER BY created_at DESC
+LIMIT ?;
+-- name: ListEventsDeliveriesByStatus :many
+SELECT id,
+    subscriber_id,
+    event_id,
+    event_type,
+    source,
+    source_event_id,
+    occurred_at,
+    payload,
+    status,
+    attempts,
+    last_status_code,
+    last_error,
+    delivered_at,
+    created_at,
+    updated_at
+FROM events.deliveries
+WHERE status = ?
+ORDER BY created_at ASC;
+-- name: UpdateEventsDeliveryStatus :exec
+UPDATE events.deliveries
+SET status = ?,
+    attempts = ?,
+    last_status_code = COALESCE(sqlc.narg('last_status_code'), last_status_code),
+    last_error = ?,
+    delivered_at = ?,
+    updated_at = NOW()
+WHERE id = ?;
+-- name: ClaimEventsDelivery :execrows
+UPDATE events.deliveries
+SET status = sqlc.arg('to_status'),
+    updated_at = NOW()
+WHERE id = sqlc.arg('id')
+    AND status = sqlc.arg('from_status');
+-- name: ClaimStaleEventsDelivery :execrows
+UPDATE events.deliveries
+SET updated_at = NOW()
+WHERE id = sqlc.arg('id')
+    AND status = sqlc.arg('status')
+    AND updated_at < sqlc.arg('updated_before');
+-- name: ClaimEventsDeliveryAttempt :execrows
+UPDATE events.deliveries
+SET attempts = attempts + 1,
+    updated_at = NOW()
+WHERE id = sqlc.arg('id')
+    AND status IN ('pending', 'retrying')
+    AND attempts = sqlc.arg('attempts');
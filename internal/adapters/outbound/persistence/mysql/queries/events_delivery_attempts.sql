@@ -0,0 +1,21 @@
+-- name: CreateEventsDeliveryAttempt :exec
+INSERT INTO events.delivery_attempts (
+        delivery_id,
+        attempt,
+        status_code,
+        error_message,
+        duration_ms,
+        attempted_at
+    )
+VALUES (?, ?, ?, ?, ?, ?);
+-- name: ListEventsDeliveryAttempts :many
+SELECT id,
+    delivery_id,
+    attempt,
+    status_code,
+    error_message,
+    duration_ms,
+    attempted_at
+FROM events.delivery_attempts
+WHERE delivery_id = ?
+ORDER BY attempt ASC;
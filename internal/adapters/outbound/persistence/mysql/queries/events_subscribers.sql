@@ -0,0 +1,57 @@
+-- name: CreateEventsSubscriber :exec
+INSERT INTO events.subscribers (
+        id,
+        name,
+        url,
+        event_types,
+        max_retries,
+        base_retry_delay_seconds,
+        max_retry_delay_seconds,
+        active
+    )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+-- name: GetEventsSubscriberById :one
+SELECT id,
+    name,
+    url,
+    event_types,
+    max_retries,
+    base_retry_delay_seconds,
+    max_retry_delay_seconds,
+    active,
+    created_at,
+    updated_at
+FROM events.subscribers
+WHERE id = ?;
+-- name: GetAllEventsSubscribers :many
+SELECT id,
+    name,
+    url,
+    event_types,
+    max_retries,
+    base_retry_delay_seconds,
+    max_retry_delay_seconds,
+    active,
+    created_at,
+    updated_at
+FROM events.subscribers
+ORDER BY created_at ASC;
+-- name: GetActiveEventsSubscribers :many
+SELECT id,
+    name,
+    url,
+    event_types,
+    max_retries,
+    base_retry_delay_seconds,
+    max_retry_delay_seconds,
+    active,
+    created_at,
+    updated_at
+FROM events.subscribers
+WHERE active = TRUE
+ORDER BY created_at ASC;
+-- name: UpdateEventsSubscriberActive :exec
+UPDATE events.subscribers
+SET active = ?,
+    updated_at = NOW()
+WHERE id = ?;
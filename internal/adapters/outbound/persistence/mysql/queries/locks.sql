@@ -0,0 +1,4 @@
+-- name: GetLock :one
+SELECT CAST(GET_LOCK(sqlc.arg('name'), 0) AS SIGNED) AS acquired;
+-- name: ReleaseLock :one
+SELECT CAST(RELEASE_LOCK(sqlc.arg('name')) AS SIGNED) AS released;
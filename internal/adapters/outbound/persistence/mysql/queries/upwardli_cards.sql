@@ -0,0 +1,97 @@
+-- name: SaveUpwardliPaymentCard :exec
+INSERT INTO upwardli.payment_cards (
+        id,
+        consumer_id,
+        last_four,
+        card_type,
+        status,
+        expiration_month,
+        expiration_year,
+        closed_at
+    )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY
+UPDATE last_four =
+VALUES(last_four),
+    card_type =
+VALUES(card_type),
+    status = IF(status = 'closed', status,
+VALUES(status)),
+    expiration_month =
+VALUES(expiration_month),
+    expiration_year =
+VALUES(expiration_year),
+    closed_at = COALESCE(closed_at,
+VALUES(closed_at));
+-- name: GetUpwardliPaymentCardById :one
+SELECT id,
+    consumer_id,
+    last_four,
+    card_type,
+    status,
+    expiration_month,
+    expiration_year,
+    closed_at,
+    created_at,
+    updated_at
+FROM upwardli.payment_cards
+WHERE id = ?;
+-- name: GetUpwardliPaymentCardsByConsumerId :many
+SELECT id,
+    consumer_id,
+    last_four,
+    card_type,
+    status,
+    expiration_month,
+    expiration_year,
+    closed_at,
+    created_at,
+    updated_at
+FROM upwardli.payment_cards
+WHERE consumer_id = ?
+ORDER BY created_at DESC;
+-- name: GetUpwardliPaymentCardsByConsumerExternalId :many
+SELECT p.id,
+    p.consumer_id,
+    p.last_four,
+    p.card_type,
+    p.status,
+    p.expiration_month,
+    p.expiration_year,
+    p.closed_at,
+    p.created_at,
+    p.updated_at
+FROM upwardli.payment_cards p
+    JOIN upwardli.consumers c ON c.id = p.consumer_id
+WHERE c.external_id = ?
+ORDER BY p.created_at DESC;
+-- name: SaveUpwardliCardTransaction :exec
+INSERT INTO upwardli.card_transactions (
+        id,
+        payment_card_id,
+        consumer_id,
+        amount,
+        currency,
+        merchant_name,
+        merchant_category_code,
+        description,
+        settled_at
+    )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY
+UPDATE amount =
+VALUES(amount),
+    settled_at =
+VALUES(settled_at);
+-- name: GetUpwardliCardTransactionsByCardId :many
+SELECT id,
+    payment_card_id,
+    consumer_id,
+    amount,
+    currency,
+    merchant_name,
+    merchant_category_code,
+    description,
+    settled_at,
+    created_at
+FROM upwardli.card_transactions
+WHERE payment_card_id = ?
+ORDER BY settled_at DESC;
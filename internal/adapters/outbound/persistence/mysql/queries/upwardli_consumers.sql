@@ -0,0 +1,64 @@
+-- name: GetUpwardliConsumerById :one
+SELECT id,
+    pcid,
+    external_id,
+    is_active,
+    kyc_status,
+    tax_id_type,
+    tax_identifier,
+    created_at,
+    updated_at,
+    deleted
+FROM upwardli.consumers
+WHERE id = ?;
+-- name: GetUpwardliConsumerByExternalId :one
+SELECT id,
+    pcid,
+    external_id,
+    is_active,
+    kyc_status,
+    tax_id_type,
+    tax_identifier,
+    created_at,
+    updated_at,
+    deleted
+FROM upwardli.consumers
+WHERE external_id = ?
+    AND deleted = FALSE
+ORDER BY created_at DESC
+LIMIT 1;
+-- name: GetUpwardliConsumerKycStatusForUpdate :one
+SELECT kyc_status
+FROM upwardli.consumers
+WHERE id = ? FOR
+UPDATE;
+-- name: UpdateUpwardliConsumerKycStatus :exec
+UPDATE upwardli.consumers
+SET kyc_status = ?,
+    updated_at = NOW()
+WHERE id = ?;
+-- name: CreateUpwardliConsumerKycTransition :exec
+INSERT INTO upwardli.consumer_kyc_transitions (
+        consumer_id,
+        from_status,
+        to_status,
+        source_event_id,
+        accepted,
+        reason,
+        occurred_at
+    )
+VALUES (?, ?, ?, ?, ?, ?, ?);
+-- name: ListUpwardliConsumerKycTransitions :many
+SELECT id,
+    consumer_id,
+    from_status,
+    to_status,
+    source_event_id,
+    accepted,
+    reason,
+    occurred_at,
+    created_at
+FROM upwardli.consumer_kyc_transitions
+WHERE consumer_id = ?
+ORDER BY occurred_at ASC,
+    id ASC;
@@ -0,0 +1,217 @@
+-- name: CreateUpwardliTransfer :execrows
+INSERT INTO upwardli.transfers (
+        id,
+        upwardli_transfer_id,
+        idempotency_key,
+        consumer_id,
+        transfer_type,
+        direction,
+        status,
+        amount,
+        currency,
+        source_account_id,
+        destination_account_id,
+        description,
+        failure_reason,
+        status_updated_at
+    )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY
+UPDATE id = id;
+-- name: ListUpwardliUnsubmittedTransfers :many
+SELECT id,
+    upwardli_transfer_id,
+    idempotency_key,
+    consumer_id,
+    transfer_type,
+    direction,
+    status,
+    amount,
+    currency,
+    source_account_id,
+    destination_account_id,
+    description,
+    failure_reason,
+    status_updated_at,
+    created_at,
+    updated_at
+FROM upwardli.transfers
+WHERE status = 'pending'
+    AND upwardli_transfer_id IS NULL
+    AND idempotency_key IS NOT NULL
+    AND created_at < ?
+ORDER BY created_at ASC
+LIMIT ?;
+-- name: SaveUpwardliTransfer :exec
+INSERT INTO upwardli.transfers (
+        id,
+        upwardli_transfer_id,
+        idempotency_key,
+        consumer_id,
+        transfer_type,
+        direction,
+        status,
+        amount,
+        currency,
+        source_account_id,
+        destination_account_id,
+        description,
+        failure_reason,
+        status_updated_at
+    )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY
+UPDATE upwardli_transfer_id =
+VALUES(upwardli_transfer_id),
+    status =
+VALUES(status),
+    amount =
+VALUES(amount),
+    currency =
+VALUES(currency),
+    source_account_id =
+VALUES(source_account_id),
+    destination_account_id =
+VALUES(destination_account_id),
+    description =
+VALUES(description),
+    failure_reason =
+VALUES(failure_reason),
+    status_updated_at =
+VALUES(status_updated_at);
+-- name: SetUpwardliTransferUpwardliTransferId :exec
+UPDATE upwardli.transfers
+SET upwardli_transfer_id = ?
+WHERE id = ?
+    AND upwardli_transfer_id IS NULL;
+-- name: GetUpwardliTransferById :one
+SELECT id,
+    upwardli_transfer_id,
+    idempotency_key,
+    consumer_id,
+    transfer_type,
+    direction,
+    status,
+    amount,
+    currency,
+    source_account_id,
+    destination_account_id,
+    description,
+    failure_reason,
+    status_updated_at,
+    created_at,
+    updated_at
+FROM upwardli.transfers
+WHERE id = ?;
+-- name: GetUpwardliTransferByUpwardliTransferId :one
+SELECT id,
+    upwardli_transfer_id,
+    idempotency_key,
+    consumer_id,
+    transfer_type,
+    direction,
+    status,
+    amount,
+    currency,
+    source_account_id,
+    destination_account_id,
+    description,
+    failure_reason,
+    status_updated_at,
+    created_at,
+    updated_at
+FROM upwardli.transfers
+WHERE upwardli_transfer_id = ?;
+-- name: GetUpwardliTransferByIdempotencyKey :one
+SELECT t.id,
+    t.upwardli_transfer_id,
+    t.idempotency_key,
+    t.consumer_id,
+    t.transfer_type,
+    t.direction,
+    t.status,
+    t.amount,
+    t.currency,
+    t.source_account_id,
+    t.destination_account_id,
+    t.description,
+    t.failure_reason,
+    t.status_updated_at,
+    t.created_at,
+    t.updated_at
+FROM upwardli.transfers t
+    JOIN upwardli.consumers c ON c.id = t.consumer_id
+WHERE t.consumer_id = sqlc.arg('consumer_id')
+    AND t.idempotency_key = sqlc.arg('idempotency_key')
+    AND c.external_id = sqlc.arg('external_id');
+-- name: GetUpwardliTransferStatusForUpdate :one
+SELECT status
+FROM upwardli.transfers
+WHERE id = sqlc.arg('id')
+    OR upwardli_transfer_id = sqlc.arg('upwardli_transfer_id')
+LIMIT 1 FOR
+UPDATE;
+-- name: GetUpwardliTransfersByConsumerId :many
+SELECT id,
+    upwardli_transfer_id,
+    idempotency_key,
+    consumer_id,
+    transfer_type,
+    direction,
+    status,
+    amount,
+    currency,
+    source_account_id,
+    destination_account_id,
+    description,
+    failure_reason,
+    status_updated_at,
+    created_at,
+    updated_at
+FROM upwardli.transfers
+WHERE consumer_id = ?
+ORDER BY created_at DESC;
+-- name: GetUpwardliTransfersByConsumerExternalId :many
+SELECT t.id,
+    t.upwardli_transfer_id,
+    t.idempotency_key,
+    t.consumer_id,
+    t.transfer_type,
+    t.direction,
+    t.status,
+    t.amount,
+    t.currency,
+    t.source_account_id,
+    t.destination_account_id,
+    t.description,
+    t.failure_reason,
+    t.status_updated_at,
+    t.created_at,
+    t.updated_at
+FROM upwardli.transfers t
+    JOIN upwardli.consumers c ON c.id = t.consumer_id
+WHERE c.external_id = ?
+ORDER BY t.created_at DESC;
+-- name: CreateUpwardliTransferStatusTransition :exec
+INSERT INTO upwardli.transfer_status_transitions (
+        transfer_id,
+        from_status,
+        to_status,
+        source_event_id,
+        accepted,
+        reason,
+        occurred_at
+    )
+VALUES (?, ?, ?, ?, ?, ?, ?);
+-- name: ListUpwardliTransferStatusTransitions :many
+SELECT id,
+    transfer_id,
+    from_status,
+    to_status,
+    source_event_id,
+    accepted,
+    reason,
+    occurred_at,
+    created_at
+FROM upwardli.transfer_status_transitions
+WHERE transfer_id = ?
+ORDER BY occurred_at ASC,
+    id ASC;
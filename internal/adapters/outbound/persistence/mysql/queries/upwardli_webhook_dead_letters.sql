@@ -0,0 +1,64 @@
+-- name: CreateUpwardliWebhookDeadLetter :exec
+INSERT INTO upwardli.webhook_dead_letters (
+        event_id,
+        event_name,
+        payload,
+        headers,
+        attempts,
+        last_error
+    )
+VALUES (?, ?, ?, ?, ?, ?) ON DUPLICATE KEY
+UPDATE attempts =
+VALUES(attempts),
+    last_error =
+VALUES(last_error),
+    failed_at = NOW(),
+    resolved_at = NULL;
+-- name: GetUpwardliWebhookDeadLetterByEventId :one
+SELECT event_id,
+    event_name,
+    payload,
+    headers,
+    attempts,
+    last_error,
+    failed_at,
+    replay_count,
+    last_replayed_at,
+    resolved_at
+FROM upwardli.webhook_dead_letters
+WHERE event_id = ?;
+-- name: ListUnresolvedUpwardliWebhookDeadLetters :many
+SELECT event_id,
+    event_name,
+    payload,
+    headers,
+    attempts,
+    last_error,
+    failed_at,
+    replay_count,
+    last_replayed_at,
+    resolved_at
+FROM upwardli.webhook_dead_letters
+WHERE resolved_at IS NULL
+    AND (
+        sqlc.narg('event_name') IS NULL
+        OR event_name = sqlc.narg('event_name')
+    )
+    AND (
+        sqlc.narg('failed_after') IS NULL
+        OR failed_at >= sqlc.narg('failed_after')
+    )
+    AND (
+        sqlc.narg('failed_before') IS NULL
+        OR failed_at <= sqlc.narg('failed_before')
+    )
+ORDER BY failed_at ASC
+LIMIT ?;
+-- name: UpdateUpwardliWebhookDeadLetterReplay :exec
+UPDATE upwardli.webhook_dead_letters
+SET replay_count = ?,
+    last_error = ?,
+    last_replayed_at = ?,
+    resolved_at = ?,
+    updated_at = NOW()
+WHERE event_id = ?;
@@ -0,0 +1,64 @@
+-- name: CreateUpwardliWebhookEvent :execrows
+INSERT INTO upwardli.webhook_events (
+        id,
+        event_name,
+        payload,
+        headers,
+        status
+    )
+VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY
+UPDATE id = id;
+-- name: GetUpwardliWebhookEventById :one
+SELECT id,
+    event_name,
+    payload,
+    headers,
+    status,
+    attempts,
+    last_error,
+    processed_at,
+    created_at,
+    updated_at
+FROM upwardli.webhook_events
+WHERE id = ?;
+-- name: UpdateUpwardliWebhookEventStatus :exec
+UPDATE upwardli.webhook_events
+SET status = ?,
+    attempts = ?,
+    last_error = ?,
+    processed_at = ?,
+    updated_at = NOW()
+WHERE id = ?;
+-- name: ListUpwardliWebhookEventsByStatus :many
+SELECT id,
+    event_name,
+    payload,
+    headers,
+    status,
+    attempts,
+    last_error,
+    processed_at,
+    created_at,
+    updated_at
+FROM upwardli.webhook_events
+WHERE status = ?
+ORDER BY created_at ASC;
+-- name: ClaimUpwardliWebhookEvent :execrows
+UPDATE upwardli.webhook_events
+SET status = sqlc.arg('to_status'),
+    updated_at = NOW()
+WHERE id = sqlc.arg('id')
+    AND status = sqlc.arg('from_status');
+-- name: ClaimStaleUpwardliWebhookEvent :execrows
+UPDATE upwardli.webhook_events
+SET updated_at = NOW()
+WHERE id = sqlc.arg('id')
+    AND status = sqlc.arg('status')
+    AND updated_at < sqlc.arg('updated_before');
+-- name: ClaimUpwardliWebhookEventAttempt :execrows
+UPDATE upwardli.webhook_events
+SET attempts = attempts + 1,
+    updated_at = NOW()
+WHERE id = sqlc.arg('id')
+    AND status IN ('pending', 'retrying')
+    AND attempts = sqlc.arg('attempts');
@@ -0,0 +1,16 @@
+-- name: CreateWebhookNonce :execrows
+INSERT INTO upwardli.webhook_nonces (provider, nonce, expires_at)
+VALUES (?, ?, ?) ON DUPLICATE KEY
+UPDATE expires_at = IF(
+        expires_at < NOW(),
+        VALUES(expires_at),
+        expires_at
+    );
+-- name: DeleteExpiredWebhookNonces :execrows
+DELETE FROM upwardli.webhook_nonces
+WHERE provider = ?
+    AND expires_at < NOW();
+-- name: DeleteWebhookNonce :exec
+DELETE FROM upwardli.webhook_nonces
+WHERE provider = ?
+    AND nonce = ?;
@@ -1,5 +1,6 @@
--- name: CreateUpwardliWebhook :exec
+-- name: CreateWebhook :exec
 INSERT INTO upwardli.webhooks (
+        provider,
         id,
         webhook_name,
         endpoint,
@@ -8,41 +9,45 @@ INSERT INTO upwardli.webhooks (
         failures,
         last_failure
     )
-VALUES (?, ?, ?, ?, ?, ?, ?);
--- name: GetUpwardliWebhookById :one
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+-- name: GetWebhookById :one
 SELECT id,
     webhook_name,
     endpoint,
     partner_id,
+    created_at,
+    updated_at,
     status,
     failures,
     last_failure,
-    created_at,
-    updated_at,
-    deleted
+    deleted,
+    provider
 FROM upwardli.webhooks
-WHERE id = ?
+WHERE provider = ?
+    AND id = ?
     AND deleted = FALSE;
--- name: GetAllUpwardliWebhooks :many
+-- name: GetWebhooksByProvider :many
 SELECT id,
     webhook_name,
     endpoint,
     partner_id,
+    created_at,
+    updated_at,
     status,
     failures,
     last_failure,
-    created_at,
-    updated_at,
-    deleted
+    deleted,
+    provider
 FROM upwardli.webhooks
-WHERE deleted = FALSE
+WHERE provider = ?
+    AND deleted = FALSE
 ORDER BY created_at DESC;
--- name: SoftDeleteUpwardliWebhook :exec
+-- name: SoftDeleteWebhook :exec
 UPDATE upwardli.webhooks
 SET deleted = TRUE,
-    deleted_at = NOW(),
     updated_at = NOW()
-WHERE id = ?
+WHERE provider = ?
+    AND id = ?
     AND deleted = FALSE;
 -- name: SaveUpwardliConsumer :exec
 INSERT INTO upwardli.consumers (
@@ -51,7 +56,8 @@ INSERT INTO upwardli.consumers (
         external_id,
         is_active,
         kyc_status,
-        tax_id_type
+        tax_id_type,
+        deleted
     )
 VALUES (
         ?,
@@ -59,13 +65,24 @@ VALUES (
         ?,
         ?,
         ?,
+        ?,
         ?
     ) ON DUPLICATE KEY
 UPDATE pcid =
 VALUES(pcid),
-    is_active =
-VALUES(is_active),
-    kyc_status =
-VALUES(kyc_status),
+    is_active = IF(deleted, FALSE,
+VALUES(is_active)),
     tax_id_type =
-VALUES(tax_id_type)
\ No newline at end of file
+VALUES(tax_id_type),
+    deleted = deleted
+    OR
+VALUES(deleted);
+-- name: UpdateWebhookStatus :exec
+UPDATE upwardli.webhooks
+SET status = ?,
+    failures = ?,
+    last_failure = ?,
+    updated_at = NOW()
+WHERE provider = ?
+    AND id = ?
+    AND deleted = FALSE;
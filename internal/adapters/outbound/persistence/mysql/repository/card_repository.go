@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"template/internal/adapters/outbound/persistence/mysql/sqlc"
+	cards "template/internal/core/cards"
+	"template/packages/common-go"
+
+	"github.com/pkg/errors"
+)
+
+func (r *repository) SaveCard(ctx context.Context, card cards.Card) error {
+	return r.queries.SaveUpwardliPaymentCard(ctx, sqlc.SaveUpwardliPaymentCardParams{
+		ID:              card.ID,
+		ConsumerID:      card.ConsumerID,
+		LastFour:        card.LastFour,
+		CardType:        string(card.Type),
+		Status:          string(card.Status),
+		ExpirationMonth: int32(card.ExpirationMonth),
+		ExpirationYear:  int32(card.ExpirationYear),
+		ClosedAt:        sql.NullTime{Time: common.TimePtrToTime(card.ClosedAt), Valid: card.ClosedAt != nil},
+	})
+}
+
+func (r *repository) GetCard(ctx context.Context, id string) (*cards.Card, error) {
+	row, err := r.queries.GetUpwardliPaymentCardById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	card := upwardliPaymentCardToDomain(row)
+	return &card, nil
+}
+
+func (r *repository) GetCardsByConsumer(ctx context.Context, consumerID string) ([]cards.Card, error) {
+	rows, err := r.queries.GetUpwardliPaymentCardsByConsumerId(ctx, consumerID)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := make([]cards.Card, len(rows))
+	for i, row := range rows {
+		cs[i] = upwardliPaymentCardToDomain(row)
+	}
+
+	return cs, nil
+}
+
+func (r *repository) GetCardsByUser(ctx context.Context, userID string) ([]cards.Card, error) {
+	rows, err := r.queries.GetUpwardliPaymentCardsByConsumerExternalId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := make([]cards.Card, len(rows))
+	for i, row := range rows {
+		cs[i] = upwardliPaymentCardToDomain(row)
+	}
+
+	return cs, nil
+}
+
+func (r *repository) SaveCardTransaction(ctx context.Context, transaction cards.Transaction) error {
+	return r.queries.SaveUpwardliCardTransaction(ctx, sqlc.SaveUpwardliCardTransactionParams{
+		ID:                   transaction.ID,
+		PaymentCardID:        transaction.CardID,
+		ConsumerID:           transaction.ConsumerID,
+		Amount:               transaction.Amount,
+		Currency:             transaction.Currency,
+		MerchantName:         transaction.MerchantName,
+		MerchantCategoryCode: transaction.MerchantCategoryCode,
+		Description:          transaction.Description,
+		SettledAt:            transaction.SettledAt,
+	})
+}
+
+func (r *repository) GetCardTransactions(ctx context.Context, cardID string) ([]cards.Transaction, error) {
+	rows, err := r.queries.GetUpwardliCardTransactionsByCardId(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]cards.Transaction, len(rows))
+	for i, row := range rows {
+		transactions[i] = cards.Transaction{
+			ID:                   row.ID,
+			CardID:               row.PaymentCardID,
+			ConsumerID:           row.ConsumerID,
+			Amount:               row.Amount,
+			Currency:             row.Currency,
+			MerchantName:         row.MerchantName,
+			MerchantCategoryCode: row.MerchantCategoryCode,
+			Description:          row.Description,
+			SettledAt:            row.SettledAt,
+			CreatedAt:            row.CreatedAt,
+		}
+	}
+
+	return transactions, nil
+}
+
+func upwardliPaymentCardToDomain(row sqlc.UpwardliPaymentCard) cards.Card {
+	card := cards.Card{
+		ID:              row.ID,
+		ConsumerID:      row.ConsumerID,
+		LastFour:        row.LastFour,
+		Type:            cards.CardType(row.CardType),
+		Status:          cards.CardStatus(row.Status),
+		ExpirationMonth: int(row.ExpirationMonth),
+		ExpirationYear:  int(row.ExpirationYear),
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+	}
+	if row.ClosedAt.Valid {
+		card.ClosedAt = common.TimeToTimePtr(row.ClosedAt.Time)
+	}
+
+	return card
+}
@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"template/internal/adapters/outbound/persistence/mysql/sqlc"
+	events "template/internal/core/events"
+	"template/packages/common-go"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func (r *repository) SaveSubscriber(ctx context.Context, subscriber events.Subscriber) error {
+	eventTypes, err := json.Marshal(subscriber.EventTypes)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal subscriber event types")
+	}
+
+	return r.queries.CreateEventsSubscriber(ctx, sqlc.CreateEventsSubscriberParams{
+		ID:                    subscriber.ID,
+		Name:                  subscriber.Name,
+		URL:                   subscriber.URL,
+		EventTypes:            eventTypes,
+		MaxRetries:            int32(subscriber.MaxRetries),
+		BaseRetryDelaySeconds: int32(subscriber.BaseRetryDelay / time.Second),
+		MaxRetryDelaySeconds:  int32(subscriber.MaxRetryDelay / time.Second),
+		Active:                subscriber.Active,
+	})
+}
+
+func (r *repository) GetSubscriber(ctx context.Context, id string) (*events.Subscriber, error) {
+	row, err := r.queries.GetEventsSubscriberById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	subscriber, err := subscriberToDomain(row)
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscriber, nil
+}
+
+func (r *repository) GetSubscribers(ctx context.Context) ([]events.Subscriber, error) {
+	rows, err := r.queries.GetAllEventsSubscribers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscribersToDomain(rows)
+}
+
+func (r *repository) GetActiveSubscribers(ctx context.Context) ([]events.Subscriber, error) {
+	rows, err := r.queries.GetActiveEventsSubscribers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscribersToDomain(rows)
+}
+
+func (r *repository) SetSubscriberActive(ctx context.Context, id string, active bool) error {
+	return r.queries.UpdateEventsSubscriberActive(ctx, sqlc.UpdateEventsSubscriberActiveParams{
+		Active: active,
+		ID:     id,
+	})
+}
+
+func (r *repository) SaveDelivery(ctx context.Context, delivery events.Delivery) (bool, error) {
+	rows, err := r.queries.CreateEventsDelivery(ctx, sqlc.CreateEventsDeliveryParams{
+		ID:            delivery.ID,
+		SubscriberID:  delivery.SubscriberID,
+		EventID:       delivery.Event.ID,
+		EventType:     string(delivery.Event.Type),
+		Source:        delivery.Event.Source,
+		SourceEventID: delivery.Event.SourceEventID,
+		OccurredAt:    delivery.Event.OccurredAt,
+		Payload:       delivery.Event.Data,
+		Status:        string(delivery.Status),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (r *repository) GetDelivery(ctx context.Context, id string) (*events.Delivery, error) {
+	row, err := r.queries.GetEventsDeliveryById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := deliveryToDomain(row)
+	return &delivery, nil
+}
+
+func (r *repository) GetDeliveries(ctx context.Context, filter events.DeliveryFilter) ([]events.Delivery, error) {
+	rows, err := r.queries.ListEventsDeliveries(ctx, sqlc.ListEventsDeliveriesParams{
+		SubscriberID: sql.NullString{String: filter.SubscriberID, Valid: filter.SubscriberID != ""},
+		Status:       sql.NullString{String: string(filter.Status), Valid: filter.Status != ""},
+		Limit:        int32(filter.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]events.Delivery, len(rows))
+	for i, row := range rows {
+		deliveries[i] = deliveryToDomain(row)
+	}
+
+	return deliveries, nil
+}
+
+func (r *repository) GetDeliveriesByStatus(ctx context.Context, statuses []events.DeliveryStatus) ([]events.Delivery, error) {
+	var deliveries []events.Delivery
+
+	for _, status := range statuses {
+		rows, err := r.queries.ListEventsDeliveriesByStatus(ctx, string(status))
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			deliveries = append(deliveries, deliveryToDomain(row))
+		}
+	}
+
+	return deliveries, nil
+}
+
+// UpdateDeliveryStatus keeps the stored status code when LastStatusCode is 0,
+// so failing a delivery after its last attempt doesn't clear it.
+func (r *repository) UpdateDeliveryStatus(ctx context.Context, delivery events.Delivery) error {
+	return r.queries.UpdateEventsDeliveryStatus(ctx, sqlc.UpdateEventsDeliveryStatusParams{
+		Status:         string(delivery.Status),
+		Attempts:       int32(delivery.Attempts),
+		LastStatusCode: sql.NullInt32{Int32: int32(delivery.LastStatusCode), Valid: delivery.LastStatusCode != 0},
+		LastError:      sql.NullString{String: common.StrPtrToStr(delivery.LastError), Valid: delivery.LastError != nil},
+		DeliveredAt:    sql.NullTime{Time: common.TimePtrToTime(delivery.DeliveredAt), Valid: delivery.DeliveredAt != nil},
+		ID:             delivery.ID,
+	})
+}
+
+func (r *repository) ClaimDelivery(ctx context.Context, id string, from, to events.DeliveryStatus) (bool, error) {
+	rows, err := r.queries.ClaimEventsDelivery(ctx, sqlc.ClaimEventsDeliveryParams{
+		ToStatus:   string(to),
+		ID:         id,
+		FromStatus: string(from),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (r *repository) ClaimStaleDelivery(ctx context.Context, id string, status events.DeliveryStatus, updatedBefore time.Time) (bool, error) {
+	rows, err := r.queries.ClaimStaleEventsDelivery(ctx, sqlc.ClaimStaleEventsDeliveryParams{
+		ID:            id,
+		Status:        string(status),
+		UpdatedBefore: updatedBefore,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (r *repository) ClaimDeliveryAttempt(ctx context.Context, id string, attempts int) (bool, error) {
+	rows, err := r.queries.ClaimEventsDeliveryAttempt(ctx, sqlc.ClaimEventsDeliveryAttemptParams{
+		ID:       id,
+		Attempts: int32(attempts),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (r *repository) SaveDeliveryAttempt(ctx context.Context, attempt events.DeliveryAttempt) error {
+	return r.queries.CreateEventsDeliveryAttempt(ctx, sqlc.CreateEventsDeliveryAttemptParams{
+		DeliveryID:   attempt.DeliveryID,
+		Attempt:      int32(attempt.Attempt),
+		StatusCode:   sql.NullInt32{Int32: int32(attempt.StatusCode), Valid: attempt.StatusCode != 0},
+		ErrorMessage: sql.NullString{String: common.StrPtrToStr(attempt.Error), Valid: attempt.Error != nil},
+		DurationMs:   attempt.Duration.Milliseconds(),
+		AttemptedAt:  attempt.AttemptedAt,
+	})
+}
+
+func (r *repository) GetDeliveryAttempts(ctx context.Context, deliveryID string) ([]events.DeliveryAttempt, error) {
+	rows, err := r.queries.ListEventsDeliveryAttempts(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := make([]events.DeliveryAttempt, len(rows))
+	for i, row := range rows {
+		attempts[i] = events.DeliveryAttempt{
+			DeliveryID:  row.DeliveryID,
+			Attempt:     int(row.Attempt),
+			StatusCode:  int(row.StatusCode.Int32),
+			Duration:    time.Duration(row.DurationMs) * time.Millisecond,
+			AttemptedAt: row.AttemptedAt,
+		}
+		if row.ErrorMessage.Valid {
+			attempts[i].Error = common.StrToStrPtr(row.ErrorMessage.String)
+		}
+	}
+
+	return attempts, nil
+}
+
+func subscribersToDomain(rows []sqlc.EventsSubscriber) ([]events.Subscriber, error) {
+	subscribers := make([]events.Subscriber, len(rows))
+	for i, row := range rows {
+		subscriber, err := subscriberToDomain(row)
+		if err != nil {
+			return nil, err
+		}
+		subscribers[i] = subscriber
+	}
+
+	return subscribers, nil
+}
+
+func subscriberToDomain(row sqlc.EventsSubscriber) (events.Subscriber, error) {
+	var eventTypes []events.EventType
+	if err := json.Unmarshal(row.EventTypes, &eventTypes); err != nil {
+		return events.Subscriber{}, errors.Wrap(err, "failed to unmarshal subscriber event types")
+	}
+
+	return events.Subscriber{
+		ID:             row.ID,
+		Name:           row.Name,
+		URL:            row.URL,
+		EventTypes:     eventTypes,
+		MaxRetries:     int(row.MaxRetries),
+		BaseRetryDelay: time.Duration(row.BaseRetryDelaySeconds) * time.Second,
+		MaxRetryDelay:  time.Duration(row.MaxRetryDelaySeconds) * time.Second,
+		Active:         row.Active,
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}, nil
+}
+
+func deliveryToDomain(row sqlc.EventsDelivery) events.Delivery {
+	delivery := events.Delivery{
+		ID:           row.ID,
+		SubscriberID: row.SubscriberID,
+		Event: events.Event{
+			ID:            row.EventID,
+			Type:          events.EventType(row.EventType),
+			Source:        row.Source,
+			SourceEventID: row.SourceEventID,
+			OccurredAt:    row.OccurredAt,
+			Data:          row.Payload,
+		},
+		Status:         events.DeliveryStatus(row.Status),
+		Attempts:       int(row.Attempts),
+		LastStatusCode: int(row.LastStatusCode.Int32),
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}
+	if row.LastError.Valid {
+		delivery.LastError = common.StrToStrPtr(row.LastError.String)
+	}
+	if row.DeliveredAt.Valid {
+		delivery.DeliveredAt = common.TimeToTimePtr(row.DeliveredAt.Time)
+	}
+
+	return delivery
+}
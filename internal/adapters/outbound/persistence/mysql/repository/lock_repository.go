@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"template/internal/adapters/outbound/persistence/mysql/sqlc"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// TryLock takes a MySQL named lock. Named locks belong to the session, so
+// the lock keeps a connection of its own until it is released.
+func (r *repository) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to get connection")
+	}
+
+	queries := sqlc.New(conn)
+
+	acquired, err := queries.GetLock(ctx, name)
+	if err != nil || acquired != 1 {
+		conn.Close()
+		return nil, false, err
+	}
+
+	unlock := func() {
+		if _, err := queries.ReleaseLock(context.Background(), name); err != nil {
+			r.logger.Error("failed to release lock", zap.Error(err), zap.String("lock", name))
+
+			// Discarding the session releases its locks too
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}
+
+	return unlock, true, nil
+}
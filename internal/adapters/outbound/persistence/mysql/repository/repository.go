@@ -2,6 +2,9 @@ package repository
 
 import (
 	banking "template/internal/core/banking"
+	cards "template/internal/core/cards"
+	events "template/internal/core/events"
+	transfers "template/internal/core/transfers"
 	webhooks "template/internal/core/webhooks"
 	"template/internal/logger"
 
@@ -12,7 +15,13 @@ import (
 
 type Repository interface {
 	webhooks.Repository
+	webhooks.EventRepository
+	webhooks.DeadLetterRepository
+	webhooks.NonceRepository
 	banking.Repository
+	cards.Repository
+	transfers.Repository
+	events.Repository
 }
 
 type repository struct {
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"template/internal/adapters/outbound/persistence/mysql/sqlc"
+	transfers "template/internal/core/transfers"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func (r *repository) GetTransfer(ctx context.Context, id string) (*transfers.Transfer, error) {
+	row, err := r.queries.GetUpwardliTransferById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	transfer := upwardliTransferToDomain(row)
+	return &transfer, nil
+}
+
+func (r *repository) GetTransferByProviderID(ctx context.Context, providerTransferID string) (*transfers.Transfer, error) {
+	row, err := r.queries.GetUpwardliTransferByUpwardliTransferId(ctx, sql.NullString{String: providerTransferID, Valid: true})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	transfer := upwardliTransferToDomain(row)
+	return &transfer, nil
+}
+
+func (r *repository) GetTransferByIdempotencyKey(ctx context.Context, userID, consumerID, idempotencyKey string) (*transfers.Transfer, error) {
+	row, err := r.queries.GetUpwardliTransferByIdempotencyKey(ctx, sqlc.GetUpwardliTransferByIdempotencyKeyParams{
+		ConsumerID:     consumerID,
+		IdempotencyKey: sql.NullString{String: idempotencyKey, Valid: true},
+		ExternalID:     userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	transfer := upwardliTransferToDomain(row)
+	return &transfer, nil
+}
+
+func (r *repository) SetProviderTransferID(ctx context.Context, id string, providerTransferID string) error {
+	return r.queries.SetUpwardliTransferUpwardliTransferId(ctx, sqlc.SetUpwardliTransferUpwardliTransferIdParams{
+		UpwardliTransferID: sql.NullString{String: providerTransferID, Valid: providerTransferID != ""},
+		ID:                 id,
+	})
+}
+
+func (r *repository) GetTransfersByConsumer(ctx context.Context, consumerID string) ([]transfers.Transfer, error) {
+	rows, err := r.queries.GetUpwardliTransfersByConsumerId(ctx, consumerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return upwardliTransfersToDomain(rows), nil
+}
+
+func (r *repository) GetTransfersByUser(ctx context.Context, userID string) ([]transfers.Transfer, error) {
+	rows, err := r.queries.GetUpwardliTransfersByConsumerExternalId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return upwardliTransfersToDomain(rows), nil
+}
+
+func (r *repository) ListUnsubmittedTransfers(ctx context.Context, createdBefore time.Time, limit int) ([]transfers.Transfer, error) {
+	rows, err := r.queries.ListUpwardliUnsubmittedTransfers(ctx, sqlc.ListUpwardliUnsubmittedTransfersParams{
+		CreatedAt: createdBefore,
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return upwardliTransfersToDomain(rows), nil
+}
+
+func (r *repository) CreateTransfer(ctx context.Context, transfer transfers.Transfer, transition transfers.StatusTransition) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	queries := r.queries.WithTx(tx)
+
+	rows, err := queries.CreateUpwardliTransfer(ctx, sqlc.CreateUpwardliTransferParams(upwardliTransferParams(transfer)))
+	if err != nil {
+		return false, err
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	if err := queries.CreateUpwardliTransferStatusTransition(ctx, upwardliTransitionParams(transition)); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *repository) SaveTransferTransition(ctx context.Context, transfer transfers.Transfer, transition transfers.StatusTransition) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	queries := r.queries.WithTx(tx)
+
+	// Lock the transfer so the transition is decided on the status it
+	// replaces, and so a transfer first heard of is only created once
+	current, err := queries.GetUpwardliTransferStatusForUpdate(ctx, sqlc.GetUpwardliTransferStatusForUpdateParams{
+		ID:                 transfer.ID,
+		UpwardliTransferID: sql.NullString{String: transfer.ProviderTransferID, Valid: transfer.ProviderTransferID != ""},
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if transfers.Status(current) != transition.From {
+		return transfers.ErrTransferStatusChanged
+	}
+
+	// The transfer is written first so a transition is never stored for a
+	// transfer that failed to save
+	if transition.Accepted {
+		if err := queries.SaveUpwardliTransfer(ctx, upwardliTransferParams(transfer)); err != nil {
+			return err
+		}
+	}
+
+	if err := queries.CreateUpwardliTransferStatusTransition(ctx, upwardliTransitionParams(transition)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *repository) GetTransferTransitions(ctx context.Context, transferID string) ([]transfers.StatusTransition, error) {
+	rows, err := r.queries.ListUpwardliTransferStatusTransitions(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	transitions := make([]transfers.StatusTransition, len(rows))
+	for i, row := range rows {
+		transitions[i] = transfers.StatusTransition{
+			TransferID:    row.TransferID,
+			From:          transfers.Status(row.FromStatus),
+			To:            transfers.Status(row.ToStatus),
+			SourceEventID: row.SourceEventID,
+			Accepted:      row.Accepted,
+			Reason:        row.Reason,
+			OccurredAt:    row.OccurredAt,
+			CreatedAt:     row.CreatedAt,
+		}
+	}
+
+	return transitions, nil
+}
+
+func upwardliTransitionParams(transition transfers.StatusTransition) sqlc.CreateUpwardliTransferStatusTransitionParams {
+	return sqlc.CreateUpwardliTransferStatusTransitionParams{
+		TransferID:    transition.TransferID,
+		FromStatus:    string(transition.From),
+		ToStatus:      string(transition.To),
+		SourceEventID: transition.SourceEventID,
+		Accepted:      transition.Accepted,
+		Reason:        transition.Reason,
+		OccurredAt:    transition.OccurredAt,
+	}
+}
+
+func upwardliTransferParams(transfer transfers.Transfer) sqlc.SaveUpwardliTransferParams {
+	return sqlc.SaveUpwardliTransferParams{
+		ID:                   transfer.ID,
+		UpwardliTransferID:   sql.NullString{String: transfer.ProviderTransferID, Valid: transfer.ProviderTransferID != ""},
+		IdempotencyKey:       sql.NullString{String: transfer.IdempotencyKey, Valid: transfer.IdempotencyKey != ""},
+		ConsumerID:           transfer.ConsumerID,
+		TransferType:         string(transfer.Type),
+		Direction:            string(transfer.Direction),
+		Status:               string(transfer.Status),
+		Amount:               transfer.Amount,
+		Currency:             transfer.Currency,
+		SourceAccountID:      transfer.SourceAccountID,
+		DestinationAccountID: transfer.DestinationAccountID,
+		Description:          transfer.Description,
+		FailureReason:        transfer.FailureReason,
+		StatusUpdatedAt:      transfer.StatusUpdatedAt,
+	}
+}
+
+func upwardliTransfersToDomain(rows []sqlc.UpwardliTransfer) []transfers.Transfer {
+	ts := make([]transfers.Transfer, len(rows))
+	for i, row := range rows {
+		ts[i] = upwardliTransferToDomain(row)
+	}
+
+	return ts
+}
+
+func upwardliTransferToDomain(row sqlc.UpwardliTransfer) transfers.Transfer {
+	return transfers.Transfer{
+		ID:                   row.ID,
+		ProviderTransferID:   row.UpwardliTransferID.String,
+		IdempotencyKey:       row.IdempotencyKey.String,
+		ConsumerID:           row.ConsumerID,
+		Type:                 transfers.Type(row.TransferType),
+		Direction:            transfers.Direction(row.Direction),
+		Status:               transfers.Status(row.Status),
+		Amount:               row.Amount,
+		Currency:             row.Currency,
+		SourceAccountID:      row.SourceAccountID,
+		DestinationAccountID: row.DestinationAccountID,
+		Description:          row.Description,
+		FailureReason:        row.FailureReason,
+		StatusUpdatedAt:      row.StatusUpdatedAt,
+		CreatedAt:            row.CreatedAt,
+		UpdatedAt:            row.UpdatedAt,
+	}
+}
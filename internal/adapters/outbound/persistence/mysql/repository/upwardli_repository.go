@@ -2,17 +2,141 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"template/internal/adapters/outbound/persistence/mysql/sqlc"
 	banking "template/internal/core/banking"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
 func (r *repository) SaveBankingConsumer(ctx context.Context, consumer banking.Consumer) error {
-	return r.queries.SaveUpwardliConsumer(ctx, sqlc.SaveUpwardliConsumerParams{
+	return r.queries.SaveUpwardliConsumer(ctx, upwardliConsumerParams(consumer))
+}
+
+func (r *repository) GetBankingConsumer(ctx context.Context, id string) (*banking.Consumer, error) {
+	row, err := r.queries.GetUpwardliConsumerById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	consumer := upwardliConsumerToDomain(row)
+	return &consumer, nil
+}
+
+func (r *repository) GetBankingConsumerByExternalID(ctx context.Context, externalID string) (*banking.Consumer, error) {
+	row, err := r.queries.GetUpwardliConsumerByExternalId(ctx, externalID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	consumer := upwardliConsumerToDomain(row)
+	return &consumer, nil
+}
+
+func (r *repository) SaveKYCTransition(ctx context.Context, consumer banking.Consumer, transition banking.KYCTransition) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	queries := r.queries.WithTx(tx)
+
+	// Lock the consumer so the transition is decided on the status it
+	// replaces
+	current, err := queries.GetUpwardliConsumerKycStatusForUpdate(ctx, transition.ConsumerID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if banking.KYCStatus(current) != transition.From {
+		return banking.ErrKYCStatusChanged
+	}
+
+	err = queries.CreateUpwardliConsumerKycTransition(ctx, sqlc.CreateUpwardliConsumerKycTransitionParams{
+		ConsumerID:    transition.ConsumerID,
+		FromStatus:    string(transition.From),
+		ToStatus:      string(transition.To),
+		SourceEventID: transition.SourceEventID,
+		Accepted:      transition.Accepted,
+		Reason:        transition.Reason,
+		OccurredAt:    transition.OccurredAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if transition.Accepted {
+		// Insert the consumer if this is the first we hear of it; the upsert
+		// leaves kyc_status alone for existing rows, so set it explicitly.
+		if err := queries.SaveUpwardliConsumer(ctx, upwardliConsumerParams(consumer)); err != nil {
+			return err
+		}
+
+		err = queries.UpdateUpwardliConsumerKycStatus(ctx, sqlc.UpdateUpwardliConsumerKycStatusParams{
+			KycStatus: string(transition.To),
+			ID:        transition.ConsumerID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *repository) GetKYCTransitions(ctx context.Context, consumerID string) ([]banking.KYCTransition, error) {
+	rows, err := r.queries.ListUpwardliConsumerKycTransitions(ctx, consumerID)
+	if err != nil {
+		return nil, err
+	}
+
+	transitions := make([]banking.KYCTransition, len(rows))
+	for i, row := range rows {
+		transitions[i] = banking.KYCTransition{
+			ConsumerID:    row.ConsumerID,
+			From:          banking.KYCStatus(row.FromStatus),
+			To:            banking.KYCStatus(row.ToStatus),
+			SourceEventID: row.SourceEventID,
+			Accepted:      row.Accepted,
+			Reason:        row.Reason,
+			OccurredAt:    row.OccurredAt,
+			CreatedAt:     row.CreatedAt,
+		}
+	}
+
+	return transitions, nil
+}
+
+func upwardliConsumerToDomain(row sqlc.UpwardliConsumer) banking.Consumer {
+	return banking.Consumer{
+		ID:            row.ID,
+		PCID:          row.Pcid,
+		ExternalID:    row.ExternalID,
+		IsActive:      row.IsActive,
+		KYCStatus:     banking.KYCStatus(row.KycStatus),
+		TaxIDType:     row.TaxIDType,
+		TaxIdentifier: row.TaxIdentifier,
+		CreatedAt:     row.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     row.UpdatedAt.Format(time.RFC3339),
+		Deleted:       row.Deleted,
+	}
+}
+
+func upwardliConsumerParams(consumer banking.Consumer) sqlc.SaveUpwardliConsumerParams {
+	return sqlc.SaveUpwardliConsumerParams{
 		ID:         consumer.ID,
 		Pcid:       consumer.PCID,
 		ExternalID: consumer.ExternalID,
 		IsActive:   consumer.IsActive,
-		KycStatus:  consumer.KYCStatus,
+		KycStatus:  string(consumer.KYCStatus),
 		TaxIDType:  consumer.TaxIDType,
-	})
+		Deleted:    consumer.Deleted,
+	}
 }
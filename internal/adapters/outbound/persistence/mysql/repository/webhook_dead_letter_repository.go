@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"template/internal/adapters/outbound/persistence/mysql/sqlc"
+	webhooks "template/internal/core/webhooks"
+	"template/packages/common-go"
+
+	"github.com/pkg/errors"
+)
+
+func (r *repository) DeadLetterWebhookEvent(ctx context.Context, event webhooks.Event) error {
+	headers, err := json.Marshal(event.Headers)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal webhook event headers")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	queries := r.queries.WithTx(tx)
+
+	lastError := sql.NullString{String: common.StrPtrToStr(event.LastError), Valid: event.LastError != nil}
+
+	switch event.Provider {
+	case webhooks.ProviderUpwardli:
+		err = queries.UpdateUpwardliWebhookEventStatus(ctx, sqlc.UpdateUpwardliWebhookEventStatusParams{
+			Status:      string(webhooks.EventStatusFailed),
+			Attempts:    int32(event.Attempts),
+			LastError:   lastError,
+			ProcessedAt: sql.NullTime{},
+			ID:          event.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = queries.CreateUpwardliWebhookDeadLetter(ctx, sqlc.CreateUpwardliWebhookDeadLetterParams{
+			EventID:   event.ID,
+			EventName: string(event.Topic),
+			Payload:   event.Payload,
+			Headers:   headers,
+			Attempts:  int32(event.Attempts),
+			LastError: common.StrPtrToStr(event.LastError),
+		})
+		if err != nil {
+			return err
+		}
+	default:
+		return errors.Errorf("unsupported webhook provider: %s", event.Provider)
+	}
+
+	return tx.Commit()
+}
+
+func (r *repository) GetDeadLetter(ctx context.Context, provider webhooks.Provider, eventID string) (*webhooks.DeadLetter, error) {
+	switch provider {
+	case webhooks.ProviderUpwardli:
+		row, err := r.queries.GetUpwardliWebhookDeadLetterByEventId(ctx, eventID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		deadLetter, err := deadLetterToDomain(provider, row)
+		if err != nil {
+			return nil, err
+		}
+
+		return &deadLetter, nil
+	default:
+		return nil, errors.Errorf("unsupported webhook provider: %s", provider)
+	}
+}
+
+func (r *repository) ListDeadLetters(ctx context.Context, provider webhooks.Provider, filter webhooks.DeadLetterFilter) ([]webhooks.DeadLetter, error) {
+	deadLetters := []webhooks.DeadLetter{}
+
+	eventName := sql.NullString{String: string(filter.Topic), Valid: filter.Topic != ""}
+	failedAfter := sql.NullTime{Time: common.TimePtrToTime(filter.FailedAfter), Valid: filter.FailedAfter != nil}
+	failedBefore := sql.NullTime{Time: common.TimePtrToTime(filter.FailedBefore), Valid: filter.FailedBefore != nil}
+
+	switch provider {
+	case webhooks.ProviderUpwardli:
+		rows, err := r.queries.ListUnresolvedUpwardliWebhookDeadLetters(ctx, sqlc.ListUnresolvedUpwardliWebhookDeadLettersParams{
+			EventName:    eventName,
+			FailedAfter:  failedAfter,
+			FailedBefore: failedBefore,
+			Limit:        int32(filter.Limit),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			deadLetter, err := deadLetterToDomain(provider, sqlc.GetUpwardliWebhookDeadLetterByEventIdRow(row))
+			if err != nil {
+				return nil, err
+			}
+			deadLetters = append(deadLetters, deadLetter)
+		}
+
+		return deadLetters, nil
+	default:
+		return nil, errors.Errorf("unsupported webhook provider: %s", provider)
+	}
+}
+
+func (r *repository) UpdateDeadLetterReplay(ctx context.Context, deadLetter webhooks.DeadLetter) error {
+	lastReplayedAt := sql.NullTime{Time: common.TimePtrToTime(deadLetter.LastReplayedAt), Valid: deadLetter.LastReplayedAt != nil}
+	resolvedAt := sql.NullTime{Time: common.TimePtrToTime(deadLetter.ResolvedAt), Valid: deadLetter.ResolvedAt != nil}
+
+	switch deadLetter.Event.Provider {
+	case webhooks.ProviderUpwardli:
+		return r.queries.UpdateUpwardliWebhookDeadLetterReplay(ctx, sqlc.UpdateUpwardliWebhookDeadLetterReplayParams{
+			ReplayCount:    int32(deadLetter.ReplayCount),
+			LastError:      common.StrPtrToStr(deadLetter.Event.LastError),
+			LastReplayedAt: lastReplayedAt,
+			ResolvedAt:     resolvedAt,
+			EventID:        deadLetter.Event.ID,
+		})
+	default:
+		return errors.Errorf("unsupported webhook provider: %s", deadLetter.Event.Provider)
+	}
+}
+
+// deadLetterToDomain maps a stored dead letter.
+func deadLetterToDomain(provider webhooks.Provider, row sqlc.GetUpwardliWebhookDeadLetterByEventIdRow) (webhooks.DeadLetter, error) {
+	var headers map[string]string
+	if err := json.Unmarshal(row.Headers, &headers); err != nil {
+		return webhooks.DeadLetter{}, errors.Wrap(err, "failed to unmarshal dead letter headers")
+	}
+
+	lastError := row.LastError
+	deadLetter := webhooks.DeadLetter{
+		Event: webhooks.Event{
+			ID:        row.EventID,
+			Topic:     webhooks.SubscriptionTopic(row.EventName),
+			Payload:   row.Payload,
+			Headers:   headers,
+			Status:    webhooks.EventStatusFailed,
+			Attempts:  int(row.Attempts),
+			LastError: &lastError,
+			Provider:  provider,
+		},
+		FailedAt:    row.FailedAt,
+		ReplayCount: int(row.ReplayCount),
+	}
+	if row.LastReplayedAt.Valid {
+		deadLetter.LastReplayedAt = common.TimeToTimePtr(row.LastReplayedAt.Time)
+	}
+	if row.ResolvedAt.Valid {
+		deadLetter.ResolvedAt = common.TimeToTimePtr(row.ResolvedAt.Time)
+	}
+
+	return deadLetter, nil
+}
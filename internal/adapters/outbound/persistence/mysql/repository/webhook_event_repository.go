@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"template/internal/adapters/outbound/persistence/mysql/sqlc"
+	webhooks "template/internal/core/webhooks"
+	"template/packages/common-go"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func (r *repository) SaveWebhookEvent(ctx context.Context, event webhooks.Event) (bool, error) {
+	headers, err := json.Marshal(event.Headers)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to marshal webhook event headers")
+	}
+
+	switch event.Provider {
+	case webhooks.ProviderUpwardli:
+		rows, err := r.queries.CreateUpwardliWebhookEvent(ctx, sqlc.CreateUpwardliWebhookEventParams{
+			ID:        event.ID,
+			EventName: string(event.Topic),
+			Payload:   event.Payload,
+			Headers:   headers,
+			Status:    string(event.Status),
+		})
+		if err != nil {
+			return false, err
+		}
+		return rows > 0, nil
+	default:
+		return false, errors.Errorf("unsupported webhook provider: %s", event.Provider)
+	}
+}
+
+func (r *repository) GetWebhookEvent(ctx context.Context, provider webhooks.Provider, id string) (*webhooks.Event, error) {
+	switch provider {
+	case webhooks.ProviderUpwardli:
+		row, err := r.queries.GetUpwardliWebhookEventById(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		event, err := webhookEventToDomain(provider, sqlc.UpwardliWebhookEvent(row))
+		if err != nil {
+			return nil, err
+		}
+
+		return &event, nil
+	default:
+		return nil, errors.Errorf("unsupported webhook provider: %s", provider)
+	}
+}
+
+func (r *repository) ListWebhookEventsByStatus(ctx context.Context, provider webhooks.Provider, statuses []webhooks.EventStatus) ([]webhooks.Event, error) {
+	var events []webhooks.Event
+
+	switch provider {
+	case webhooks.ProviderUpwardli:
+		for _, status := range statuses {
+			rows, err := r.queries.ListUpwardliWebhookEventsByStatus(ctx, string(status))
+			if err != nil {
+				return nil, err
+			}
+			for _, row := range rows {
+				event, err := webhookEventToDomain(provider, sqlc.UpwardliWebhookEvent(row))
+				if err != nil {
+					return nil, err
+				}
+				events = append(events, event)
+			}
+		}
+
+		return events, nil
+	default:
+		return nil, errors.Errorf("unsupported webhook provider: %s", provider)
+	}
+}
+
+func (r *repository) UpdateWebhookEventStatus(ctx context.Context, event webhooks.Event) error {
+	lastError := sql.NullString{String: common.StrPtrToStr(event.LastError), Valid: event.LastError != nil}
+	processedAt := sql.NullTime{Time: common.TimePtrToTime(event.ProcessedAt), Valid: event.ProcessedAt != nil}
+
+	switch event.Provider {
+	case webhooks.ProviderUpwardli:
+		return r.queries.UpdateUpwardliWebhookEventStatus(ctx, sqlc.UpdateUpwardliWebhookEventStatusParams{
+			Status:      string(event.Status),
+			Attempts:    int32(event.Attempts),
+			LastError:   lastError,
+			ProcessedAt: processedAt,
+			ID:          event.ID,
+		})
+	default:
+		return errors.Errorf("unsupported webhook provider: %s", event.Provider)
+	}
+}
+
+func (r *repository) ClaimWebhookEvent(ctx context.Context, provider webhooks.Provider, id string, from, to webhooks.EventStatus) (bool, error) {
+	var (
+		rows int64
+		err  error
+	)
+
+	switch provider {
+	case webhooks.ProviderUpwardli:
+		rows, err = r.queries.ClaimUpwardliWebhookEvent(ctx, sqlc.ClaimUpwardliWebhookEventParams{
+			ToStatus:   string(to),
+			ID:         id,
+			FromStatus: string(from),
+		})
+	default:
+		return false, errors.Errorf("unsupported webhook provider: %s", provider)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (r *repository) ClaimStaleWebhookEvent(ctx context.Context, provider webhooks.Provider, id string, status webhooks.EventStatus, updatedBefore time.Time) (bool, error) {
+	var (
+		rows int64
+		err  error
+	)
+
+	switch provider {
+	case webhooks.ProviderUpwardli:
+		rows, err = r.queries.ClaimStaleUpwardliWebhookEvent(ctx, sqlc.ClaimStaleUpwardliWebhookEventParams{
+			ID:            id,
+			Status:        string(status),
+			UpdatedBefore: updatedBefore,
+		})
+	default:
+		return false, errors.Errorf("unsupported webhook provider: %s", provider)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (r *repository) ClaimWebhookEventAttempt(ctx context.Context, provider webhooks.Provider, id string, attempts int) (bool, error) {
+	var (
+		rows int64
+		err  error
+	)
+
+	switch provider {
+	case webhooks.ProviderUpwardli:
+		rows, err = r.queries.ClaimUpwardliWebhookEventAttempt(ctx, sqlc.ClaimUpwardliWebhookEventAttemptParams{
+			ID:       id,
+			Attempts: int32(attempts),
+		})
+	default:
+		return false, errors.Errorf("unsupported webhook provider: %s", provider)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// webhookEventToDomain maps a stored event.
+func webhookEventToDomain(provider webhooks.Provider, row sqlc.UpwardliWebhookEvent) (webhooks.Event, error) {
+	var headers map[string]string
+	if err := json.Unmarshal(row.Headers, &headers); err != nil {
+		return webhooks.Event{}, errors.Wrap(err, "failed to unmarshal webhook event headers")
+	}
+
+	event := webhooks.Event{
+		ID:        row.ID,
+		Topic:     webhooks.SubscriptionTopic(row.EventName),
+		Payload:   row.Payload,
+		Headers:   headers,
+		Status:    webhooks.EventStatus(row.Status),
+		Attempts:  int(row.Attempts),
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+		Provider:  provider,
+	}
+	if row.LastError.Valid {
+		event.LastError = &row.LastError.String
+	}
+	if row.ProcessedAt.Valid {
+		event.ProcessedAt = common.TimeToTimePtr(row.ProcessedAt.Time)
+	}
+
+	return event, nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"template/internal/adapters/outbound/persistence/mysql/sqlc"
+	webhooks "template/internal/core/webhooks"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Nonces of every provider share one table, keyed by provider.
+
+func (r *repository) SaveWebhookNonce(ctx context.Context, provider webhooks.Provider, nonce string, expiresAt time.Time) (bool, error) {
+	if !webhooks.IsValidProvider(provider) {
+		return false, errors.Errorf("unsupported webhook provider: %s", provider)
+	}
+
+	rows, err := r.queries.CreateWebhookNonce(ctx, sqlc.CreateWebhookNonceParams{
+		Provider:  string(provider),
+		Nonce:     nonce,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	// An unexpired duplicate is left unchanged, so no rows are affected
+	return rows > 0, nil
+}
+
+func (r *repository) DeleteWebhookNonce(ctx context.Context, provider webhooks.Provider, nonce string) error {
+	if !webhooks.IsValidProvider(provider) {
+		return errors.Errorf("unsupported webhook provider: %s", provider)
+	}
+
+	return r.queries.DeleteWebhookNonce(ctx, sqlc.DeleteWebhookNonceParams{
+		Provider: string(provider),
+		Nonce:    nonce,
+	})
+}
+
+func (r *repository) DeleteExpiredWebhookNonces(ctx context.Context, provider webhooks.Provider) (int64, error) {
+	if !webhooks.IsValidProvider(provider) {
+		return 0, errors.Errorf("unsupported webhook provider: %s", provider)
+	}
+
+	return r.queries.DeleteExpiredWebhookNonces(ctx, string(provider))
+}
@@ -6,58 +6,88 @@ import (
 	"template/internal/adapters/outbound/persistence/mysql/sqlc"
 	webhooks "template/internal/core/webhooks"
 	"template/packages/common-go"
+
+	"github.com/pkg/errors"
 )
 
-func (r *repository) CreateWebhook(ctx context.Context, webhook webhooks.Webhook) error {
+// Registrations of every provider share one table, keyed by provider.
 
-	switch webhook.Provider {
-	case webhooks.ProviderUpwardli:
-		return r.queries.CreateUpwardliWebhook(ctx, sqlc.CreateUpwardliWebhookParams{
-			ID:          webhook.ID,
-			WebhookName: string(webhook.WebhookName),
-			Endpoint:    webhook.Endpoint,
-			PartnerID:   webhook.PartnerID,
-			Status:      webhook.Status,
-			Failures:    sql.NullInt32{Int32: int32(webhook.Failures), Valid: webhook.Failures != 0},
-			LastFailure: sql.NullTime{Time: common.TimePtrToTime(webhook.LastFailure), Valid: webhook.LastFailure != nil},
-		})
-	default:
-		return nil
+func (r *repository) CreateWebhook(ctx context.Context, webhook webhooks.Webhook) error {
+	if !webhooks.IsValidProvider(webhook.Provider) {
+		return errors.Errorf("unsupported webhook provider: %s", webhook.Provider)
 	}
+
+	return r.queries.CreateWebhook(ctx, sqlc.CreateWebhookParams{
+		Provider:    string(webhook.Provider),
+		ID:          webhook.ID,
+		WebhookName: string(webhook.WebhookName),
+		Endpoint:    webhook.Endpoint,
+		PartnerID:   webhook.PartnerID,
+		Status:      webhook.Status,
+		Failures:    sql.NullInt32{Int32: int32(webhook.Failures), Valid: webhook.Failures != 0},
+		LastFailure: sql.NullTime{Time: common.TimePtrToTime(webhook.LastFailure), Valid: webhook.LastFailure != nil},
+	})
 }
 
 func (r *repository) GetAllWebhooksByProvider(ctx context.Context, provider webhooks.Provider) ([]webhooks.Webhook, error) {
+	if !webhooks.IsValidProvider(provider) {
+		return nil, errors.Errorf("unsupported webhook provider: %s", provider)
+	}
+
+	rows, err := r.queries.GetWebhooksByProvider(ctx, string(provider))
+	if err != nil {
+		return nil, err
+	}
+
 	var ws []webhooks.Webhook
+	for _, row := range rows {
+		ws = append(ws, webhookToDomain(row))
+	}
 
-	switch provider {
-	case webhooks.ProviderUpwardli:
-		rows, err := r.queries.GetAllUpwardliWebhooks(ctx)
-		if err != nil {
-			return nil, err
-		}
-		for _, row := range rows {
-			ws = append(ws, webhooks.Webhook{
-				ID:          row.ID,
-				WebhookName: webhooks.SubscriptionTopic(row.WebhookName),
-				Endpoint:    row.Endpoint,
-				PartnerID:   row.PartnerID,
-				Status:      row.Status,
-				Failures:    int64(row.Failures.Int32),
-				LastFailure: common.TimeToTimePtr(row.LastFailure.Time),
-			})
-		}
-
-		return ws, nil
-	default:
-		return ws, nil
+	return ws, nil
+}
+
+func (r *repository) UpdateWebhookStatus(ctx context.Context, webhook webhooks.Webhook) error {
+	if !webhooks.IsValidProvider(webhook.Provider) {
+		return errors.Errorf("unsupported webhook provider: %s", webhook.Provider)
 	}
+
+	return r.queries.UpdateWebhookStatus(ctx, sqlc.UpdateWebhookStatusParams{
+		Status:      webhook.Status,
+		Failures:    sql.NullInt32{Int32: int32(webhook.Failures), Valid: true},
+		LastFailure: sql.NullTime{Time: common.TimePtrToTime(webhook.LastFailure), Valid: webhook.LastFailure != nil},
+		Provider:    string(webhook.Provider),
+		ID:          webhook.ID,
+	})
 }
 
 func (r *repository) SoftDeleteWebhook(ctx context.Context, provider webhooks.Provider, id string) error {
-	switch provider {
-	case webhooks.ProviderUpwardli:
-		return r.queries.SoftDeleteUpwardliWebhook(ctx, id)
-	default:
-		return nil
+	if !webhooks.IsValidProvider(provider) {
+		return errors.Errorf("unsupported webhook provider: %s", provider)
 	}
+
+	return r.queries.SoftDeleteWebhook(ctx, sqlc.SoftDeleteWebhookParams{
+		Provider: string(provider),
+		ID:       id,
+	})
+}
+
+// webhookToDomain maps a stored registration.
+func webhookToDomain(row sqlc.UpwardliWebhook) webhooks.Webhook {
+	webhook := webhooks.Webhook{
+		ID:          row.ID,
+		WebhookName: webhooks.SubscriptionTopic(row.WebhookName),
+		Endpoint:    row.Endpoint,
+		PartnerID:   row.PartnerID,
+		Status:      row.Status,
+		Failures:    int64(row.Failures.Int32),
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+		Provider:    webhooks.Provider(row.Provider),
+	}
+	if row.LastFailure.Valid {
+		webhook.LastFailure = common.TimeToTimePtr(row.LastFailure.Time)
+	}
+
+	return webhook
 }
@@ -0,0 +1,110 @@
+package repository_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"template/internal/adapters/outbound/persistence/mysql/repository"
+	webhooks "template/internal/core/webhooks"
+	"template/internal/logger"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+)
+
+func newTestRepository(t *testing.T) (repository.Repository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return repository.NewRepository(sqlx.NewDb(db, "mysql"), &logger.NoOpLogger{}), mock
+}
+
+func TestWebhookStorageIsKeyedByProvider(t *testing.T) {
+	for _, provider := range []webhooks.Provider{webhooks.ProviderUpwardli, webhooks.ProviderApril} {
+		t.Run(string(provider), func(t *testing.T) {
+			repo, mock := newTestRepository(t)
+			ctx := context.Background()
+			now := time.Now()
+
+			mock.ExpectExec(regexp.QuoteMeta("INSERT INTO upwardli.webhooks")).
+				WithArgs(string(provider), "wh_1", "payment.created", "https://example.com", "partner_1", webhooks.WebhookStatusActive, sqlmock.AnyArg(), sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery(regexp.QuoteMeta("FROM upwardli.webhooks")).
+				WithArgs(string(provider)).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "webhook_name", "endpoint", "partner_id", "created_at", "updated_at", "status", "failures", "last_failure", "deleted", "provider"}).
+					AddRow("wh_1", "payment.created", "https://example.com", "partner_1", now, now, webhooks.WebhookStatusActive, nil, nil, false, string(provider)))
+			mock.ExpectExec(regexp.QuoteMeta("INSERT INTO upwardli.webhook_nonces")).
+				WithArgs(string(provider), "nonce_1", sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(regexp.QuoteMeta("DELETE FROM upwardli.webhook_nonces")).
+				WithArgs(string(provider), "nonce_1").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := repo.CreateWebhook(ctx, webhooks.Webhook{
+				ID:          "wh_1",
+				WebhookName: "payment.created",
+				Endpoint:    "https://example.com",
+				PartnerID:   "partner_1",
+				Status:      webhooks.WebhookStatusActive,
+				Provider:    provider,
+			})
+			if err != nil {
+				t.Fatalf("CreateWebhook() error = %v", err)
+			}
+
+			stored, err := repo.GetAllWebhooksByProvider(ctx, provider)
+			if err != nil {
+				t.Fatalf("GetAllWebhooksByProvider() error = %v", err)
+			}
+			if len(stored) != 1 || stored[0].Provider != provider {
+				t.Errorf("stored = %+v, want one %s webhook", stored, provider)
+			}
+
+			nonces := webhooks.NewNonceStore(repo, provider)
+			saved, err := nonces.Remember(ctx, "nonce_1", now.Add(time.Minute))
+			if err != nil {
+				t.Fatalf("Remember() error = %v", err)
+			}
+			if !saved {
+				t.Errorf("saved = false, want true")
+			}
+			if err := nonces.Forget(ctx, "nonce_1"); err != nil {
+				t.Fatalf("Forget() error = %v", err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestWebhookStorageRejectsUnknownProviders(t *testing.T) {
+	repo, mock := newTestRepository(t)
+	ctx := context.Background()
+	provider := webhooks.Provider("unknown")
+
+	if err := repo.CreateWebhook(ctx, webhooks.Webhook{ID: "wh_1", Provider: provider}); err == nil {
+		t.Error("CreateWebhook() error = nil, want an error")
+	}
+	if _, err := repo.GetAllWebhooksByProvider(ctx, provider); err == nil {
+		t.Error("GetAllWebhooksByProvider() error = nil, want an error")
+	}
+	if err := repo.SoftDeleteWebhook(ctx, provider, "wh_1"); err == nil {
+		t.Error("SoftDeleteWebhook() error = nil, want an error")
+	}
+	if _, err := repo.SaveWebhookNonce(ctx, provider, "nonce_1", time.Now()); err == nil {
+		t.Error("SaveWebhookNonce() error = nil, want an error")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
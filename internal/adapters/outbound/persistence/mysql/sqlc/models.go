@@ -6,9 +6,64 @@ package sqlc
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
+type EventsDelivery struct {
+	ID             string          `db:"id" json:"id"`
+	SubscriberID   string          `db:"subscriber_id" json:"subscriberId"`
+	EventID        string          `db:"event_id" json:"eventId"`
+	EventType      string          `db:"event_type" json:"eventType"`
+	Source         string          `db:"source" json:"source"`
+	SourceEventID  string          `db:"source_event_id" json:"sourceEventId"`
+	OccurredAt     time.Time       `db:"occurred_at" json:"occurredAt"`
+	Payload        json.RawMessage `db:"payload" json:"payload"`
+	Status         string          `db:"status" json:"status"`
+	Attempts       int32           `db:"attempts" json:"attempts"`
+	LastStatusCode sql.NullInt32   `db:"last_status_code" json:"lastStatusCode"`
+	LastError      sql.NullString  `db:"last_error" json:"lastError"`
+	DeliveredAt    sql.NullTime    `db:"delivered_at" json:"deliveredAt"`
+	CreatedAt      time.Time       `db:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time       `db:"updated_at" json:"updatedAt"`
+}
+
+type EventsDeliveryAttempt struct {
+	ID           int64          `db:"id" json:"id"`
+	DeliveryID   string         `db:"delivery_id" json:"deliveryId"`
+	Attempt      int32          `db:"attempt" json:"attempt"`
+	StatusCode   sql.NullInt32  `db:"status_code" json:"statusCode"`
+	ErrorMessage sql.NullString `db:"error_message" json:"errorMessage"`
+	DurationMs   int64          `db:"duration_ms" json:"durationMs"`
+	AttemptedAt  time.Time      `db:"attempted_at" json:"attemptedAt"`
+}
+
+type EventsSubscriber struct {
+	ID                    string          `db:"id" json:"id"`
+	Name                  string          `db:"name" json:"name"`
+	URL                   string          `db:"url" json:"url"`
+	EventTypes            json.RawMessage `db:"event_types" json:"eventTypes"`
+	MaxRetries            int32           `db:"max_retries" json:"maxRetries"`
+	BaseRetryDelaySeconds int32           `db:"base_retry_delay_seconds" json:"baseRetryDelaySeconds"`
+	MaxRetryDelaySeconds  int32           `db:"max_retry_delay_seconds" json:"maxRetryDelaySeconds"`
+	Active                bool            `db:"active" json:"active"`
+	CreatedAt             time.Time       `db:"created_at" json:"createdAt"`
+	UpdatedAt             time.Time       `db:"updated_at" json:"updatedAt"`
+}
+
+type UpwardliCardTransaction struct {
+	ID                   string    `db:"id" json:"id"`
+	PaymentCardID        string    `db:"payment_card_id" json:"paymentCardId"`
+	ConsumerID           string    `db:"consumer_id" json:"consumerId"`
+	Amount               int64     `db:"amount" json:"amount"`
+	Currency             string    `db:"currency" json:"currency"`
+	MerchantName         string    `db:"merchant_name" json:"merchantName"`
+	MerchantCategoryCode string    `db:"merchant_category_code" json:"merchantCategoryCode"`
+	Description          string    `db:"description" json:"description"`
+	SettledAt            time.Time `db:"settled_at" json:"settledAt"`
+	CreatedAt            time.Time `db:"created_at" json:"createdAt"`
+}
+
 type UpwardliConsumer struct {
 	ID            string    `db:"id" json:"id"`
 	Pcid          string    `db:"pcid" json:"pcid"`
@@ -22,6 +77,62 @@ type UpwardliConsumer struct {
 	Deleted       bool      `db:"deleted" json:"deleted"`
 }
 
+type UpwardliConsumerKycTransition struct {
+	ID            int64     `db:"id" json:"id"`
+	ConsumerID    string    `db:"consumer_id" json:"consumerId"`
+	FromStatus    string    `db:"from_status" json:"fromStatus"`
+	ToStatus      string    `db:"to_status" json:"toStatus"`
+	SourceEventID string    `db:"source_event_id" json:"sourceEventId"`
+	Accepted      bool      `db:"accepted" json:"accepted"`
+	Reason        string    `db:"reason" json:"reason"`
+	OccurredAt    time.Time `db:"occurred_at" json:"occurredAt"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
+}
+
+type UpwardliPaymentCard struct {
+	ID              string       `db:"id" json:"id"`
+	ConsumerID      string       `db:"consumer_id" json:"consumerId"`
+	LastFour        string       `db:"last_four" json:"lastFour"`
+	CardType        string       `db:"card_type" json:"cardType"`
+	Status          string       `db:"status" json:"status"`
+	ExpirationMonth int32        `db:"expiration_month" json:"expirationMonth"`
+	ExpirationYear  int32        `db:"expiration_year" json:"expirationYear"`
+	ClosedAt        sql.NullTime `db:"closed_at" json:"closedAt"`
+	CreatedAt       time.Time    `db:"created_at" json:"createdAt"`
+	UpdatedAt       time.Time    `db:"updated_at" json:"updatedAt"`
+}
+
+type UpwardliTransfer struct {
+	ID                   string         `db:"id" json:"id"`
+	UpwardliTransferID   sql.NullString `db:"upwardli_transfer_id" json:"upwardliTransferId"`
+	IdempotencyKey       sql.NullString `db:"idempotency_key" json:"idempotencyKey"`
+	ConsumerID           string         `db:"consumer_id" json:"consumerId"`
+	TransferType         string         `db:"transfer_type" json:"transferType"`
+	Direction            string         `db:"direction" json:"direction"`
+	Status               string         `db:"status" json:"status"`
+	Amount               int64          `db:"amount" json:"amount"`
+	Currency             string         `db:"currency" json:"currency"`
+	SourceAccountID      string         `db:"source_account_id" json:"sourceAccountId"`
+	DestinationAccountID string         `db:"destination_account_id" json:"destinationAccountId"`
+	Description          string         `db:"description" json:"description"`
+	FailureReason        string         `db:"failure_reason" json:"failureReason"`
+	StatusUpdatedAt      time.Time      `db:"status_updated_at" json:"statusUpdatedAt"`
+	CreatedAt            time.Time      `db:"created_at" json:"createdAt"`
+	UpdatedAt            time.Time      `db:"updated_at" json:"updatedAt"`
+}
+
+type UpwardliTransferStatusTransition struct {
+	ID            int64     `db:"id" json:"id"`
+	TransferID    string    `db:"transfer_id" json:"transferId"`
+	FromStatus    string    `db:"from_status" json:"fromStatus"`
+	ToStatus      string    `db:"to_status" json:"toStatus"`
+	SourceEventID string    `db:"source_event_id" json:"sourceEventId"`
+	Accepted      bool      `db:"accepted" json:"accepted"`
+	Reason        string    `db:"reason" json:"reason"`
+	OccurredAt    time.Time `db:"occurred_at" json:"occurredAt"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
+}
+
 type UpwardliWebhook struct {
 	ID          string        `db:"id" json:"id"`
 	WebhookName string        `db:"webhook_name" json:"webhookName"`
@@ -33,4 +144,40 @@ type UpwardliWebhook struct {
 	Failures    sql.NullInt32 `db:"failures" json:"failures"`
 	LastFailure sql.NullTime  `db:"last_failure" json:"lastFailure"`
 	Deleted     sql.NullBool  `db:"deleted" json:"deleted"`
+	Provider    string        `db:"provider" json:"provider"`
+}
+
+type UpwardliWebhookDeadLetter struct {
+	EventID        string          `db:"event_id" json:"eventId"`
+	EventName      string          `db:"event_name" json:"eventName"`
+	Payload        json.RawMessage `db:"payload" json:"payload"`
+	Headers        json.RawMessage `db:"headers" json:"headers"`
+	Attempts       int32           `db:"attempts" json:"attempts"`
+	LastError      string          `db:"last_error" json:"lastError"`
+	FailedAt       time.Time       `db:"failed_at" json:"failedAt"`
+	ReplayCount    int32           `db:"replay_count" json:"replayCount"`
+	LastReplayedAt sql.NullTime    `db:"last_replayed_at" json:"lastReplayedAt"`
+	ResolvedAt     sql.NullTime    `db:"resolved_at" json:"resolvedAt"`
+	CreatedAt      time.Time       `db:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time       `db:"updated_at" json:"updatedAt"`
+}
+
+type UpwardliWebhookEvent struct {
+	ID          string          `db:"id" json:"id"`
+	EventName   string          `db:"event_name" json:"eventName"`
+	Payload     json.RawMessage `db:"payload" json:"payload"`
+	Headers     json.RawMessage `db:"headers" json:"headers"`
+	Status      string          `db:"status" json:"status"`
+	Attempts    int32           `db:"attempts" json:"attempts"`
+	LastError   sql.NullString  `db:"last_error" json:"lastError"`
+	ProcessedAt sql.NullTime    `db:"processed_at" json:"processedAt"`
+	CreatedAt   time.Time       `db:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time       `db:"updated_at" json:"updatedAt"`
+}
+
+type UpwardliWebhookNonce struct {
+	Nonce     string    `db:"nonce" json:"nonce"`
+	ExpiresAt time.Time `db:"expires_at" json:"expiresAt"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+	Provider  string    `db:"provider" json:"provider"`
 }
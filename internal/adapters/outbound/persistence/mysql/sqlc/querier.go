@@ -6,14 +6,71 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
 )
 
 type Querier interface {
-	CreateUpwardliWebhook(ctx context.Context, arg CreateUpwardliWebhookParams) error
-	GetAllUpwardliWebhooks(ctx context.Context) ([]GetAllUpwardliWebhooksRow, error)
-	GetUpwardliWebhookById(ctx context.Context, id string) (GetUpwardliWebhookByIdRow, error)
+	ClaimEventsDelivery(ctx context.Context, arg ClaimEventsDeliveryParams) (int64, error)
+	ClaimEventsDeliveryAttempt(ctx context.Context, arg ClaimEventsDeliveryAttemptParams) (int64, error)
+	ClaimStaleEventsDelivery(ctx context.Context, arg ClaimStaleEventsDeliveryParams) (int64, error)
+	ClaimStaleUpwardliWebhookEvent(ctx context.Context, arg ClaimStaleUpwardliWebhookEventParams) (int64, error)
+	ClaimUpwardliWebhookEvent(ctx context.Context, arg ClaimUpwardliWebhookEventParams) (int64, error)
+	ClaimUpwardliWebhookEventAttempt(ctx context.Context, arg ClaimUpwardliWebhookEventAttemptParams) (int64, error)
+	CreateEventsDelivery(ctx context.Context, arg CreateEventsDeliveryParams) (int64, error)
+	CreateEventsDeliveryAttempt(ctx context.Context, arg CreateEventsDeliveryAttemptParams) error
+	CreateEventsSubscriber(ctx context.Context, arg CreateEventsSubscriberParams) error
+	CreateUpwardliConsumerKycTransition(ctx context.Context, arg CreateUpwardliConsumerKycTransitionParams) error
+	CreateUpwardliTransfer(ctx context.Context, arg CreateUpwardliTransferParams) (int64, error)
+	CreateUpwardliTransferStatusTransition(ctx context.Context, arg CreateUpwardliTransferStatusTransitionParams) error
+	CreateUpwardliWebhookDeadLetter(ctx context.Context, arg CreateUpwardliWebhookDeadLetterParams) error
+	CreateUpwardliWebhookEvent(ctx context.Context, arg CreateUpwardliWebhookEventParams) (int64, error)
+	CreateWebhook(ctx context.Context, arg CreateWebhookParams) error
+	CreateWebhookNonce(ctx context.Context, arg CreateWebhookNonceParams) (int64, error)
+	DeleteExpiredWebhookNonces(ctx context.Context, provider string) (int64, error)
+	DeleteWebhookNonce(ctx context.Context, arg DeleteWebhookNonceParams) error
+	GetActiveEventsSubscribers(ctx context.Context) ([]EventsSubscriber, error)
+	GetAllEventsSubscribers(ctx context.Context) ([]EventsSubscriber, error)
+	GetEventsDeliveryById(ctx context.Context, id string) (EventsDelivery, error)
+	GetEventsSubscriberById(ctx context.Context, id string) (EventsSubscriber, error)
+	GetLock(ctx context.Context, name interface{}) (int64, error)
+	GetUpwardliCardTransactionsByCardId(ctx context.Context, paymentCardID string) ([]UpwardliCardTransaction, error)
+	GetUpwardliConsumerByExternalId(ctx context.Context, externalID string) (UpwardliConsumer, error)
+	GetUpwardliConsumerById(ctx context.Context, id string) (UpwardliConsumer, error)
+	GetUpwardliConsumerKycStatusForUpdate(ctx context.Context, id string) (string, error)
+	GetUpwardliPaymentCardById(ctx context.Context, id string) (UpwardliPaymentCard, error)
+	GetUpwardliPaymentCardsByConsumerExternalId(ctx context.Context, externalID string) ([]UpwardliPaymentCard, error)
+	GetUpwardliPaymentCardsByConsumerId(ctx context.Context, consumerID string) ([]UpwardliPaymentCard, error)
+	GetUpwardliTransferById(ctx context.Context, id string) (UpwardliTransfer, error)
+	GetUpwardliTransferByIdempotencyKey(ctx context.Context, arg GetUpwardliTransferByIdempotencyKeyParams) (UpwardliTransfer, error)
+	GetUpwardliTransferByUpwardliTransferId(ctx context.Context, upwardliTransferID sql.NullString) (UpwardliTransfer, error)
+	GetUpwardliTransferStatusForUpdate(ctx context.Context, arg GetUpwardliTransferStatusForUpdateParams) (string, error)
+	GetUpwardliTransfersByConsumerExternalId(ctx context.Context, externalID string) ([]UpwardliTransfer, error)
+	GetUpwardliTransfersByConsumerId(ctx context.Context, consumerID string) ([]UpwardliTransfer, error)
+	GetUpwardliWebhookDeadLetterByEventId(ctx context.Context, eventID string) (GetUpwardliWebhookDeadLetterByEventIdRow, error)
+	GetUpwardliWebhookEventById(ctx context.Context, id string) (GetUpwardliWebhookEventByIdRow, error)
+	GetWebhookById(ctx context.Context, arg GetWebhookByIdParams) (UpwardliWebhook, error)
+	GetWebhooksByProvider(ctx context.Context, provider string) ([]UpwardliWebhook, error)
+	ListEventsDeliveries(ctx context.Context, arg ListEventsDeliveriesParams) ([]EventsDelivery, error)
+	ListEventsDeliveriesByStatus(ctx context.Context, status string) ([]EventsDelivery, error)
+	ListEventsDeliveryAttempts(ctx context.Context, deliveryID string) ([]EventsDeliveryAttempt, error)
+	ListUnresolvedUpwardliWebhookDeadLetters(ctx context.Context, arg ListUnresolvedUpwardliWebhookDeadLettersParams) ([]ListUnresolvedUpwardliWebhookDeadLettersRow, error)
+	ListUpwardliConsumerKycTransitions(ctx context.Context, consumerID string) ([]UpwardliConsumerKycTransition, error)
+	ListUpwardliTransferStatusTransitions(ctx context.Context, transferID string) ([]UpwardliTransferStatusTransition, error)
+	ListUpwardliUnsubmittedTransfers(ctx context.Context, arg ListUpwardliUnsubmittedTransfersParams) ([]UpwardliTransfer, error)
+	ListUpwardliWebhookEventsByStatus(ctx context.Context, status string) ([]ListUpwardliWebhookEventsByStatusRow, error)
+	ReleaseLock(ctx context.Context, name interface{}) (int64, error)
+	SaveUpwardliCardTransaction(ctx context.Context, arg SaveUpwardliCardTransactionParams) error
 	SaveUpwardliConsumer(ctx context.Context, arg SaveUpwardliConsumerParams) error
-	SoftDeleteUpwardliWebhook(ctx context.Context, id string) error
+	SaveUpwardliPaymentCard(ctx context.Context, arg SaveUpwardliPaymentCardParams) error
+	SaveUpwardliTransfer(ctx context.Context, arg SaveUpwardliTransferParams) error
+	SetUpwardliTransferUpwardliTransferId(ctx context.Context, arg SetUpwardliTransferUpwardliTransferIdParams) error
+	SoftDeleteWebhook(ctx context.Context, arg SoftDeleteWebhookParams) error
+	UpdateEventsDeliveryStatus(ctx context.Context, arg UpdateEventsDeliveryStatusParams) error
+	UpdateEventsSubscriberActive(ctx context.Context, arg UpdateEventsSubscriberActiveParams) error
+	UpdateUpwardliConsumerKycStatus(ctx context.Context, arg UpdateUpwardliConsumerKycStatusParams) error
+	UpdateUpwardliWebhookDeadLetterReplay(ctx context.Context, arg UpdateUpwardliWebhookDeadLetterReplayParams) error
+	UpdateUpwardliWebhookEventStatus(ctx context.Context, arg UpdateUpwardliWebhookEventStatusParams) error
+	UpdateWebhookStatus(ctx context.Context, arg UpdateWebhookStatusParams) error
 }
 
 var _ Querier = (*Queries)(nil)
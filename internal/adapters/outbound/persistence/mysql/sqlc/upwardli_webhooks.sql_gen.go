@@ -8,11 +8,11 @@ package sqlc
 import (
 	"context"
 	"database/sql"
-	"time"
 )
 
-const createUpwardliWebhook = `-- name: CreateUpwardliWebhook :exec
+const createWebhook = `-- name: CreateWebhook :exec
 INSERT INTO upwardli.webhooks (
+        provider,
         id,
         webhook_name,
         endpoint,
@@ -21,10 +21,11 @@ INSERT INTO upwardli.webhooks (
         failures,
         last_failure
     )
-VALUES (?, ?, ?, ?, ?, ?, ?)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 `
 
-type CreateUpwardliWebhookParams struct {
+type CreateWebhookParams struct {
+	Provider    string        `db:"provider" json:"provider"`
 	ID          string        `db:"id" json:"id"`
 	WebhookName string        `db:"webhook_name" json:"webhookName"`
 	Endpoint    string        `db:"endpoint" json:"endpoint"`
@@ -34,8 +35,9 @@ type CreateUpwardliWebhookParams struct {
 	LastFailure sql.NullTime  `db:"last_failure" json:"lastFailure"`
 }
 
-func (q *Queries) CreateUpwardliWebhook(ctx context.Context, arg CreateUpwardliWebhookParams) error {
-	_, err := q.db.ExecContext(ctx, createUpwardliWebhook,
+func (q *Queries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) error {
+	_, err := q.db.ExecContext(ctx, createWebhook,
+		arg.Provider,
 		arg.ID,
 		arg.WebhookName,
 		arg.Endpoint,
@@ -47,55 +49,87 @@ func (q *Queries) CreateUpwardliWebhook(ctx context.Context, arg CreateUpwardliW
 	return err
 }
 
-const getAllUpwardliWebhooks = `-- name: GetAllUpwardliWebhooks :many
+const getWebhookById = `-- name: GetWebhookById :one
 SELECT id,
     webhook_name,
     endpoint,
     partner_id,
+    created_at,
+    updated_at,
     status,
     failures,
     last_failure,
+    deleted,
+    provider
+FROM upwardli.webhooks
+WHERE provider = ?
+    AND id = ?
+    AND deleted = FALSE
+`
+
+type GetWebhookByIdParams struct {
+	Provider string `db:"provider" json:"provider"`
+	ID       string `db:"id" json:"id"`
+}
+
+func (q *Queries) GetWebhookById(ctx context.Context, arg GetWebhookByIdParams) (UpwardliWebhook, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookById, arg.Provider, arg.ID)
+	var i UpwardliWebhook
+	err := row.Scan(
+		&i.ID,
+		&i.WebhookName,
+		&i.Endpoint,
+		&i.PartnerID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.Failures,
+		&i.LastFailure,
+		&i.Deleted,
+		&i.Provider,
+	)
+	return i, err
+}
+
+const getWebhooksByProvider = `-- name: GetWebhooksByProvider :many
+SELECT id,
+    webhook_name,
+    endpoint,
+    partner_id,
     created_at,
     updated_at,
-    deleted
+    status,
+    failures,
+    last_failure,
+    deleted,
+    provider
 FROM upwardli.webhooks
-WHERE deleted = FALSE
+WHERE provider = ?
+    AND deleted = FALSE
 ORDER BY created_at DESC
 `
 
-type GetAllUpwardliWebhooksRow struct {
-	ID          string        `db:"id" json:"id"`
-	WebhookName string        `db:"webhook_name" json:"webhookName"`
-	Endpoint    string        `db:"endpoint" json:"endpoint"`
-	PartnerID   string        `db:"partner_id" json:"partnerId"`
-	Status      string        `db:"status" json:"status"`
-	Failures    sql.NullInt32 `db:"failures" json:"failures"`
-	LastFailure sql.NullTime  `db:"last_failure" json:"lastFailure"`
-	CreatedAt   time.Time     `db:"created_at" json:"createdAt"`
-	UpdatedAt   time.Time     `db:"updated_at" json:"updatedAt"`
-	Deleted     sql.NullBool  `db:"deleted" json:"deleted"`
-}
-
-func (q *Queries) GetAllUpwardliWebhooks(ctx context.Context) ([]GetAllUpwardliWebhooksRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllUpwardliWebhooks)
+func (q *Queries) GetWebhooksByProvider(ctx context.Context, provider string) ([]UpwardliWebhook, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhooksByProvider, provider)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetAllUpwardliWebhooksRow{}
+	items := []UpwardliWebhook{}
 	for rows.Next() {
-		var i GetAllUpwardliWebhooksRow
+		var i UpwardliWebhook
 		if err := rows.Scan(
 			&i.ID,
 			&i.WebhookName,
 			&i.Endpoint,
 			&i.PartnerID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
 			&i.Status,
 			&i.Failures,
 			&i.LastFailure,
-			&i.CreatedAt,
-			&i.UpdatedAt,
 			&i.Deleted,
+			&i.Provider,
 		); err != nil {
 			return nil, err
 		}
@@ -110,53 +144,6 @@ func (q *Queries) GetAllUpwardliWebhooks(ctx context.Context) ([]GetAllUpwardliW
 	return items, nil
 }
 
-const getUpwardliWebhookById = `-- name: GetUpwardliWebhookById :one
-SELECT id,
-    webhook_name,
-    endpoint,
-    partner_id,
-    status,
-    failures,
-    last_failure,
-    created_at,
-    updated_at,
-    deleted
-FROM upwardli.webhooks
-WHERE id = ?
-    AND deleted = FALSE
-`
-
-type GetUpwardliWebhookByIdRow struct {
-	ID          string        `db:"id" json:"id"`
-	WebhookName string        `db:"webhook_name" json:"webhookName"`
-	Endpoint    string        `db:"endpoint" json:"endpoint"`
-	PartnerID   string        `db:"partner_id" json:"partnerId"`
-	Status      string        `db:"status" json:"status"`
-	Failures    sql.NullInt32 `db:"failures" json:"failures"`
-	LastFailure sql.NullTime  `db:"last_failure" json:"lastFailure"`
-	CreatedAt   time.Time     `db:"created_at" json:"createdAt"`
-	UpdatedAt   time.Time     `db:"updated_at" json:"updatedAt"`
-	Deleted     sql.NullBool  `db:"deleted" json:"deleted"`
-}
-
-func (q *Queries) GetUpwardliWebhookById(ctx context.Context, id string) (GetUpwardliWebhookByIdRow, error) {
-	row := q.db.QueryRowContext(ctx, getUpwardliWebhookById, id)
-	var i GetUpwardliWebhookByIdRow
-	err := row.Scan(
-		&i.ID,
-		&i.WebhookName,
-		&i.Endpoint,
-		&i.PartnerID,
-		&i.Status,
-		&i.Failures,
-		&i.LastFailure,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Deleted,
-	)
-	return i, err
-}
-
 const saveUpwardliConsumer = `-- name: SaveUpwardliConsumer :exec
 INSERT INTO upwardli.consumers (
         id,
@@ -164,7 +151,8 @@ INSERT INTO upwardli.consumers (
         external_id,
         is_active,
         kyc_status,
-        tax_id_type
+        tax_id_type,
+        deleted
     )
 VALUES (
         ?,
@@ -172,16 +160,18 @@ VALUES (
         ?,
         ?,
         ?,
+        ?,
         ?
     ) ON DUPLICATE KEY
 UPDATE pcid =
 VALUES(pcid),
-    is_active =
-VALUES(is_active),
-    kyc_status =
-VALUES(kyc_status),
+    is_active = IF(deleted, FALSE,
+VALUES(is_active)),
     tax_id_type =
-VALUES(tax_id_type)
+VALUES(tax_id_type),
+    deleted = deleted
+    OR
+VALUES(deleted)
 `
 
 type SaveUpwardliConsumerParams struct {
@@ -191,6 +181,7 @@ type SaveUpwardliConsumerParams struct {
 	IsActive   bool   `db:"is_active" json:"isActive"`
 	KycStatus  string `db:"kyc_status" json:"kycStatus"`
 	TaxIDType  string `db:"tax_id_type" json:"taxIdType"`
+	Deleted    bool   `db:"deleted" json:"deleted"`
 }
 
 func (q *Queries) SaveUpwardliConsumer(ctx context.Context, arg SaveUpwardliConsumerParams) error {
@@ -201,20 +192,56 @@ func (q *Queries) SaveUpwardliConsumer(ctx context.Context, arg SaveUpwardliCons
 		arg.IsActive,
 		arg.KycStatus,
 		arg.TaxIDType,
+		arg.Deleted,
 	)
 	return err
 }
 
-const softDeleteUpwardliWebhook = `-- name: SoftDeleteUpwardliWebhook :exec
+const softDeleteWebhook = `-- name: SoftDeleteWebhook :exec
 UPDATE upwardli.webhooks
 SET deleted = TRUE,
-    deleted_at = NOW(),
     updated_at = NOW()
-WHERE id = ?
+WHERE provider = ?
+    AND id = ?
     AND deleted = FALSE
 `
 
-func (q *Queries) SoftDeleteUpwardliWebhook(ctx context.Context, id string) error {
-	_, err := q.db.ExecContext(ctx, softDeleteUpwardliWebhook, id)
+type SoftDeleteWebhookParams struct {
+	Provider string `db:"provider" json:"provider"`
+	ID       string `db:"id" json:"id"`
+}
+
+func (q *Queries) SoftDeleteWebhook(ctx context.Context, arg SoftDeleteWebhookParams) error {
+	_, err := q.db.ExecContext(ctx, softDeleteWebhook, arg.Provider, arg.ID)
+	return err
+}
+
+const updateWebhookStatus = `-- name: UpdateWebhookStatus :exec
+UPDATE upwardli.webhooks
+SET status = ?,
+    failures = ?,
+    last_failure = ?,
+    updated_at = NOW()
+WHERE provider = ?
+    AND id = ?
+    AND deleted = FALSE
+`
+
+type UpdateWebhookStatusParams struct {
+	Status      string        `db:"status" json:"status"`
+	Failures    sql.NullInt32 `db:"failures" json:"failures"`
+	LastFailure sql.NullTime  `db:"last_failure" json:"lastFailure"`
+	Provider    string        `db:"provider" json:"provider"`
+	ID          string        `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateWebhookStatus(ctx context.Context, arg UpdateWebhookStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateWebhookStatus,
+		arg.Status,
+		arg.Failures,
+		arg.LastFailure,
+		arg.Provider,
+		arg.ID,
+	)
 	return err
 }
@@ -12,19 +12,25 @@ import (
 )
 
 type router struct {
+	Webhooks httphandlers.WebhookHandler
 	Upwardli httphandlers.UpwardliHandler
+	Events   httphandlers.EventSubscriberHandler
 }
 
-func newRouter(cfg config.Config, s services, w webhookProcessors) router {
+func newRouter(cfg config.Config, l logger.Logger, s services, w webhookProcessors) router {
 	return router{
-		Upwardli: httphandlers.NewUpwardliHandler(cfg, s.webhooks, w.UpwardliProcessor),
+		Webhooks: httphandlers.NewWebhookHandler(l, w.Providers),
+		Upwardli: httphandlers.NewUpwardliHandler(cfg, s.webhooks, s.webhookHealth, w.UpwardliDeadLetters, s.consumers, s.cards, s.transfers),
+		Events:   httphandlers.NewEventSubscriberHandler(s.eventSubs),
 	}
 }
 
 func (router *router) Serve(port string, logger logger.Logger) {
 	r := chi.NewRouter()
 
+	httphandlers.AcceptWebhookEndpoints(r, router.Webhooks)
 	httphandlers.AcceptUpwardliEndpoints(r, router.Upwardli)
+	httphandlers.AcceptEventSubscriberEndpoints(r, router.Events)
 
 	r.Use(cors.Handler(cors.Options{
 		AllowedHeaders: []string{"Content-Type", "Authorization"},
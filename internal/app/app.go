@@ -1,13 +1,20 @@
 package app
 
 import (
+	"context"
+	webhookprocessors "template/internal/adapters/inbound/webhook-processors"
 	"template/internal/adapters/outbound/persistence/mysql"
 	"template/internal/config"
+	webhooks "template/internal/core/webhooks"
 	"template/internal/logger"
+	"time"
 
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
+const webhookSyncTimeout = 2 * time.Minute
+
 type App struct {
 	Server router
 }
@@ -19,20 +26,78 @@ func NewApp(cfg config.Config, logger logger.Logger) *App {
 		logger.Fatal("Failed to connect to database", zap.Error(err))
 	}
 
-	cronjobs := newCronJobs(logger)
-	cronjobs.setupCronJobs()
-
 	repos := newRepositories(database, logger)
 
 	clients := newClients(cfg, logger)
 
-	webhookProcessors := newWebhookProcessors(logger, clients)
+	eventWorkers := newEventWorkers(logger)
+
+	services := newServices(cfg, logger, repos, clients, eventWorkers)
 
-	services := newServices(cfg, logger, repos, clients)
+	checkEventSigning(cfg, logger, repos)
 
-	router := newRouter(cfg, services, webhookProcessors)
+	webhookWorkers := newWebhookWorkers(logger)
+
+	webhookProcessors := newWebhookProcessors(cfg, logger, clients, repos, services, webhookWorkers)
+
+	cronjobs := newCronJobs(logger)
+	cronjobs.setupCronJobs(cfg, repos, services, webhookProcessors)
+
+	if err := webhookProcessors.UpwardliInbox.Resume(context.Background()); err != nil {
+		logger.Error("Failed to resume upwardli webhook events", zap.Error(err))
+	}
+
+	if err := services.eventHub.Resume(context.Background()); err != nil {
+		logger.Error("Failed to resume event deliveries", zap.Error(err))
+	}
+
+	if cfg.Upwardli().SyncWebhooksOnStartup {
+		syncWebhooks(cfg, logger, services)
+	}
+
+	router := newRouter(cfg, logger, services, webhookProcessors)
 
 	return &App{
 		Server: router,
 	}
 }
+
+// checkEventSigning refuses to start without INTER_SERVICE_SECRET once event
+// subscribers are configured, since their events could not be signed.
+func checkEventSigning(cfg config.Config, logger logger.Logger, r repositories) {
+	if cfg.InterServiceSecret() != "" {
+		return
+	}
+
+	subscribers, err := r.Repository.GetActiveSubscribers(context.Background())
+	if err != nil {
+		logger.Fatal("Failed to get event subscribers", zap.Error(err))
+	}
+	if len(subscribers) > 0 {
+		logger.Fatal("INTER_SERVICE_SECRET is required when event subscribers are configured",
+			zap.Int("subscribers", len(subscribers)))
+	}
+}
+
+// syncWebhooks converges the Upwardli subscriptions on the desired state. A
+// failure is logged rather than fatal so a provider outage can't block a deploy.
+func syncWebhooks(cfg config.Config, logger logger.Logger, s services) {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookSyncTimeout)
+	defer cancel()
+
+	result, err := s.webhooks.Sync(ctx, webhookprocessors.UpwardliDesiredState(cfg.Upwardli().WebhookURL), false)
+	if errors.Is(err, webhooks.ErrSubscriptionsLocked) {
+		logger.Info("Skipped upwardli webhook sync, another instance is syncing")
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to sync upwardli webhook subscriptions", zap.Error(err))
+		return
+	}
+
+	for key, reason := range result.Errors {
+		logger.Warn("Failed to sync upwardli webhook subscription",
+			zap.String("subscription", key),
+			zap.String("reason", reason))
+	}
+}
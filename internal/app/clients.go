@@ -3,25 +3,29 @@ package app
 import (
 	httpclients "template/internal/adapters/outbound/http-clients"
 	"template/internal/config"
+	events "template/internal/core/events"
 	"template/internal/logger"
 
 	"go.uber.org/zap"
 )
 
 type clients struct {
-	UpwardliPartner httpclients.UpwardliPartnerClient
+	UpwardliPartner  httpclients.UpwardliPartnerClient
+	EventSubscribers events.Sender
 }
 
 func newClients(config config.Config, logger logger.Logger) clients {
 	upwardliPartner, err := httpclients.NewUpwardliPartnerClient(httpclients.UpwardliPartnerClientConfig{
 		Config: config.Upwardli(),
 		Scope:  nil,
+		Logger: logger,
 	})
 	if err != nil {
 		logger.Fatal("failed to create upwardli partner client", zap.Error(err))
 	}
 
 	return clients{
-		UpwardliPartner: upwardliPartner,
+		UpwardliPartner:  upwardliPartner,
+		EventSubscribers: httpclients.NewEventSubscriberClient(config.InterServiceSecret()),
 	}
 }
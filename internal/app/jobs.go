@@ -2,8 +2,38 @@ package app
 
 import (
 	"template/internal/adapters/inbound/jobs"
+	webhookprocessors "template/internal/adapters/inbound/webhook-processors"
+	"template/internal/config"
+	events "template/internal/core/events"
+	webhooks "template/internal/core/webhooks"
 	"template/internal/logger"
 	"template/packages/cronjob-go"
+	"time"
+)
+
+const (
+	webhookWorkerCount    = 4
+	webhookQueueSize      = 1000
+	webhookMaxRetries     = 5
+	webhookBaseRetryDelay = 5 * time.Second
+	webhookMaxRetryDelay  = 10 * time.Minute
+
+	// Retries and backoff per delivery come from the subscriber.
+	eventDeliveryWorkerCount = 4
+	eventDeliveryQueueSize   = 1000
+
+	// every 15 minutes
+	webhookReconciliationSpec = "0 */15 * * * *"
+	// every 5 minutes
+	webhookHealthSpec = "0 */5 * * * *"
+	// every hour
+	webhookNonceCleanupSpec = "0 0 * * * *"
+	// every minute
+	webhookEventSweepSpec = "0 * * * * *"
+	// every minute
+	eventDeliverySweepSpec = "15 * * * * *"
+	// every 5 minutes
+	transferResubmitSpec = "30 */5 * * * *"
 )
 
 type cronjobs struct {
@@ -16,13 +46,25 @@ func newCronJobs(logger logger.Logger) cronjobs {
 	}
 }
 
-func (c *cronjobs) setupCronJobs() {
+func (c *cronjobs) setupCronJobs(cfg config.Config, r repositories, s services, w webhookProcessors) {
 	c.logger.Info("Setting up cron jobs")
 
 	scheduler := cronjob.NewScheduler(1, 100)
 	cronScheduler := cronjob.NewCronScheduler(scheduler)
 
 	cronScheduler.AddJob("0 2 * * *", c.WithLogger(jobs.FakeJob))
+	cronScheduler.AddJob(webhookReconciliationSpec, c.WithLogger(jobs.WebhookReconciliationJob(s.webhooks, webhooks.ReconcileOptions{
+		Endpoint:        cfg.Upwardli().WebhookURL,
+		RequiredTopics:  webhookprocessors.UpwardliSubscriptionTopics,
+		RecreateMissing: true,
+	})))
+	cronScheduler.AddJob(webhookHealthSpec, c.WithLogger(jobs.WebhookHealthJob(s.webhookHealth)))
+	cronScheduler.AddJob(transferResubmitSpec, c.WithLogger(jobs.TransferResubmitJob(s.transfers)))
+	cronScheduler.AddJob(webhookEventSweepSpec, c.WithLogger(jobs.WebhookEventSweepJob(w.UpwardliInbox, webhooks.ProviderUpwardli)))
+	cronScheduler.AddJob(eventDeliverySweepSpec, c.WithLogger(jobs.EventDeliverySweepJob(s.eventHub)))
+	if cfg.Webhooks(webhooks.ProviderUpwardli).ReplayStore == webhooks.ReplayStoreMySQL {
+		cronScheduler.AddJob(webhookNonceCleanupSpec, c.WithLogger(jobs.WebhookNonceCleanupJob(r.Repository, webhooks.ProviderUpwardli)))
+	}
 
 	c.logger.Info("Starting cron jobs")
 	cronScheduler.Start()
@@ -33,3 +75,35 @@ func (c *cronjobs) WithLogger(job func(logger logger.Logger)) func() {
 		job(c.logger)
 	}
 }
+
+type webhookWorkers struct {
+	Dispatcher webhooks.Dispatcher
+}
+
+func newWebhookWorkers(logger logger.Logger) webhookWorkers {
+	logger.Info("Starting webhook workers")
+
+	scheduler := cronjob.NewScheduler(webhookWorkerCount, webhookQueueSize,
+		cronjob.WithExponentialBackoff(webhookBaseRetryDelay, webhookMaxRetryDelay),
+	)
+	scheduler.Start()
+
+	return webhookWorkers{
+		Dispatcher: jobs.NewWebhookEventDispatcher(logger, scheduler, webhookMaxRetries),
+	}
+}
+
+type eventWorkers struct {
+	Dispatcher events.Dispatcher
+}
+
+func newEventWorkers(logger logger.Logger) eventWorkers {
+	logger.Info("Starting event delivery workers")
+
+	scheduler := cronjob.NewScheduler(eventDeliveryWorkerCount, eventDeliveryQueueSize)
+	scheduler.Start()
+
+	return eventWorkers{
+		Dispatcher: jobs.NewEventDeliveryDispatcher(logger, scheduler),
+	}
+}
@@ -2,21 +2,78 @@ package app
 
 import (
 	"template/internal/config"
+	banking "template/internal/core/banking"
+	cards "template/internal/core/cards"
+	events "template/internal/core/events"
+	transfers "template/internal/core/transfers"
 	webhooks "template/internal/core/webhooks"
 	"template/internal/logger"
 )
 
 type services struct {
-	webhooks webhooks.Service
+	webhooks      webhooks.Service
+	webhookHealth webhooks.HealthMonitor
+	consumers     banking.ConsumerManager
+	cards         cards.Service
+	transfers     transfers.Service
+	eventHub      events.Hub
+	eventSubs     events.SubscriberManager
 }
 
-func newServices(config config.Config, logger logger.Logger, repos repositories, clients clients) services {
+func newServices(config config.Config, logger logger.Logger, repos repositories, clients clients, e eventWorkers) services {
 	webhooksService := webhooks.NewService(logger, repos.Repository, clients.UpwardliPartner, webhooks.ProviderUpwardli)
 	if webhooksService == nil {
 		logger.Fatal("failed to create upwardli service")
 	}
 
+	webhookHealth := webhooks.NewHealthMonitor(logger, clients.UpwardliPartner, repos.Repository, webhooks.ProviderUpwardli)
+	if webhookHealth == nil {
+		logger.Fatal("failed to create upwardli webhook health monitor")
+	}
+
+	consumerManager := banking.NewConsumerManager(
+		logger,
+		repos.Repository,
+		clients.UpwardliPartner,
+		config.Upwardli().EmbeddedComponentURL,
+	)
+	if consumerManager == nil {
+		logger.Fatal("failed to create banking consumer manager")
+	}
+
+	cardsService := cards.NewService(logger, repos.Repository, repos.Repository)
+	if cardsService == nil {
+		logger.Fatal("failed to create cards service")
+	}
+
+	transfersService := transfers.NewService(
+		logger,
+		repos.Repository,
+		repos.Repository,
+		clients.UpwardliPartner,
+		config.Upwardli().FBOAccountNumber,
+	)
+	if transfersService == nil {
+		logger.Fatal("failed to create transfers service")
+	}
+
+	eventHub := events.NewHub(logger, repos.Repository, clients.EventSubscribers, e.Dispatcher)
+	if eventHub == nil {
+		logger.Fatal("failed to create event hub")
+	}
+
+	eventSubscribers := events.NewSubscriberManager(logger, repos.Repository, eventHub, e.Dispatcher, clients.EventSubscribers)
+	if eventSubscribers == nil {
+		logger.Fatal("failed to create event subscriber manager")
+	}
+
 	return services{
-		webhooks: *webhooksService,
+		webhooks:      *webhooksService,
+		webhookHealth: webhookHealth,
+		consumers:     consumerManager,
+		cards:         cardsService,
+		transfers:     transfersService,
+		eventHub:      eventHub,
+		eventSubs:     eventSubscribers,
 	}
 }
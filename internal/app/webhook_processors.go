@@ -2,16 +2,88 @@ package app
 
 import (
 	webhookprocessors "template/internal/adapters/inbound/webhook-processors"
+	"template/internal/config"
 	webhooks "template/internal/core/webhooks"
 	"template/internal/logger"
+	webhookSDK "template/packages/webhook-go"
+
+	"go.uber.org/zap"
 )
 
 type webhookProcessors struct {
-	UpwardliProcessor webhooks.Processor
+	Providers           webhooks.ProviderRegistry
+	UpwardliProcessor   webhooks.Processor
+	UpwardliInbox       webhooks.Inbox
+	UpwardliDeadLetters webhooks.DeadLetterManager
 }
 
-func newWebhookProcessors(l logger.Logger, c clients) webhookProcessors {
+func newWebhookProcessors(cfg config.Config, l logger.Logger, c clients, r repositories, s services, w webhookWorkers) webhookProcessors {
+	upwardliTopics := webhooks.NewTopicRegistry(l, webhooks.ProviderUpwardli)
+
+	if err := webhookprocessors.RegisterUpwardliConsumerHandlers(upwardliTopics, l, s.consumers, s.eventHub); err != nil {
+		l.Fatal("failed to register upwardli consumer handlers", zap.Error(err))
+	}
+
+	if err := webhookprocessors.RegisterUpwardliCardHandlers(upwardliTopics, l, s.cards, s.eventHub); err != nil {
+		l.Fatal("failed to register upwardli card handlers", zap.Error(err))
+	}
+
+	if err := webhookprocessors.RegisterUpwardliTransferHandlers(upwardliTopics, l, s.transfers, s.eventHub); err != nil {
+		l.Fatal("failed to register upwardli transfer handlers", zap.Error(err))
+	}
+
+	topics := upwardliTopics.Topics()
+	names := make([]string, len(topics))
+	for i, topic := range topics {
+		names[i] = string(topic)
+	}
+	l.Info("registered upwardli webhook topics", zap.Strings("topics", names))
+
+	upwardliProcessor := webhookprocessors.NewUpwardliProcessor(l, c.UpwardliPartner, upwardliTopics)
+
+	upwardliInbox := webhooks.NewInbox(l, upwardliProcessor, r.Repository, w.Dispatcher, webhooks.ProviderUpwardli)
+	if upwardliInbox == nil {
+		l.Fatal("failed to create upwardli webhook inbox")
+	}
+
+	upwardliDeadLetters := webhooks.NewDeadLetterManager(l, r.Repository, upwardliInbox, webhooks.ProviderUpwardli)
+	if upwardliDeadLetters == nil {
+		l.Fatal("failed to create upwardli dead letter manager")
+	}
+
+	// Registrations and nonces are stored per provider, but April has no
+	// verifier, processor or subscription client yet, so only Upwardli is
+	// registered.
+	providers := webhooks.NewProviderRegistry()
+	upwardliWebhooks := cfg.Webhooks(webhooks.ProviderUpwardli)
+	err := providers.Register(webhooks.ProviderUpwardli, webhooks.ProviderRegistration{
+		Verifier: webhookprocessors.NewUpwardliVerifier(
+			l,
+			upwardliWebhooks.Secrets,
+			upwardliWebhooks.Tolerance,
+			newNonceStore(upwardliWebhooks.ReplayStore, r, webhooks.ProviderUpwardli),
+		),
+		Processor:    upwardliProcessor,
+		Client:       c.UpwardliPartner,
+		Inbox:        upwardliInbox,
+		MaxBodyBytes: upwardliWebhooks.MaxBodyBytes,
+	})
+	if err != nil {
+		l.Fatal("failed to register upwardli webhook provider", zap.Error(err))
+	}
+
 	return webhookProcessors{
-		UpwardliProcessor: webhookprocessors.NewUpwardliProcessor(l, c.UpwardliPartner),
+		Providers:           providers,
+		UpwardliProcessor:   upwardliProcessor,
+		UpwardliInbox:       upwardliInbox,
+		UpwardliDeadLetters: upwardliDeadLetters,
 	}
 }
+
+func newNonceStore(store string, r repositories, provider webhooks.Provider) webhookSDK.NonceStore {
+	if store == webhooks.ReplayStoreMySQL {
+		return webhooks.NewNonceStore(r.Repository, provider)
+	}
+
+	return webhookSDK.NewMemoryNonceStore(webhookSDK.DefaultNonceCapacity)
+}
@@ -6,6 +6,7 @@ import (
 	"template/internal/core/aws"
 	banking "template/internal/core/banking"
 	"template/internal/core/plaid"
+	webhooks "template/internal/core/webhooks"
 )
 
 type Config interface {
@@ -21,6 +22,8 @@ type Config interface {
 	AWS() aws.Config
 	Plaid() plaid.Config
 	Upwardli() banking.Config
+	// Webhooks returns how deliveries from a provider are received.
+	Webhooks(provider webhooks.Provider) webhooks.Config
 
 	// Internal services
 	InterServiceSecret() string
@@ -41,6 +44,7 @@ type config struct {
 	awsConfig            aws.Config
 	plaidConfig          plaid.Config
 	bankingConfig        banking.Config
+	webhookConfigs       map[webhooks.Provider]webhooks.Config
 }
 
 func Load() (Config, error) {
@@ -71,3 +75,7 @@ func (c *config) Upwardli() banking.Config     { return c.bankingConfig }
 func (c *config) InterServiceSecret() string   { return c.interServiceSecret }
 func (c *config) ClientJWTTokenSecret() string { return c.clientJWTTokenSecret }
 func (c *config) SentryDSN() string            { return c.sentryDSN }
+
+func (c *config) Webhooks(provider webhooks.Provider) webhooks.Config {
+	return c.webhookConfigs[provider]
+}
@@ -2,13 +2,18 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
 	"template/internal/adapters/outbound/persistence/mysql"
 	"template/internal/core/aws"
 	banking "template/internal/core/banking"
 	"template/internal/core/plaid"
+	webhooks "template/internal/core/webhooks"
+	webhookSDK "template/packages/webhook-go"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
 	plaidSDK "github.com/plaid/plaid-go/v32/plaid"
 )
 
@@ -17,6 +22,16 @@ func loadEnvFile() error {
 }
 
 func loadFromEnv() (*config, error) {
+	upwardliWebhooks, err := loadWebhookConfig("UPWARDLI")
+	if err != nil {
+		return nil, err
+	}
+
+	upwardliLimits, err := loadClientLimits("UPWARDLI")
+	if err != nil {
+		return nil, err
+	}
+
 	env := os.Getenv("ENV")
 	if env == "" {
 		env = "DEVELOPMENT"
@@ -57,12 +72,112 @@ func loadFromEnv() (*config, error) {
 		},
 
 		bankingConfig: banking.Config{
-			AuthURL:              os.Getenv("UPWARDLI_AUTH_URL"),
-			APIURL:               os.Getenv("UPWARDLI_API_URL"),
-			ClientID:             os.Getenv("UPWARDLI_CLIENT_ID"),
-			ClientSecret:         os.Getenv("UPWARDLI_CLIENT_SECRET"),
-			EmbeddedComponentURL: os.Getenv("UPWARDLI_EMBEDDED_COMPONENT_URL"),
-			FBOAccountNumber:     os.Getenv("UPWARDLI_FBO_ACCOUNT_NUMBER"),
+			AuthURL:               os.Getenv("UPWARDLI_AUTH_URL"),
+			APIURL:                os.Getenv("UPWARDLI_API_URL"),
+			ClientID:              os.Getenv("UPWARDLI_CLIENT_ID"),
+			ClientSecret:          os.Getenv("UPWARDLI_CLIENT_SECRET"),
+			EmbeddedComponentURL:  os.Getenv("UPWARDLI_EMBEDDED_COMPONENT_URL"),
+			FBOAccountNumber:      os.Getenv("UPWARDLI_FBO_ACCOUNT_NUMBER"),
+			WebhookURL:            os.Getenv("UPWARDLI_WEBHOOK_URL"),
+			SyncWebhooksOnStartup: os.Getenv("UPWARDLI_SYNC_WEBHOOKS_ON_STARTUP") == "true",
+			RateLimit:             upwardliLimits.RateLimit,
+			RateBurst:             upwardliLimits.RateBurst,
+			MaxInFlight:           upwardliLimits.MaxInFlight,
+		},
+
+		webhookConfigs: map[webhooks.Provider]webhooks.Config{
+			webhooks.ProviderUpwardli: upwardliWebhooks,
 		},
 	}, nil
 }
+
+// loadClientLimits reads how fast a partner API may be called from the
+// variables starting with prefix, e.g. UPWARDLI_RATE_LIMIT.
+func loadClientLimits(prefix string) (banking.Config, error) {
+	cfg := banking.Config{
+		RateLimit:   banking.DefaultRateLimit,
+		RateBurst:   banking.DefaultRateBurst,
+		MaxInFlight: banking.DefaultMaxInFlight,
+	}
+
+	if value := os.Getenv(prefix + "_RATE_LIMIT"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return cfg, errors.Wrapf(err, "invalid %s_RATE_LIMIT", prefix)
+		}
+		cfg.RateLimit = parsed
+	}
+
+	if value := os.Getenv(prefix + "_RATE_BURST"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return cfg, errors.Wrapf(err, "invalid %s_RATE_BURST", prefix)
+		}
+		cfg.RateBurst = parsed
+	}
+
+	if value := os.Getenv(prefix + "_MAX_IN_FLIGHT"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return cfg, errors.Wrapf(err, "invalid %s_MAX_IN_FLIGHT", prefix)
+		}
+		cfg.MaxInFlight = parsed
+	}
+
+	return cfg, nil
+}
+
+// loadWebhookConfig reads how a provider's webhooks are received from the
+// variables starting with prefix, e.g. UPWARDLI_WEBHOOK_SECRETS.
+func loadWebhookConfig(prefix string) (webhooks.Config, error) {
+	cfg := webhooks.Config{
+		Tolerance:    webhookSDK.DefaultTolerance,
+		ReplayStore:  os.Getenv(prefix + "_WEBHOOK_REPLAY_STORE"),
+		MaxBodyBytes: webhooks.DefaultMaxBodyBytes,
+	}
+	if cfg.ReplayStore == "" {
+		cfg.ReplayStore = webhooks.ReplayStoreMemory
+	}
+
+	if value := os.Getenv(prefix + "_WEBHOOK_TOLERANCE"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return cfg, errors.Wrapf(err, "invalid %s_WEBHOOK_TOLERANCE", prefix)
+		}
+		cfg.Tolerance = parsed
+	}
+
+	if value := os.Getenv(prefix + "_WEBHOOK_MAX_BODY_BYTES"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return cfg, errors.Wrapf(err, "invalid %s_WEBHOOK_MAX_BODY_BYTES", prefix)
+		}
+		cfg.MaxBodyBytes = parsed
+	}
+
+	secrets, err := loadWebhookSecrets(prefix+"_WEBHOOK_SECRETS", prefix+"_WEBHOOK_SECRET")
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Secrets = secrets
+
+	return cfg, nil
+}
+
+// loadWebhookSecrets reads a JSON list of signing secrets from keyringEnv,
+// falling back to a single secret in secretEnv.
+func loadWebhookSecrets(keyringEnv, secretEnv string) ([]webhookSDK.Secret, error) {
+	if value := os.Getenv(keyringEnv); value != "" {
+		secrets, err := webhookSDK.ParseSecrets(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", keyringEnv)
+		}
+		return secrets, nil
+	}
+
+	if value := os.Getenv(secretEnv); value != "" {
+		return []webhookSDK.Secret{{ID: "default", Value: value}}, nil
+	}
+
+	return nil, nil
+}
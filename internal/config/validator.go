@@ -1,6 +1,39 @@
 package config
 
+import (
+	"strings"
+	webhooks "template/internal/core/webhooks"
+
+	"github.com/pkg/errors"
+)
+
 func validate(c Config) error {
 	// TO DO: Implement validation logic
+	for _, provider := range []webhooks.Provider{webhooks.ProviderUpwardli} {
+		cfg := c.Webhooks(provider)
+		prefix := strings.ToUpper(string(provider))
+
+		switch cfg.ReplayStore {
+		case webhooks.ReplayStoreMemory, webhooks.ReplayStoreMySQL:
+		default:
+			return errors.Errorf("invalid %s_WEBHOOK_REPLAY_STORE: %s", prefix, cfg.ReplayStore)
+		}
+
+		if cfg.MaxBodyBytes <= 0 {
+			return errors.Errorf("invalid %s_WEBHOOK_MAX_BODY_BYTES: %d", prefix, cfg.MaxBodyBytes)
+		}
+	}
+
+	upwardli := c.Upwardli()
+	if upwardli.RateLimit < 0 {
+		return errors.Errorf("invalid UPWARDLI_RATE_LIMIT: %v", upwardli.RateLimit)
+	}
+	if upwardli.RateBurst < 0 {
+		return errors.Errorf("invalid UPWARDLI_RATE_BURST: %d", upwardli.RateBurst)
+	}
+	if upwardli.MaxInFlight < 0 {
+		return errors.Errorf("invalid UPWARDLI_MAX_IN_FLIGHT: %d", upwardli.MaxInFlight)
+	}
+
 	return nil
 }
@@ -1,5 +1,11 @@
 package banking
 
+const (
+	DefaultRateLimit   = 10
+	DefaultRateBurst   = 20
+	DefaultMaxInFlight = 10
+)
+
 type Config struct {
 	BaseURL              string
 	AuthURL              string
@@ -9,4 +15,14 @@ type Config struct {
 	EmbeddedComponentURL string
 	FBOAccountNumber     string
 	WebhookURL           string
+	// SyncWebhooksOnStartup converges the webhook subscriptions on the
+	// desired state when the service starts.
+	SyncWebhooksOnStartup bool
+	// RateLimit is the number of requests per second sent to the partner
+	// API, in bursts of up to RateBurst. Zero disables rate limiting.
+	RateLimit float64
+	RateBurst int
+	// MaxInFlight caps the concurrent requests to the partner API. Zero
+	// disables the cap.
+	MaxInFlight int
 }
@@ -1,17 +1,160 @@
 package banking
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"template/internal/logger"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var ErrKYCStatusChanged = errors.New("KYC status changed concurrently")
+
+// kycTransitionAttempts bounds how often a transition is decided again after
+// losing a race with another one.
+const kycTransitionAttempts = 3
 
 type consumerManager struct {
-	repo Repository
+	logger               logger.Logger
+	repo                 Repository
+	client               ConsumerClient
+	embeddedComponentURL string
 }
 
-func NewConsumerManager(repo Repository) ConsumerManager {
+func NewConsumerManager(
+	logger logger.Logger,
+	repo Repository,
+	client ConsumerClient,
+	embeddedComponentURL string,
+) ConsumerManager {
+	if logger == nil {
+		return nil
+	}
+
 	return &consumerManager{
-		repo: repo,
+		logger:               logger,
+		repo:                 repo,
+		client:               client,
+		embeddedComponentURL: embeddedComponentURL,
 	}
 }
 
-func (m *consumerManager) SaveConsumer(ctx context.Context, consumer Consumer) error {
+func (m *consumerManager) SaveConsumer(ctx context.Context, consumer Consumer) (Consumer, error) {
+	stored, err := m.repo.GetBankingConsumer(ctx, consumer.ID)
+	if err != nil {
+		return Consumer{}, errors.Wrap(err, "failed to get consumer")
+	}
+
+	// Updates can arrive after the consumer was closed, the upsert keeps it
+	// closed too
+	if stored != nil && stored.Deleted {
+		consumer.IsActive = false
+		consumer.Deleted = true
+	}
+
+	if err := m.repo.SaveBankingConsumer(ctx, consumer); err != nil {
+		return Consumer{}, err
+	}
+
+	return consumer, nil
+}
+
+func (m *consumerManager) CloseConsumer(ctx context.Context, consumer Consumer) error {
+	consumer.IsActive = false
+	consumer.Deleted = true
 	return m.repo.SaveBankingConsumer(ctx, consumer)
 }
+
+func (m *consumerManager) TransitionKYC(
+	ctx context.Context,
+	consumer Consumer,
+	to KYCStatus,
+	sourceEventID string,
+	occurredAt time.Time,
+) (KYCTransition, error) {
+	if consumer.ID == "" {
+		return KYCTransition{}, errors.New("consumer ID is required")
+	}
+	if to == KYCStatusNone || !IsValidKYCStatus(to) {
+		return KYCTransition{}, errors.Errorf("invalid KYC status: %s", to)
+	}
+
+	// The status is checked again when the transition is saved, so a
+	// concurrent transition makes us decide again on the new status
+	for attempt := 1; ; attempt++ {
+		transition, err := m.transitionKYC(ctx, consumer, to, sourceEventID, occurredAt)
+		if errors.Is(err, ErrKYCStatusChanged) && attempt < kycTransitionAttempts {
+			continue
+		}
+
+		return transition, err
+	}
+}
+
+func (m *consumerManager) transitionKYC(
+	ctx context.Context,
+	consumer Consumer,
+	to KYCStatus,
+	sourceEventID string,
+	occurredAt time.Time,
+) (KYCTransition, error) {
+	stored, err := m.repo.GetBankingConsumer(ctx, consumer.ID)
+	if err != nil {
+		return KYCTransition{}, errors.Wrap(err, "failed to get consumer")
+	}
+
+	from := KYCStatusNone
+	if stored != nil {
+		from = stored.KYCStatus
+	}
+
+	transition := KYCTransition{
+		ConsumerID:    consumer.ID,
+		From:          from,
+		To:            to,
+		SourceEventID: sourceEventID,
+		Accepted:      true,
+		OccurredAt:    occurredAt,
+	}
+
+	if from == to {
+		m.logger.Debug("ignoring repeated KYC status",
+			zap.String("consumerID", consumer.ID),
+			zap.String("status", string(to)),
+			zap.String("eventID", sourceEventID))
+		return transition, nil
+	}
+
+	if !CanTransitionKYC(from, to) {
+		transition.Accepted = false
+		transition.Reason = fmt.Sprintf("transition from %q to %q is not allowed", from, to)
+
+		m.logger.Warn("rejected out-of-order KYC transition",
+			zap.String("consumerID", consumer.ID),
+			zap.String("from", string(from)),
+			zap.String("to", string(to)),
+			zap.String("eventID", sourceEventID))
+	}
+
+	consumer.KYCStatus = to
+	if err := m.repo.SaveKYCTransition(ctx, consumer, transition); err != nil {
+		return KYCTransition{}, errors.Wrap(err, "failed to save KYC transition")
+	}
+
+	return transition, nil
+}
+
+func (m *consumerManager) GetKYCHistory(ctx context.Context, consumerID string) ([]KYCTransition, error) {
+	if consumerID == "" {
+		return nil, errors.New("consumer ID is required")
+	}
+
+	transitions, err := m.repo.GetKYCTransitions(ctx, consumerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get KYC transitions")
+	}
+
+	return transitions, nil
+}
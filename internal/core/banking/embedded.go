@@ -0,0 +1,55 @@
+package banking
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var ErrConsumerClosed = ErrConsumerNotFound.WithMessage("user's banking consumer is closed")
+
+func (m *consumerManager) CreateEmbeddedSession(ctx context.Context, userID string, component EmbeddedComponent) (*EmbeddedSession, error) {
+	if component != EmbeddedComponentCard && component != EmbeddedComponentKYC {
+		return nil, ErrInvalidConsumer.WithMessagef("invalid embedded component: %s", component)
+	}
+	if m.embeddedComponentURL == "" {
+		return nil, errors.New("embedded component URL is not configured")
+	}
+
+	consumer, err := m.userConsumer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Inactive consumers still need the KYC component to get activated
+	if consumer.Deleted {
+		return nil, ErrConsumerClosed
+	}
+
+	session, err := m.client.CreateEmbeddedSession(ctx, consumer.ID, component, EmbeddedSessionTTL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create embedded session")
+	}
+
+	componentURL, err := url.Parse(m.embeddedComponentURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid embedded component URL")
+	}
+
+	query := componentURL.Query()
+	query.Set("component", string(component))
+	query.Set("token", session.Token)
+	componentURL.RawQuery = query.Encode()
+
+	session.ConsumerID = consumer.ID
+	session.Component = component
+	session.URL = componentURL.String()
+
+	m.logger.Info("created embedded component session",
+		zap.String("consumerID", consumer.ID),
+		zap.String("component", string(component)),
+		zap.Time("expiresAt", session.ExpiresAt))
+
+	return session, nil
+}
@@ -0,0 +1,29 @@
+package banking
+
+// kycTransitions lists the statuses a consumer may move to from each status.
+// Approved is terminal.
+var kycTransitions = map[kycStatus][]kycStatus{
+	KYCStatusNone:        {KYCStatusStarted, KYCStatusPending},
+	KYCStatusStarted:     {KYCStatusPending, KYCStatusFailed},
+	KYCStatusPending:     {KYCStatusCompleted, KYCStatusNeedsReview, KYCStatusApproved, KYCStatusFailed},
+	KYCStatusCompleted:   {KYCStatusNeedsReview, KYCStatusApproved, KYCStatusFailed},
+	KYCStatusNeedsReview: {KYCStatusPending, KYCStatusApproved, KYCStatusFailed},
+	KYCStatusFailed:      {KYCStatusStarted},
+	KYCStatusApproved:    {},
+}
+
+// CanTransitionKYC reports whether a consumer may move between the two statuses.
+func CanTransitionKYC(from, to KYCStatus) bool {
+	for _, allowed := range kycTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidKYCStatus reports whether the status is part of the KYC state machine.
+func IsValidKYCStatus(status KYCStatus) bool {
+	_, ok := kycTransitions[status]
+	return ok
+}
@@ -0,0 +1,281 @@
+package banking_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	banking "template/internal/core/banking"
+	"template/internal/logger"
+)
+
+// fakeRepository stores consumers and their KYC transitions in memory.
+// Saving a transition fails when the stored status is no longer its From,
+// as in the database.
+type fakeRepository struct {
+	consumers   map[string]banking.Consumer
+	transitions []banking.KYCTransition
+	// concurrent are statuses another transition saves right before each
+	// of the next saves
+	concurrent []banking.KYCStatus
+}
+
+func newFakeRepository(consumers ...banking.Consumer) *fakeRepository {
+	repo := &fakeRepository{consumers: map[string]banking.Consumer{}}
+	for _, consumer := range consumers {
+		repo.consumers[consumer.ID] = consumer
+	}
+	return repo
+}
+
+func (r *fakeRepository) SaveBankingConsumer(ctx context.Context, consumer banking.Consumer) error {
+	r.consumers[consumer.ID] = consumer
+	return nil
+}
+
+func (r *fakeRepository) GetBankingConsumer(ctx context.Context, id string) (*banking.Consumer, error) {
+	consumer, ok := r.consumers[id]
+	if !ok {
+		return nil, nil
+	}
+	return &consumer, nil
+}
+
+func (r *fakeRepository) GetBankingConsumerByExternalID(ctx context.Context, externalID string) (*banking.Consumer, error) {
+	for _, consumer := range r.consumers {
+		if consumer.ExternalID == externalID && !consumer.Deleted {
+			return &consumer, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRepository) SaveKYCTransition(ctx context.Context, consumer banking.Consumer, transition banking.KYCTransition) error {
+	if len(r.concurrent) > 0 {
+		stored := r.consumers[consumer.ID]
+		stored.ID = consumer.ID
+		stored.KYCStatus = r.concurrent[0]
+		r.consumers[consumer.ID] = stored
+		r.concurrent = r.concurrent[1:]
+	}
+
+	if r.consumers[consumer.ID].KYCStatus != transition.From {
+		return banking.ErrKYCStatusChanged
+	}
+
+	r.transitions = append(r.transitions, transition)
+	if transition.Accepted {
+		r.consumers[consumer.ID] = consumer
+	}
+	return nil
+}
+
+func (r *fakeRepository) GetKYCTransitions(ctx context.Context, consumerID string) ([]banking.KYCTransition, error) {
+	var transitions []banking.KYCTransition
+	for _, transition := range r.transitions {
+		if transition.ConsumerID == consumerID {
+			transitions = append(transitions, transition)
+		}
+	}
+	return transitions, nil
+}
+
+const testConsumerID = "con_1"
+
+func newTestManager(repo *fakeRepository) banking.ConsumerManager {
+	return banking.NewConsumerManager(&logger.NoOpLogger{}, repo, nil, "")
+}
+
+func consumerWithStatus(status banking.KYCStatus) banking.Consumer {
+	return banking.Consumer{ID: testConsumerID, ExternalID: "user_1", KYCStatus: status}
+}
+
+func TestCanTransitionKYC(t *testing.T) {
+	tests := []struct {
+		from banking.KYCStatus
+		to   banking.KYCStatus
+		want bool
+	}{
+		{from: banking.KYCStatusNone, to: banking.KYCStatusStarted, want: true},
+		{from: banking.KYCStatusNone, to: banking.KYCStatusPending, want: true},
+		{from: banking.KYCStatusStarted, to: banking.KYCStatusPending, want: true},
+		{from: banking.KYCStatusPending, to: banking.KYCStatusApproved, want: true},
+		{from: banking.KYCStatusPending, to: banking.KYCStatusNeedsReview, want: true},
+		{from: banking.KYCStatusCompleted, to: banking.KYCStatusApproved, want: true},
+		{from: banking.KYCStatusNeedsReview, to: banking.KYCStatusPending, want: true},
+		{from: banking.KYCStatusFailed, to: banking.KYCStatusStarted, want: true},
+		{from: banking.KYCStatusNone, to: banking.KYCStatusApproved, want: false},
+		{from: banking.KYCStatusPending, to: banking.KYCStatusStarted, want: false},
+		{from: banking.KYCStatusFailed, to: banking.KYCStatusApproved, want: false},
+		{from: banking.KYCStatusApproved, to: banking.KYCStatusFailed, want: false},
+		{from: banking.KYCStatusApproved, to: banking.KYCStatusPending, want: false},
+		{from: banking.KYCStatusPending, to: banking.KYCStatusPending, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			if got := banking.CanTransitionKYC(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransitionKYC(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransitionKYC(t *testing.T) {
+	tests := []struct {
+		name string
+		// stored is nil for a consumer that is not stored yet
+		stored          *banking.Consumer
+		to              banking.KYCStatus
+		wantAccepted    bool
+		wantStatus      banking.KYCStatus
+		wantTransitions int
+	}{
+		{
+			name:            "first status of a new consumer",
+			to:              banking.KYCStatusPending,
+			wantAccepted:    true,
+			wantStatus:      banking.KYCStatusPending,
+			wantTransitions: 1,
+		},
+		{
+			name:            "allowed transition",
+			stored:          &banking.Consumer{ID: testConsumerID, KYCStatus: banking.KYCStatusPending},
+			to:              banking.KYCStatusApproved,
+			wantAccepted:    true,
+			wantStatus:      banking.KYCStatusApproved,
+			wantTransitions: 1,
+		},
+		{
+			name:            "out-of-order webhook after approval",
+			stored:          &banking.Consumer{ID: testConsumerID, KYCStatus: banking.KYCStatusApproved},
+			to:              banking.KYCStatusPending,
+			wantAccepted:    false,
+			wantStatus:      banking.KYCStatusApproved,
+			wantTransitions: 1,
+		},
+		{
+			name:            "out-of-order webhook before start",
+			stored:          &banking.Consumer{ID: testConsumerID, KYCStatus: banking.KYCStatusPending},
+			to:              banking.KYCStatusStarted,
+			wantAccepted:    false,
+			wantStatus:      banking.KYCStatusPending,
+			wantTransitions: 1,
+		},
+		{
+			name:            "duplicate webhook",
+			stored:          &banking.Consumer{ID: testConsumerID, KYCStatus: banking.KYCStatusApproved},
+			to:              banking.KYCStatusApproved,
+			wantAccepted:    true,
+			wantStatus:      banking.KYCStatusApproved,
+			wantTransitions: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository()
+			if tt.stored != nil {
+				repo = newFakeRepository(*tt.stored)
+			}
+			manager := newTestManager(repo)
+
+			transition, err := manager.TransitionKYC(context.Background(), consumerWithStatus(tt.to), tt.to, "evt_1", time.Now())
+			if err != nil {
+				t.Fatalf("TransitionKYC() error = %v", err)
+			}
+			if transition.Accepted != tt.wantAccepted {
+				t.Errorf("accepted = %v, want %v", transition.Accepted, tt.wantAccepted)
+			}
+			if got := repo.consumers[testConsumerID].KYCStatus; got != tt.wantStatus {
+				t.Errorf("stored status = %q, want %q", got, tt.wantStatus)
+			}
+			if len(repo.transitions) != tt.wantTransitions {
+				t.Errorf("recorded %d transitions, want %d", len(repo.transitions), tt.wantTransitions)
+			}
+		})
+	}
+}
+
+func TestTransitionKYCRejectsInvalidStatuses(t *testing.T) {
+	for _, to := range []banking.KYCStatus{banking.KYCStatusNone, "unknown"} {
+		t.Run(string(to), func(t *testing.T) {
+			repo := newFakeRepository()
+
+			_, err := newTestManager(repo).TransitionKYC(context.Background(), consumerWithStatus(to), to, "evt_1", time.Now())
+			if err == nil {
+				t.Fatal("TransitionKYC() error = nil, want an error")
+			}
+			if len(repo.transitions) != 0 {
+				t.Errorf("recorded %d transitions, want 0", len(repo.transitions))
+			}
+		})
+	}
+}
+
+func TestTransitionKYCDecidesAgainAfterConcurrentChange(t *testing.T) {
+	tests := []struct {
+		name         string
+		concurrent   banking.KYCStatus
+		wantAccepted bool
+		wantFrom     banking.KYCStatus
+		wantStatus   banking.KYCStatus
+	}{
+		{
+			name:         "still allowed from the new status",
+			concurrent:   banking.KYCStatusNeedsReview,
+			wantAccepted: true,
+			wantFrom:     banking.KYCStatusNeedsReview,
+			wantStatus:   banking.KYCStatusApproved,
+		},
+		{
+			name:         "no longer allowed from the new status",
+			concurrent:   banking.KYCStatusFailed,
+			wantAccepted: false,
+			wantFrom:     banking.KYCStatusFailed,
+			wantStatus:   banking.KYCStatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository(consumerWithStatus(banking.KYCStatusPending))
+			repo.concurrent = []banking.KYCStatus{tt.concurrent}
+
+			transition, err := newTestManager(repo).TransitionKYC(
+				context.Background(), consumerWithStatus(banking.KYCStatusApproved), banking.KYCStatusApproved, "evt_1", time.Now())
+			if err != nil {
+				t.Fatalf("TransitionKYC() error = %v", err)
+			}
+			if transition.From != tt.wantFrom {
+				t.Errorf("from = %q, want %q", transition.From, tt.wantFrom)
+			}
+			if transition.Accepted != tt.wantAccepted {
+				t.Errorf("accepted = %v, want %v", transition.Accepted, tt.wantAccepted)
+			}
+			if got := repo.consumers[testConsumerID].KYCStatus; got != tt.wantStatus {
+				t.Errorf("stored status = %q, want %q", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestTransitionKYCGivesUpAfterRepeatedChanges(t *testing.T) {
+	repo := newFakeRepository(consumerWithStatus(banking.KYCStatusStarted))
+	// Every attempt loses the race to another transition
+	repo.concurrent = []banking.KYCStatus{
+		banking.KYCStatusPending,
+		banking.KYCStatusNeedsReview,
+		banking.KYCStatusPending,
+	}
+
+	_, err := newTestManager(repo).TransitionKYC(
+		context.Background(), consumerWithStatus(banking.KYCStatusApproved), banking.KYCStatusApproved, "evt_1", time.Now())
+	if !errors.Is(err, banking.ErrKYCStatusChanged) {
+		t.Fatalf("err = %v, want %v", err, banking.ErrKYCStatusChanged)
+	}
+	if len(repo.transitions) != 0 {
+		t.Errorf("recorded %d transitions, want 0", len(repo.transitions))
+	}
+}
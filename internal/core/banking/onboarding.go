@@ -0,0 +1,150 @@
+package banking
+
+import (
+	"context"
+	"net/http"
+	"template/packages/common-go"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidConsumer = common.AppError{
+		Code:   "INVALID_INPUT",
+		Status: http.StatusBadRequest,
+	}
+	ErrConsumerNotFound = common.AppError{
+		Code:    "NOT_FOUND",
+		Message: "user has no banking consumer",
+		Status:  http.StatusNotFound,
+	}
+	ErrConsumerExists = common.AppError{
+		Code:    "ALREADY_EXISTS",
+		Message: "user already has a banking consumer",
+		Status:  http.StatusConflict,
+	}
+	ErrConsumerClosedAtProvider = common.AppError{
+		Code:    "CONSUMER_CLOSED",
+		Message: "user's banking consumer was closed and cannot be reopened",
+		Status:  http.StatusConflict,
+	}
+)
+
+func (m *consumerManager) CreateUserConsumer(ctx context.Context, userID string, consumer Consumer) (*Consumer, error) {
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+	if consumer.Profile.FirstName == "" || consumer.Profile.LastName == "" || consumer.Profile.Email == "" {
+		return nil, ErrInvalidConsumer.WithMessage("first name, last name and email are required")
+	}
+
+	stored, err := m.repo.GetBankingConsumerByExternalID(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get consumer")
+	}
+	if stored != nil {
+		return nil, ErrConsumerExists
+	}
+
+	// A previous attempt may have created the consumer without storing it
+	created, err := m.client.GetConsumerByExternalID(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to look up consumer")
+	}
+	// The provider keeps the external ID of closed consumers, a new one
+	// cannot be created for the user
+	if created != nil && created.Deleted {
+		return nil, ErrConsumerClosedAtProvider
+	}
+
+	if created == nil {
+		consumer.ExternalID = userID
+		created, err = m.client.CreateConsumer(ctx, consumer)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create consumer")
+		}
+	}
+
+	if err := m.repo.SaveBankingConsumer(ctx, *created); err != nil {
+		return nil, errors.Wrap(err, "failed to save consumer")
+	}
+
+	m.logger.Info("created banking consumer",
+		zap.String("consumerID", created.ID),
+		zap.String("userID", userID))
+
+	return created, nil
+}
+
+func (m *consumerManager) GetUserConsumer(ctx context.Context, userID string) (*Consumer, error) {
+	stored, err := m.userConsumer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	consumer, err := m.client.GetConsumer(ctx, stored.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get consumer")
+	}
+	if consumer == nil {
+		return nil, ErrConsumerNotFound
+	}
+
+	return consumer, nil
+}
+
+func (m *consumerManager) UpdateUserConsumer(ctx context.Context, userID string, profile ConsumerProfile) (*Consumer, error) {
+	stored, err := m.userConsumer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	consumer, err := m.client.UpdateConsumer(ctx, stored.ID, profile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update consumer")
+	}
+
+	if err := m.repo.SaveBankingConsumer(ctx, *consumer); err != nil {
+		return nil, errors.Wrap(err, "failed to save consumer")
+	}
+
+	return consumer, nil
+}
+
+func (m *consumerManager) CloseUserConsumer(ctx context.Context, userID string) error {
+	stored, err := m.userConsumer(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := m.client.CloseConsumer(ctx, stored.ID); err != nil {
+		return errors.Wrap(err, "failed to close consumer")
+	}
+
+	if err := m.CloseConsumer(ctx, *stored); err != nil {
+		return errors.Wrap(err, "failed to save closed consumer")
+	}
+
+	m.logger.Info("closed banking consumer",
+		zap.String("consumerID", stored.ID),
+		zap.String("userID", userID))
+
+	return nil
+}
+
+func (m *consumerManager) userConsumer(ctx context.Context, userID string) (*Consumer, error) {
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	consumer, err := m.repo.GetBankingConsumerByExternalID(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get consumer")
+	}
+	if consumer == nil {
+		return nil, ErrConsumerNotFound
+	}
+
+	return consumer, nil
+}
@@ -1,13 +1,96 @@
 package banking
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type ConsumerManager interface {
-	SaveConsumer(ctx context.Context, consumer Consumer) error
+	// SaveConsumer stores the consumer and returns it as stored. A closed
+	// consumer is never reopened.
+	SaveConsumer(ctx context.Context, consumer Consumer) (Consumer, error)
+	// CloseConsumer stores the consumer as inactive and deleted.
+	CloseConsumer(ctx context.Context, consumer Consumer) error
+	OnboardingManager
+	KYCManager
+	EmbeddedComponentManager
+}
+
+// OnboardingManager drives a user's consumer at the provider. The consumer's
+// external ID is the user ID.
+type OnboardingManager interface {
+	CreateUserConsumer(ctx context.Context, userID string, consumer Consumer) (*Consumer, error)
+	GetUserConsumer(ctx context.Context, userID string) (*Consumer, error)
+	// UpdateUserConsumer changes the non-empty fields of the profile.
+	UpdateUserConsumer(ctx context.Context, userID string, profile ConsumerProfile) (*Consumer, error)
+	CloseUserConsumer(ctx context.Context, userID string) error
+}
+
+type EmbeddedComponentManager interface {
+	// CreateEmbeddedSession mints a short-lived session for the user's own
+	// consumer and returns the URL the component is loaded from.
+	CreateEmbeddedSession(ctx context.Context, userID string, component EmbeddedComponent) (*EmbeddedSession, error)
+}
+
+type KYCManager interface {
+	// TransitionKYC moves the consumer to the given KYC status if the state
+	// machine allows it. Disallowed transitions are recorded as rejected.
+	TransitionKYC(ctx context.Context, consumer Consumer, to KYCStatus, sourceEventID string, occurredAt time.Time) (KYCTransition, error)
+	GetKYCHistory(ctx context.Context, consumerID string) ([]KYCTransition, error)
 }
 
 type Repository interface {
 	SaveBankingConsumer(ctx context.Context, consumer Consumer) error
+	// GetBankingConsumer returns nil when the consumer does not exist.
+	GetBankingConsumer(ctx context.Context, id string) (*Consumer, error)
+	// GetBankingConsumerByExternalID returns nil when the user has no open
+	// consumer.
+	GetBankingConsumerByExternalID(ctx context.Context, externalID string) (*Consumer, error)
+	// SaveKYCTransition records the transition and, if it was accepted, saves
+	// the consumer with its new KYC status. It returns ErrKYCStatusChanged
+	// when the stored status is no longer the transition's From.
+	SaveKYCTransition(ctx context.Context, consumer Consumer, transition KYCTransition) error
+	GetKYCTransitions(ctx context.Context, consumerID string) ([]KYCTransition, error)
+}
+
+// ConsumerClient manages consumers at the banking provider.
+type ConsumerClient interface {
+	CreateConsumer(ctx context.Context, consumer Consumer) (*Consumer, error)
+	// GetConsumer returns nil when the consumer does not exist.
+	GetConsumer(ctx context.Context, id string) (*Consumer, error)
+	// GetConsumerByExternalID returns nil when the consumer does not exist.
+	// It prefers an open consumer and only returns a closed one when no open
+	// consumer exists.
+	GetConsumerByExternalID(ctx context.Context, externalID string) (*Consumer, error)
+	UpdateConsumer(ctx context.Context, id string, profile ConsumerProfile) (*Consumer, error)
+	CloseConsumer(ctx context.Context, id string) error
+	// CreateEmbeddedSession returns a session token valid for the consumer
+	// and component only.
+	CreateEmbeddedSession(ctx context.Context, consumerID string, component EmbeddedComponent, ttl time.Duration) (*EmbeddedSession, error)
 }
 
 type Consumer = consumer
+type ConsumerProfile = consumerProfile
+type Address = address
+type EmbeddedComponent = embeddedComponent
+type EmbeddedSession = embeddedSession
+type KYCStatus = kycStatus
+type KYCTransition = kycTransition
+
+const (
+	KYCStatusNone        kycStatus = ""
+	KYCStatusStarted     kycStatus = "started"
+	KYCStatusPending     kycStatus = "pending"
+	KYCStatusCompleted   kycStatus = "completed"
+	KYCStatusNeedsReview kycStatus = "needs_review"
+	KYCStatusApproved    kycStatus = "approved"
+	KYCStatusFailed      kycStatus = "failed"
+)
+
+const (
+	EmbeddedComponentCard embeddedComponent = "card"
+	EmbeddedComponentKYC  embeddedComponent = "kyc"
+)
+
+// EmbeddedSessionTTL is how long an embedded component session stays valid.
+const EmbeddedSessionTTL = 15 * time.Minute
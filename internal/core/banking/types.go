@@ -1,14 +1,64 @@
 package banking
 
+import "time"
+
+type kycStatus string
+
+type embeddedComponent string
+
 type consumer struct {
 	ID            string
 	PCID          string
 	ExternalID    string
 	IsActive      bool
-	KYCStatus     string
+	KYCStatus     kycStatus
 	TaxIDType     string
 	TaxIdentifier string
 	CreatedAt     string
 	UpdatedAt     string
 	Deleted       bool
+	// Profile is only populated when the consumer is read from the provider.
+	Profile consumerProfile
+}
+
+// consumerProfile holds the personal details the provider keeps for a
+// consumer. They are not stored locally.
+type consumerProfile struct {
+	FirstName   string
+	LastName    string
+	Email       string
+	PhoneNumber string
+	DateOfBirth string
+	Address     address
+}
+
+type address struct {
+	Line1 string
+	Line2 string
+	City  string
+	State string
+	Zip   string
+}
+
+type kycTransition struct {
+	ConsumerID    string
+	From          kycStatus
+	To            kycStatus
+	SourceEventID string
+	// Accepted is false for out-of-order transitions, which are recorded but
+	// not applied to the consumer.
+	Accepted   bool
+	Reason     string
+	OccurredAt time.Time
+	CreatedAt  time.Time
+}
+
+// embeddedSession lets the frontend load a provider UI component scoped to a
+// single consumer until ExpiresAt.
+type embeddedSession struct {
+	ConsumerID string
+	Component  embeddedComponent
+	Token      string
+	URL        string
+	ExpiresAt  time.Time
 }
@@ -0,0 +1,46 @@
+package cards
+
+import "context"
+
+type Repository interface {
+	SaveCard(ctx context.Context, card Card) error
+	// GetCard returns nil when the card does not exist.
+	GetCard(ctx context.Context, id string) (*Card, error)
+	GetCardsByConsumer(ctx context.Context, consumerID string) ([]Card, error)
+	// GetCardsByUser lists the cards of the consumer whose external ID is the
+	// given user ID.
+	GetCardsByUser(ctx context.Context, userID string) ([]Card, error)
+	SaveCardTransaction(ctx context.Context, transaction Transaction) error
+	GetCardTransactions(ctx context.Context, cardID string) ([]Transaction, error)
+}
+
+type Service interface {
+	// SaveCard stores the card and returns it as stored. A closed card is
+	// never reopened.
+	SaveCard(ctx context.Context, card Card) (Card, error)
+	CloseCard(ctx context.Context, card Card) error
+	RecordSettlement(ctx context.Context, transaction Transaction) error
+	GetConsumerCards(ctx context.Context, consumerID string) ([]Card, error)
+	GetCardTransactions(ctx context.Context, cardID string) ([]Transaction, error)
+	GetUserCards(ctx context.Context, userID string) ([]Card, error)
+	// GetUserCardTransactions returns ErrCardNotFound when the card does not
+	// belong to the user.
+	GetUserCardTransactions(ctx context.Context, userID string, cardID string) ([]Transaction, error)
+}
+
+type Card = card
+type CardStatus = cardStatus
+type CardType = cardType
+type Transaction = transaction
+
+const (
+	CardStatusActive   cardStatus = "active"
+	CardStatusInactive cardStatus = "inactive"
+	CardStatusLocked   cardStatus = "locked"
+	CardStatusClosed   cardStatus = "closed"
+)
+
+const (
+	CardTypeVirtual  cardType = "virtual"
+	CardTypePhysical cardType = "physical"
+)
@@ -0,0 +1,181 @@
+package cards
+
+import (
+	"context"
+	"net/http"
+	banking "template/internal/core/banking"
+	"template/internal/logger"
+	"template/packages/common-go"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var ErrCardNotFound = common.AppError{
+	Code:    "NOT_FOUND",
+	Message: "card not found",
+	Status:  http.StatusNotFound,
+}
+
+type service struct {
+	logger    logger.Logger
+	repo      Repository
+	consumers banking.Repository
+}
+
+func NewService(logger logger.Logger, repo Repository, consumers banking.Repository) Service {
+	if logger == nil {
+		return nil
+	}
+
+	return &service{
+		logger:    logger,
+		repo:      repo,
+		consumers: consumers,
+	}
+}
+
+func (s *service) SaveCard(ctx context.Context, card Card) (Card, error) {
+	if card.ID == "" {
+		return Card{}, errors.New("card ID is required")
+	}
+	if card.ConsumerID == "" {
+		return Card{}, errors.New("consumer ID is required")
+	}
+
+	stored, err := s.repo.GetCard(ctx, card.ID)
+	if err != nil {
+		return Card{}, errors.Wrap(err, "failed to get card")
+	}
+
+	// Updates can arrive after the card was closed, the upsert keeps it
+	// closed too
+	if stored != nil && stored.Status == CardStatusClosed {
+		card.Status = CardStatusClosed
+		card.ClosedAt = stored.ClosedAt
+	}
+
+	if err := s.repo.SaveCard(ctx, card); err != nil {
+		return Card{}, errors.Wrap(err, "failed to save card")
+	}
+
+	return card, nil
+}
+
+func (s *service) CloseCard(ctx context.Context, card Card) error {
+	card.Status = CardStatusClosed
+	if card.ClosedAt == nil {
+		now := time.Now()
+		card.ClosedAt = &now
+	}
+
+	if _, err := s.SaveCard(ctx, card); err != nil {
+		return err
+	}
+
+	s.logger.Info("closed card",
+		zap.String("cardID", card.ID),
+		zap.String("consumerID", card.ConsumerID))
+
+	return nil
+}
+
+func (s *service) RecordSettlement(ctx context.Context, transaction Transaction) error {
+	if transaction.ID == "" {
+		return errors.New("transaction ID is required")
+	}
+	if transaction.CardID == "" {
+		return errors.New("card ID is required")
+	}
+
+	if transaction.ConsumerID == "" {
+		card, err := s.repo.GetCard(ctx, transaction.CardID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get card")
+		}
+		if card == nil {
+			return errors.Errorf("card %s not found for transaction %s", transaction.CardID, transaction.ID)
+		}
+		transaction.ConsumerID = card.ConsumerID
+	}
+
+	if err := s.repo.SaveCardTransaction(ctx, transaction); err != nil {
+		return errors.Wrap(err, "failed to save settled transaction")
+	}
+
+	return nil
+}
+
+func (s *service) GetConsumerCards(ctx context.Context, consumerID string) ([]Card, error) {
+	if consumerID == "" {
+		return nil, errors.New("consumer ID is required")
+	}
+
+	cards, err := s.repo.GetCardsByConsumer(ctx, consumerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cards")
+	}
+
+	return cards, nil
+}
+
+func (s *service) GetCardTransactions(ctx context.Context, cardID string) ([]Transaction, error) {
+	if cardID == "" {
+		return nil, errors.New("card ID is required")
+	}
+
+	transactions, err := s.repo.GetCardTransactions(ctx, cardID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get card transactions")
+	}
+
+	return transactions, nil
+}
+
+func (s *service) GetUserCards(ctx context.Context, userID string) ([]Card, error) {
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	cards, err := s.repo.GetCardsByUser(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cards")
+	}
+
+	return cards, nil
+}
+
+func (s *service) GetUserCardTransactions(ctx context.Context, userID string, cardID string) ([]Transaction, error) {
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	if err := s.checkOwner(ctx, userID, cardID); err != nil {
+		return nil, err
+	}
+
+	return s.GetCardTransactions(ctx, cardID)
+}
+
+// checkOwner makes sure the card belongs to the user's consumer. Cards of
+// other users are reported as not found.
+func (s *service) checkOwner(ctx context.Context, userID, cardID string) error {
+	card, err := s.repo.GetCard(ctx, cardID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get card")
+	}
+	if card == nil {
+		return ErrCardNotFound
+	}
+
+	consumer, err := s.consumers.GetBankingConsumer(ctx, card.ConsumerID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get consumer")
+	}
+	if consumer == nil || consumer.ExternalID != userID {
+		return ErrCardNotFound
+	}
+
+	return nil
+}
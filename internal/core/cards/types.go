@@ -0,0 +1,36 @@
+package cards
+
+import "time"
+
+// Internal types
+type cardStatus string
+
+type cardType string
+
+type card struct {
+	ID              string
+	ConsumerID      string
+	LastFour        string
+	Type            cardType
+	Status          cardStatus
+	ExpirationMonth int
+	ExpirationYear  int
+	ClosedAt        *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// transaction is a settled card transaction. Amounts are in minor units
+// (cents) of Currency.
+type transaction struct {
+	ID                   string
+	CardID               string
+	ConsumerID           string
+	Amount               int64
+	Currency             string
+	MerchantName         string
+	MerchantCategoryCode string
+	Description          string
+	SettledAt            time.Time
+	CreatedAt            time.Time
+}
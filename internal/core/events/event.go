@@ -0,0 +1,50 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// NewEvent builds the event published for a provider webhook. The ID is
+// derived from the source event, so reprocessing a webhook publishes the same
+// event again, which the hub and subscribers deduplicate on.
+func NewEvent(eventType EventType, source string, sourceEventID string, occurredAt time.Time, data any) (Event, error) {
+	if sourceEventID == "" {
+		return Event{}, errors.New("source event ID is required")
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, errors.Wrapf(err, "failed to marshal %s event data", eventType)
+	}
+
+	return Event{
+		ID:            uuid.NewSHA1(uuid.NameSpaceURL, []byte(source+"/"+sourceEventID+"/"+string(eventType))).String(),
+		Type:          eventType,
+		Source:        source,
+		SourceEventID: sourceEventID,
+		OccurredAt:    occurredAt,
+		Data:          payload,
+	}, nil
+}
+
+func (s Subscriber) subscribes(eventType EventType) bool {
+	for _, subscribed := range s.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s Subscriber) retryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: s.MaxRetries,
+		BaseDelay:  s.BaseRetryDelay,
+		MaxDelay:   s.MaxRetryDelay,
+	}
+}
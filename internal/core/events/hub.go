@@ -0,0 +1,297 @@
+package events
+
+import (
+	"context"
+	"template/internal/logger"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var errSubscriberInactive = errors.New("subscriber is inactive")
+
+// resumeAfter leaves deliveries another instance updated recently to that
+// instance when resuming
+const resumeAfter = 5 * time.Minute
+
+type hub struct {
+	logger     logger.Logger
+	repo       Repository
+	sender     Sender
+	dispatcher Dispatcher
+}
+
+func NewHub(logger logger.Logger, repo Repository, sender Sender, dispatcher Dispatcher) Hub {
+	if logger == nil {
+		return nil
+	}
+
+	return &hub{
+		logger:     logger,
+		repo:       repo,
+		sender:     sender,
+		dispatcher: dispatcher,
+	}
+}
+
+func (h *hub) Publish(ctx context.Context, event Event) error {
+	subscribers, err := h.repo.GetActiveSubscribers(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get event subscribers")
+	}
+
+	for _, subscriber := range subscribers {
+		if !subscriber.subscribes(event.Type) {
+			continue
+		}
+
+		delivery := Delivery{
+			ID:           uuid.New().String(),
+			SubscriberID: subscriber.ID,
+			Event:        event,
+			Status:       DeliveryStatusPending,
+		}
+
+		created, err := h.repo.SaveDelivery(ctx, delivery)
+		if err != nil {
+			return errors.Wrapf(err, "failed to save delivery to subscriber %s", subscriber.ID)
+		}
+		if !created {
+			continue
+		}
+
+		// The delivery is stored, so a dispatch failure is deferred to the
+		// next sweep and must not fail the publish.
+		if err := h.dispatcher.Dispatch(ctx, delivery, subscriber.retryPolicy(), h); err != nil {
+			h.deferDelivery(ctx, delivery, err)
+		}
+	}
+
+	return nil
+}
+
+func (h *hub) Resume(ctx context.Context) error {
+	deliveries, err := h.repo.GetDeliveriesByStatus(ctx, []DeliveryStatus{DeliveryStatusPending, DeliveryStatusRetrying})
+	if err != nil {
+		return errors.Wrap(err, "failed to list unfinished event deliveries")
+	}
+
+	updatedBefore := time.Now().Add(-resumeAfter)
+	policies := map[string]RetryPolicy{}
+	resumed, failed := 0, 0
+	for _, delivery := range deliveries {
+		// Claiming the delivery first keeps other instances from resuming it
+		// too
+		claimed, err := h.repo.ClaimStaleDelivery(ctx, delivery.ID, delivery.Status, updatedBefore)
+		if err != nil {
+			return errors.Wrapf(err, "failed to claim event delivery %s", delivery.ID)
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := h.dispatch(ctx, delivery, policies); err != nil {
+			h.deferDelivery(ctx, delivery, err)
+			failed++
+			continue
+		}
+		resumed++
+	}
+
+	h.logger.Info("resumed unfinished event deliveries",
+		zap.Int("count", resumed),
+		zap.Int("deferred", failed))
+
+	if failed > 0 {
+		return errors.Errorf("failed to dispatch %d of %d event deliveries", failed, resumed+failed)
+	}
+
+	return nil
+}
+
+func (h *hub) Sweep(ctx context.Context) (int, error) {
+	deliveries, err := h.repo.GetDeliveriesByStatus(ctx, []DeliveryStatus{DeliveryStatusDeferred})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list deferred event deliveries")
+	}
+
+	policies := map[string]RetryPolicy{}
+	dispatched := 0
+	for _, delivery := range deliveries {
+		delivery.Status = DeliveryStatusPending
+		if delivery.Attempts > 0 {
+			delivery.Status = DeliveryStatusRetrying
+		}
+
+		// Claiming the delivery first keeps other instances from dispatching
+		// it too
+		claimed, err := h.repo.ClaimDelivery(ctx, delivery.ID, DeliveryStatusDeferred, delivery.Status)
+		if err != nil {
+			return dispatched, errors.Wrapf(err, "failed to claim event delivery %s", delivery.ID)
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := h.dispatch(ctx, delivery, policies); err != nil {
+			h.deferDelivery(ctx, delivery, err)
+			continue
+		}
+		dispatched++
+	}
+
+	return dispatched, nil
+}
+
+// dispatch queues a stored delivery with the retry policy of its subscriber.
+// policies caches the policies already looked up.
+func (h *hub) dispatch(ctx context.Context, delivery Delivery, policies map[string]RetryPolicy) error {
+	policy, ok := policies[delivery.SubscriberID]
+	if !ok {
+		subscriber, err := h.repo.GetSubscriber(ctx, delivery.SubscriberID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get subscriber %s", delivery.SubscriberID)
+		}
+
+		// Deliver fails the deliveries of a removed subscriber on their first
+		// attempt, so its policy doesn't matter
+		policy = RetryPolicy{MaxRetries: DefaultMaxRetries, BaseDelay: DefaultBaseRetryDelay, MaxDelay: DefaultMaxRetryDelay}
+		if subscriber != nil {
+			policy = subscriber.retryPolicy()
+		}
+		policies[delivery.SubscriberID] = policy
+	}
+
+	return h.dispatcher.Dispatch(ctx, delivery, policy, h)
+}
+
+func (h *hub) Deliver(ctx context.Context, delivery Delivery) error {
+	subscriber, err := h.repo.GetSubscriber(ctx, delivery.SubscriberID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get event subscriber")
+	}
+
+	// Claiming the attempt keeps a delivery that is queued twice, e.g. by a
+	// resume during a rolling deploy, from being sent twice
+	claimed, err := h.repo.ClaimDeliveryAttempt(ctx, delivery.ID, delivery.Attempts)
+	if err != nil {
+		return errors.Wrap(err, "failed to claim event delivery attempt")
+	}
+	if !claimed {
+		h.logger.Debug("skipping event delivery finished or attempted elsewhere",
+			zap.String("deliveryID", delivery.ID),
+			zap.Int("attempts", delivery.Attempts))
+		return nil
+	}
+
+	// Retrying can't bring back a removed subscriber, so the delivery is
+	// failed right away instead of using up its retries. Failing it stores
+	// the attempts actually made.
+	if subscriber == nil || !subscriber.Active {
+		return h.Fail(ctx, delivery, errSubscriberInactive)
+	}
+
+	delivery.Attempts++
+	start := time.Now()
+	statusCode, sendErr := h.sender.Send(ctx, *subscriber, delivery.Event)
+
+	attempt := DeliveryAttempt{
+		DeliveryID:  delivery.ID,
+		Attempt:     delivery.Attempts,
+		StatusCode:  statusCode,
+		Duration:    time.Since(start),
+		AttemptedAt: start,
+	}
+	delivery.LastStatusCode = statusCode
+
+	if sendErr != nil {
+		h.logger.Warn("failed to deliver event",
+			zap.Error(sendErr),
+			zap.String("deliveryID", delivery.ID),
+			zap.String("subscriberID", subscriber.ID),
+			zap.String("eventType", string(delivery.Event.Type)),
+			zap.Int("statusCode", statusCode),
+			zap.Int("attempts", delivery.Attempts))
+
+		lastError := sendErr.Error()
+		attempt.Error = &lastError
+		delivery.Status = DeliveryStatusRetrying
+		delivery.LastError = &lastError
+	} else {
+		now := time.Now()
+		delivery.Status = DeliveryStatusDelivered
+		delivery.LastError = nil
+		delivery.DeliveredAt = &now
+	}
+
+	if err := h.repo.SaveDeliveryAttempt(ctx, attempt); err != nil {
+		h.logger.Error("failed to log event delivery attempt",
+			zap.Error(err),
+			zap.String("deliveryID", delivery.ID),
+			zap.Int("attempt", attempt.Attempt))
+	}
+
+	if updateErr := h.repo.UpdateDeliveryStatus(ctx, delivery); updateErr != nil {
+		h.logger.Error("failed to update event delivery status",
+			zap.Error(updateErr),
+			zap.String("deliveryID", delivery.ID),
+			zap.String("status", string(delivery.Status)))
+		if sendErr == nil {
+			return errors.Wrap(updateErr, "failed to update event delivery status")
+		}
+	}
+
+	return sendErr
+}
+
+func (h *hub) Fail(ctx context.Context, delivery Delivery, cause error) error {
+	lastError := cause.Error()
+	delivery.Status = DeliveryStatusFailed
+	delivery.LastError = &lastError
+
+	if err := h.repo.UpdateDeliveryStatus(ctx, delivery); err != nil {
+		return errors.Wrap(err, "failed to fail event delivery")
+	}
+
+	h.logger.Error("event delivery failed",
+		zap.Error(cause),
+		zap.String("deliveryID", delivery.ID),
+		zap.String("subscriberID", delivery.SubscriberID),
+		zap.String("eventType", string(delivery.Event.Type)),
+		zap.Int("attempts", delivery.Attempts))
+
+	return nil
+}
+
+func (h *hub) Defer(ctx context.Context, delivery Delivery, cause error) error {
+	lastError := cause.Error()
+	delivery.Status = DeliveryStatusDeferred
+	delivery.LastError = &lastError
+
+	if err := h.repo.UpdateDeliveryStatus(ctx, delivery); err != nil {
+		return errors.Wrap(err, "failed to defer event delivery")
+	}
+
+	h.logger.Warn("event delivery deferred to the next sweep",
+		zap.Error(cause),
+		zap.String("deliveryID", delivery.ID),
+		zap.String("subscriberID", delivery.SubscriberID),
+		zap.String("eventType", string(delivery.Event.Type)),
+		zap.Int("attempts", delivery.Attempts))
+
+	return nil
+}
+
+// deferDelivery defers a delivery that could not be dispatched, logging when
+// even that fails. The delivery then stays pending until the next Resume.
+func (h *hub) deferDelivery(ctx context.Context, delivery Delivery, cause error) {
+	if err := h.Defer(ctx, delivery, cause); err != nil {
+		h.logger.Error("failed to dispatch event delivery",
+			zap.Error(cause),
+			zap.NamedError("deferError", err),
+			zap.String("deliveryID", delivery.ID),
+			zap.String("subscriberID", delivery.SubscriberID))
+	}
+}
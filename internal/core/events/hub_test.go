@@ -0,0 +1,507 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	events "template/internal/core/events"
+	"template/internal/logger"
+)
+
+// fakeRepository stores subscribers and deliveries in memory. Deliveries are
+// unique per subscriber and event, as in the database.
+type fakeRepository struct {
+	subscribers []events.Subscriber
+	deliveries  map[string]events.Delivery
+	order       []string
+	attempts    []events.DeliveryAttempt
+}
+
+func newFakeRepository(subscribers ...events.Subscriber) *fakeRepository {
+	return &fakeRepository{
+		subscribers: subscribers,
+		deliveries:  map[string]events.Delivery{},
+	}
+}
+
+func (r *fakeRepository) SaveSubscriber(ctx context.Context, subscriber events.Subscriber) error {
+	r.subscribers = append(r.subscribers, subscriber)
+	return nil
+}
+
+func (r *fakeRepository) GetSubscriber(ctx context.Context, id string) (*events.Subscriber, error) {
+	for _, subscriber := range r.subscribers {
+		if subscriber.ID == id {
+			return &subscriber, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRepository) GetSubscribers(ctx context.Context) ([]events.Subscriber, error) {
+	return r.subscribers, nil
+}
+
+func (r *fakeRepository) GetActiveSubscribers(ctx context.Context) ([]events.Subscriber, error) {
+	var active []events.Subscriber
+	for _, subscriber := range r.subscribers {
+		if subscriber.Active {
+			active = append(active, subscriber)
+		}
+	}
+	return active, nil
+}
+
+func (r *fakeRepository) SetSubscriberActive(ctx context.Context, id string, active bool) error {
+	return nil
+}
+
+func (r *fakeRepository) SaveDelivery(ctx context.Context, delivery events.Delivery) (bool, error) {
+	for _, stored := range r.deliveries {
+		if stored.SubscriberID == delivery.SubscriberID && stored.Event.ID == delivery.Event.ID {
+			return false, nil
+		}
+	}
+
+	r.deliveries[delivery.ID] = delivery
+	r.order = append(r.order, delivery.ID)
+	return true, nil
+}
+
+func (r *fakeRepository) GetDelivery(ctx context.Context, id string) (*events.Delivery, error) {
+	delivery, ok := r.deliveries[id]
+	if !ok {
+		return nil, nil
+	}
+	return &delivery, nil
+}
+
+func (r *fakeRepository) GetDeliveries(ctx context.Context, filter events.DeliveryFilter) ([]events.Delivery, error) {
+	return r.GetDeliveriesByStatus(ctx, []events.DeliveryStatus{filter.Status})
+}
+
+func (r *fakeRepository) GetDeliveriesByStatus(ctx context.Context, statuses []events.DeliveryStatus) ([]events.Delivery, error) {
+	var deliveries []events.Delivery
+	for _, status := range statuses {
+		for _, id := range r.order {
+			if r.deliveries[id].Status == status {
+				deliveries = append(deliveries, r.deliveries[id])
+			}
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *fakeRepository) UpdateDeliveryStatus(ctx context.Context, delivery events.Delivery) error {
+	r.deliveries[delivery.ID] = delivery
+	return nil
+}
+
+func (r *fakeRepository) ClaimDelivery(ctx context.Context, id string, from, to events.DeliveryStatus) (bool, error) {
+	delivery, ok := r.deliveries[id]
+	if !ok || delivery.Status != from {
+		return false, nil
+	}
+
+	delivery.Status = to
+	r.deliveries[id] = delivery
+	return true, nil
+}
+
+func (r *fakeRepository) ClaimStaleDelivery(ctx context.Context, id string, status events.DeliveryStatus, updatedBefore time.Time) (bool, error) {
+	delivery, ok := r.deliveries[id]
+	if !ok || delivery.Status != status || !delivery.UpdatedAt.Before(updatedBefore) {
+		return false, nil
+	}
+
+	delivery.UpdatedAt = time.Now()
+	r.deliveries[id] = delivery
+	return true, nil
+}
+
+func (r *fakeRepository) ClaimDeliveryAttempt(ctx context.Context, id string, attempts int) (bool, error) {
+	delivery, ok := r.deliveries[id]
+	if !ok || delivery.Attempts != attempts {
+		return false, nil
+	}
+	if delivery.Status != events.DeliveryStatusPending && delivery.Status != events.DeliveryStatusRetrying {
+		return false, nil
+	}
+
+	delivery.Attempts++
+	r.deliveries[id] = delivery
+	return true, nil
+}
+
+func (r *fakeRepository) SaveDeliveryAttempt(ctx context.Context, attempt events.DeliveryAttempt) error {
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
+
+func (r *fakeRepository) GetDeliveryAttempts(ctx context.Context, deliveryID string) ([]events.DeliveryAttempt, error) {
+	return r.attempts, nil
+}
+
+// fakeSender fails the first failures sends.
+type fakeSender struct {
+	failures int
+	sent     []string
+}
+
+func (s *fakeSender) Send(ctx context.Context, subscriber events.Subscriber, event events.Event) (int, error) {
+	if s.failures > 0 {
+		s.failures--
+		return 503, errors.New("subscriber unavailable")
+	}
+
+	s.sent = append(s.sent, subscriber.ID+"/"+event.ID)
+	return 200, nil
+}
+
+func (s *fakeSender) Configured() bool {
+	return true
+}
+
+type dispatched struct {
+	delivery events.Delivery
+	policy   events.RetryPolicy
+	handler  events.DeliveryHandler
+}
+
+// fakeDispatcher queues deliveries until run and fails the first failures
+// dispatches.
+type fakeDispatcher struct {
+	failures int
+	queued   []dispatched
+}
+
+func (d *fakeDispatcher) Dispatch(ctx context.Context, delivery events.Delivery, policy events.RetryPolicy, handler events.DeliveryHandler) error {
+	if d.failures > 0 {
+		d.failures--
+		return errors.New("job queue is full")
+	}
+
+	d.queued = append(d.queued, dispatched{delivery: delivery, policy: policy, handler: handler})
+	return nil
+}
+
+// run delivers the queued deliveries the way the scheduler does, retrying
+// each one from its stored attempts until the policy is used up.
+func (d *fakeDispatcher) run(t *testing.T) {
+	t.Helper()
+	ctx := context.Background()
+
+	for _, job := range d.queued {
+		for retryCount := job.delivery.Attempts; ; retryCount++ {
+			delivery := job.delivery
+			delivery.Attempts = retryCount
+
+			err := job.handler.Deliver(ctx, delivery)
+			if err == nil {
+				break
+			}
+			if retryCount < job.policy.MaxRetries {
+				continue
+			}
+
+			delivery.Attempts++
+			if err := job.handler.Fail(ctx, delivery, err); err != nil {
+				t.Fatalf("fail: %v", err)
+			}
+			break
+		}
+	}
+	d.queued = nil
+}
+
+func newTestSubscriber(id string, maxRetries int, eventTypes ...events.EventType) events.Subscriber {
+	return events.Subscriber{
+		ID:             id,
+		Name:           id,
+		URL:            "https://" + id + ".internal/events",
+		EventTypes:     eventTypes,
+		MaxRetries:     maxRetries,
+		BaseRetryDelay: time.Second,
+		MaxRetryDelay:  time.Minute,
+		Active:         true,
+	}
+}
+
+func newTestEvent(t *testing.T, eventType events.EventType, sourceEventID string) events.Event {
+	t.Helper()
+
+	event, err := events.NewEvent(eventType, "upwardli", sourceEventID, time.Now(), map[string]string{"id": sourceEventID})
+	if err != nil {
+		t.Fatalf("new event: %v", err)
+	}
+	return event
+}
+
+func TestPublishDeliversToSubscribers(t *testing.T) {
+	ctx := context.Background()
+	cards := newTestSubscriber("cards", 3, events.EventTypeCardCreated)
+	consumers := newTestSubscriber("consumers", 3, events.EventTypeConsumerCreated)
+	inactive := newTestSubscriber("inactive", 3, events.EventTypeCardCreated)
+	inactive.Active = false
+
+	repo := newFakeRepository(cards, consumers, inactive)
+	sender := &fakeSender{}
+	dispatcher := &fakeDispatcher{}
+	hub := events.NewHub(&logger.NoOpLogger{}, repo, sender, dispatcher)
+
+	event := newTestEvent(t, events.EventTypeCardCreated, "evt_1")
+	if err := hub.Publish(ctx, event); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	if len(dispatcher.queued) != 1 {
+		t.Fatalf("dispatched %d deliveries, want 1", len(dispatcher.queued))
+	}
+	want := events.RetryPolicy{MaxRetries: 3, BaseDelay: time.Second, MaxDelay: time.Minute}
+	if got := dispatcher.queued[0].policy; got != want {
+		t.Errorf("policy = %+v, want %+v", got, want)
+	}
+
+	dispatcher.run(t)
+
+	if len(sender.sent) != 1 || sender.sent[0] != "cards/"+event.ID {
+		t.Errorf("sent = %v, want [cards/%s]", sender.sent, event.ID)
+	}
+	delivered, _ := repo.GetDeliveriesByStatus(ctx, []events.DeliveryStatus{events.DeliveryStatusDelivered})
+	if len(delivered) != 1 || delivered[0].Attempts != 1 {
+		t.Errorf("delivered = %+v, want 1 delivery after 1 attempt", delivered)
+	}
+}
+
+func TestPublishSkipsDuplicateEvents(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepository(newTestSubscriber("cards", 3, events.EventTypeCardCreated))
+	dispatcher := &fakeDispatcher{}
+	hub := events.NewHub(&logger.NoOpLogger{}, repo, &fakeSender{}, dispatcher)
+
+	// A reprocessed webhook publishes an event with the same ID
+	for i := 0; i < 2; i++ {
+		if err := hub.Publish(ctx, newTestEvent(t, events.EventTypeCardCreated, "evt_1")); err != nil {
+			t.Fatalf("publish %d: %v", i, err)
+		}
+	}
+	if err := hub.Publish(ctx, newTestEvent(t, events.EventTypeCardCreated, "evt_2")); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	if len(dispatcher.queued) != 2 {
+		t.Errorf("dispatched %d deliveries, want 2", len(dispatcher.queued))
+	}
+	if len(repo.deliveries) != 2 {
+		t.Errorf("stored %d deliveries, want 2", len(repo.deliveries))
+	}
+}
+
+func TestDeliveryRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxRetries   int
+		failures     int
+		wantStatus   events.DeliveryStatus
+		wantAttempts int
+	}{
+		{name: "first attempt", maxRetries: 3, failures: 0, wantStatus: events.DeliveryStatusDelivered, wantAttempts: 1},
+		{name: "after retries", maxRetries: 3, failures: 2, wantStatus: events.DeliveryStatusDelivered, wantAttempts: 3},
+		{name: "on the last retry", maxRetries: 3, failures: 3, wantStatus: events.DeliveryStatusDelivered, wantAttempts: 4},
+		{name: "retries exhausted", maxRetries: 2, failures: 5, wantStatus: events.DeliveryStatusFailed, wantAttempts: 3},
+		{name: "no retries", maxRetries: 0, failures: 1, wantStatus: events.DeliveryStatusFailed, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newFakeRepository(newTestSubscriber("cards", tt.maxRetries, events.EventTypeCardCreated))
+			dispatcher := &fakeDispatcher{}
+			hub := events.NewHub(&logger.NoOpLogger{}, repo, &fakeSender{failures: tt.failures}, dispatcher)
+
+			if err := hub.Publish(ctx, newTestEvent(t, events.EventTypeCardCreated, "evt_1")); err != nil {
+				t.Fatalf("publish: %v", err)
+			}
+			dispatcher.run(t)
+
+			delivery := repo.deliveries[repo.order[0]]
+			if delivery.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", delivery.Status, tt.wantStatus)
+			}
+			if delivery.Attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", delivery.Attempts, tt.wantAttempts)
+			}
+			if len(repo.attempts) != tt.wantAttempts {
+				t.Errorf("logged %d attempts, want %d", len(repo.attempts), tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryKeepsAttemptsAcrossResume(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepository(newTestSubscriber("cards", 3, events.EventTypeCardCreated))
+	sender := &fakeSender{failures: 10}
+	dispatcher := &fakeDispatcher{}
+	hub := events.NewHub(&logger.NoOpLogger{}, repo, sender, dispatcher)
+
+	if err := hub.Publish(ctx, newTestEvent(t, events.EventTypeCardCreated, "evt_1")); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	// Two attempts are made before the service restarts
+	job := dispatcher.queued[0]
+	for attempts := 0; attempts < 2; attempts++ {
+		delivery := job.delivery
+		delivery.Attempts = attempts
+		if err := hub.Deliver(ctx, delivery); err == nil {
+			t.Fatal("deliver succeeded, want an error")
+		}
+	}
+	dispatcher.queued = nil
+
+	if err := hub.Resume(ctx); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if got := dispatcher.queued[0].delivery.Attempts; got != 2 {
+		t.Errorf("resumed with %d attempts, want 2", got)
+	}
+
+	dispatcher.run(t)
+
+	delivery := repo.deliveries[repo.order[0]]
+	if delivery.Status != events.DeliveryStatusFailed || delivery.Attempts != 4 {
+		t.Errorf("delivery = %s after %d attempts, want failed after 4", delivery.Status, delivery.Attempts)
+	}
+}
+
+func TestUndispatchedDeliveriesAreSwept(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepository(newTestSubscriber("cards", 3, events.EventTypeCardCreated))
+	sender := &fakeSender{}
+	dispatcher := &fakeDispatcher{failures: 1}
+	hub := events.NewHub(&logger.NoOpLogger{}, repo, sender, dispatcher)
+
+	// The publish succeeds, since the delivery is stored
+	if err := hub.Publish(ctx, newTestEvent(t, events.EventTypeCardCreated, "evt_1")); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if status := repo.deliveries[repo.order[0]].Status; status != events.DeliveryStatusDeferred {
+		t.Fatalf("status = %s, want %s", status, events.DeliveryStatusDeferred)
+	}
+
+	swept, err := hub.Sweep(ctx)
+	if err != nil {
+		t.Fatalf("sweep: %v", err)
+	}
+	if swept != 1 {
+		t.Errorf("swept %d deliveries, want 1", swept)
+	}
+
+	// A second sweep finds nothing left to claim
+	if swept, _ := hub.Sweep(ctx); swept != 0 {
+		t.Errorf("second sweep dispatched %d deliveries, want 0", swept)
+	}
+
+	dispatcher.run(t)
+
+	if status := repo.deliveries[repo.order[0]].Status; status != events.DeliveryStatusDelivered {
+		t.Errorf("status = %s, want %s", status, events.DeliveryStatusDelivered)
+	}
+}
+
+func TestResumeContinuesPastDispatchFailures(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepository(newTestSubscriber("cards", 3, events.EventTypeCardCreated))
+	dispatcher := &fakeDispatcher{}
+	hub := events.NewHub(&logger.NoOpLogger{}, repo, &fakeSender{}, dispatcher)
+
+	for _, id := range []string{"evt_1", "evt_2", "evt_3"} {
+		if err := hub.Publish(ctx, newTestEvent(t, events.EventTypeCardCreated, id)); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+	dispatcher.queued = nil
+	dispatcher.failures = 1
+
+	if err := hub.Resume(ctx); err == nil {
+		t.Error("resume succeeded, want an error for the failed dispatch")
+	}
+
+	if len(dispatcher.queued) != 2 {
+		t.Errorf("resumed %d deliveries, want 2", len(dispatcher.queued))
+	}
+	if status := repo.deliveries[repo.order[0]].Status; status != events.DeliveryStatusDeferred {
+		t.Errorf("status = %s, want %s", status, events.DeliveryStatusDeferred)
+	}
+}
+
+func TestResumeClaimsDeliveries(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepository(newTestSubscriber("cards", 3, events.EventTypeCardCreated))
+	dispatcher := &fakeDispatcher{}
+	hub := events.NewHub(&logger.NoOpLogger{}, repo, &fakeSender{}, dispatcher)
+
+	for _, id := range []string{"evt_1", "evt_2"} {
+		if err := hub.Publish(ctx, newTestEvent(t, events.EventTypeCardCreated, id)); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+	dispatcher.queued = nil
+
+	// The second delivery was just updated by the instance working on it
+	recent := repo.deliveries[repo.order[1]]
+	recent.UpdatedAt = time.Now()
+	repo.deliveries[recent.ID] = recent
+
+	// Two instances start at the same time during a rolling deploy
+	other := &fakeDispatcher{}
+	otherHub := events.NewHub(&logger.NoOpLogger{}, repo, &fakeSender{}, other)
+	if err := hub.Resume(ctx); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if err := otherHub.Resume(ctx); err != nil {
+		t.Fatalf("resume on the other instance: %v", err)
+	}
+
+	if len(dispatcher.queued) != 1 || dispatcher.queued[0].delivery.ID != repo.order[0] {
+		t.Errorf("resumed %d deliveries, want only the stale one", len(dispatcher.queued))
+	}
+	if len(other.queued) != 0 {
+		t.Errorf("other instance resumed %d deliveries, want 0", len(other.queued))
+	}
+}
+
+func TestDeliverSkipsUnclaimableDeliveries(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepository(newTestSubscriber("cards", 3, events.EventTypeCardCreated))
+	sender := &fakeSender{}
+	dispatcher := &fakeDispatcher{}
+	hub := events.NewHub(&logger.NoOpLogger{}, repo, sender, dispatcher)
+
+	if err := hub.Publish(ctx, newTestEvent(t, events.EventTypeCardCreated, "evt_1")); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	// The delivery ends up queued twice, e.g. on two instances
+	dispatcher.queued = append(dispatcher.queued, dispatcher.queued[0])
+	dispatcher.run(t)
+
+	if len(sender.sent) != 1 {
+		t.Errorf("sent %d times, want once", len(sender.sent))
+	}
+
+	// A delivered delivery is not sent again
+	if err := hub.Deliver(ctx, repo.deliveries[repo.order[0]]); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if len(sender.sent) != 1 {
+		t.Errorf("sent %d times after delivering again, want once", len(sender.sent))
+	}
+	delivery := repo.deliveries[repo.order[0]]
+	if delivery.Status != events.DeliveryStatusDelivered || delivery.Attempts != 1 {
+		t.Errorf("delivery = %s after %d attempts, want delivered after 1", delivery.Status, delivery.Attempts)
+	}
+}
@@ -0,0 +1,150 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	SaveSubscriber(ctx context.Context, subscriber Subscriber) error
+	// GetSubscriber returns nil when the subscriber does not exist.
+	GetSubscriber(ctx context.Context, id string) (*Subscriber, error)
+	GetSubscribers(ctx context.Context) ([]Subscriber, error)
+	GetActiveSubscribers(ctx context.Context) ([]Subscriber, error)
+	SetSubscriberActive(ctx context.Context, id string, active bool) error
+	// SaveDelivery returns false when the event was already published to the
+	// subscriber.
+	SaveDelivery(ctx context.Context, delivery Delivery) (bool, error)
+	// GetDelivery returns nil when the delivery does not exist.
+	GetDelivery(ctx context.Context, id string) (*Delivery, error)
+	GetDeliveries(ctx context.Context, filter DeliveryFilter) ([]Delivery, error)
+	GetDeliveriesByStatus(ctx context.Context, statuses []DeliveryStatus) ([]Delivery, error)
+	UpdateDeliveryStatus(ctx context.Context, delivery Delivery) error
+	// ClaimDelivery moves a delivery from one status to another. It returns
+	// false when the delivery was no longer in the from status.
+	ClaimDelivery(ctx context.Context, id string, from, to DeliveryStatus) (bool, error)
+	// ClaimStaleDelivery claims a delivery in the given status that was not
+	// updated since updatedBefore by touching it. It returns false when the
+	// delivery changed in the meantime.
+	ClaimStaleDelivery(ctx context.Context, id string, status DeliveryStatus, updatedBefore time.Time) (bool, error)
+	// ClaimDeliveryAttempt counts an attempt of a pending or retrying
+	// delivery that has made the given number of attempts. It returns false
+	// when the delivery was finished, deferred or attempted elsewhere in the
+	// meantime.
+	ClaimDeliveryAttempt(ctx context.Context, id string, attempts int) (bool, error)
+	SaveDeliveryAttempt(ctx context.Context, attempt DeliveryAttempt) error
+	GetDeliveryAttempts(ctx context.Context, deliveryID string) ([]DeliveryAttempt, error)
+}
+
+// Sender posts an event to a subscriber. The status code is 0 when no
+// response was received.
+type Sender interface {
+	Send(ctx context.Context, subscriber Subscriber, event Event) (int, error)
+	// Configured reports whether the sender can sign events, so subscribers
+	// can be added.
+	Configured() bool
+}
+
+type DeliveryHandler interface {
+	// Deliver runs a single delivery attempt. delivery.Attempts holds the
+	// number of attempts made before this one. It does nothing when the
+	// delivery was finished or attempted elsewhere in the meantime.
+	Deliver(ctx context.Context, delivery Delivery) error
+	// Fail records that a delivery will not be retried any further.
+	Fail(ctx context.Context, delivery Delivery, cause error) error
+	// Defer records that a delivery could not be queued, so Sweep dispatches
+	// it again later.
+	Defer(ctx context.Context, delivery Delivery, cause error) error
+}
+
+// Dispatcher queues deliveries for asynchronous sending, retrying each one
+// as the policy allows. Retries resume from delivery.Attempts.
+type Dispatcher interface {
+	Dispatch(ctx context.Context, delivery Delivery, policy RetryPolicy, handler DeliveryHandler) error
+}
+
+type Publisher interface {
+	// Publish fans the event out to every active subscriber of its type.
+	// Publishing an event again does not deliver it twice.
+	Publish(ctx context.Context, event Event) error
+}
+
+type Hub interface {
+	Publisher
+	DeliveryHandler
+
+	// Resume re-dispatches deliveries that were stored but never finished,
+	// e.g. because the service restarted while they were queued. Only
+	// deliveries left untouched for a while are claimed, so those another
+	// instance is still working on are left alone. A delivery that can't be
+	// dispatched is deferred and the others are still resumed.
+	Resume(ctx context.Context) error
+	// Sweep re-dispatches the deferred deliveries and returns how many were
+	// queued.
+	Sweep(ctx context.Context) (int, error)
+}
+
+type SubscriberManager interface {
+	CreateSubscriber(ctx context.Context, request SubscriberRequest) (*Subscriber, error)
+	GetSubscribers(ctx context.Context) ([]Subscriber, error)
+	// DeactivateSubscriber stops new deliveries to the subscriber. Queued
+	// deliveries are not sent.
+	DeactivateSubscriber(ctx context.Context, id string) error
+	GetDeliveries(ctx context.Context, filter DeliveryFilter) ([]Delivery, error)
+	GetDeliveryAttempts(ctx context.Context, deliveryID string) ([]DeliveryAttempt, error)
+	// Redeliver queues a failed delivery again, extending its retry budget by
+	// the subscriber's retries.
+	Redeliver(ctx context.Context, deliveryID string) error
+}
+
+type Event = event
+type EventType = eventType
+type Subscriber = subscriber
+type Delivery = delivery
+type DeliveryStatus = deliveryStatus
+type DeliveryAttempt = deliveryAttempt
+type DeliveryFilter = deliveryFilter
+type SubscriberRequest = subscriberRequest
+type RetryPolicy = retryPolicy
+
+const (
+	EventTypeConsumerCreated        eventType = "consumer.created"
+	EventTypeConsumerUpdated        eventType = "consumer.updated"
+	EventTypeConsumerClosed         eventType = "consumer.closed"
+	EventTypeConsumerKYCUpdated     eventType = "consumer.kyc.updated"
+	EventTypeCardCreated            eventType = "card.created"
+	EventTypeCardUpdated            eventType = "card.updated"
+	EventTypeCardClosed             eventType = "card.closed"
+	EventTypeCardTransactionSettled eventType = "card.transaction.settled"
+	EventTypeTransferUpdated        eventType = "transfer.updated"
+)
+
+// EventTypes are the event types subscribers can register for.
+var EventTypes = []EventType{
+	EventTypeConsumerCreated,
+	EventTypeConsumerUpdated,
+	EventTypeConsumerClosed,
+	EventTypeConsumerKYCUpdated,
+	EventTypeCardCreated,
+	EventTypeCardUpdated,
+	EventTypeCardClosed,
+	EventTypeCardTransactionSettled,
+	EventTypeTransferUpdated,
+}
+
+const (
+	DeliveryStatusPending   deliveryStatus = "pending"
+	DeliveryStatusRetrying  deliveryStatus = "retrying"
+	DeliveryStatusDelivered deliveryStatus = "delivered"
+	DeliveryStatusFailed    deliveryStatus = "failed"
+	// DeliveryStatusDeferred marks a delivery that could not be queued, e.g.
+	// because the dispatch queue was full. Sweep dispatches it again.
+	DeliveryStatusDeferred deliveryStatus = "deferred"
+)
+
+// The retry policy of a subscriber that doesn't set one.
+const (
+	DefaultMaxRetries     = 5
+	DefaultBaseRetryDelay = 5 * time.Second
+	DefaultMaxRetryDelay  = 30 * time.Minute
+)
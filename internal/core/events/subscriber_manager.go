@@ -0,0 +1,275 @@
+package events
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"template/internal/logger"
+	"template/packages/common-go"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDeliveryLimit = 100
+	maxDeliveryLimit     = 500
+	maxSubscriberRetries = 20
+	minRetryDelay        = time.Second
+	maxRetryDelay        = 24 * time.Hour
+)
+
+var (
+	ErrInvalidSubscriber = common.AppError{
+		Code:    "INVALID_INPUT",
+		Message: "invalid event subscriber",
+		Status:  http.StatusBadRequest,
+	}
+	ErrSubscriberNotFound = common.AppError{
+		Code:    "NOT_FOUND",
+		Message: "event subscriber not found",
+		Status:  http.StatusNotFound,
+	}
+	ErrDeliveryNotFound = common.AppError{
+		Code:    "NOT_FOUND",
+		Message: "event delivery not found",
+		Status:  http.StatusNotFound,
+	}
+	ErrSigningNotConfigured = common.AppError{
+		Code:    "NOT_CONFIGURED",
+		Message: "INTER_SERVICE_SECRET must be set before adding event subscribers",
+		Status:  http.StatusConflict,
+	}
+	ErrDeliveryNotFailed = common.AppError{
+		Code:    "INVALID_STATE",
+		Message: "only failed event deliveries can be redelivered",
+		Status:  http.StatusConflict,
+	}
+)
+
+type subscriberManager struct {
+	logger     logger.Logger
+	repo       Repository
+	hub        Hub
+	dispatcher Dispatcher
+	sender     Sender
+}
+
+func NewSubscriberManager(logger logger.Logger, repo Repository, hub Hub, dispatcher Dispatcher, sender Sender) SubscriberManager {
+	if logger == nil {
+		return nil
+	}
+
+	return &subscriberManager{
+		logger:     logger,
+		repo:       repo,
+		hub:        hub,
+		dispatcher: dispatcher,
+		sender:     sender,
+	}
+}
+
+func (m *subscriberManager) CreateSubscriber(ctx context.Context, request SubscriberRequest) (*Subscriber, error) {
+	if err := validateSubscriberRequest(&request); err != nil {
+		return nil, err
+	}
+
+	// Events are sent signed, so a subscriber can't be served without a secret
+	if !m.sender.Configured() {
+		return nil, ErrSigningNotConfigured
+	}
+
+	now := time.Now()
+	subscriber := Subscriber{
+		ID:             uuid.New().String(),
+		Name:           request.Name,
+		URL:            request.URL,
+		EventTypes:     request.EventTypes,
+		MaxRetries:     request.MaxRetries,
+		BaseRetryDelay: request.BaseRetryDelay,
+		MaxRetryDelay:  request.MaxRetryDelay,
+		Active:         true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := m.repo.SaveSubscriber(ctx, subscriber); err != nil {
+		return nil, errors.Wrap(err, "failed to save event subscriber")
+	}
+
+	m.logger.Info("created event subscriber",
+		zap.String("subscriberID", subscriber.ID),
+		zap.String("name", subscriber.Name),
+		zap.Int("eventTypes", len(subscriber.EventTypes)))
+
+	return &subscriber, nil
+}
+
+func (m *subscriberManager) GetSubscribers(ctx context.Context) ([]Subscriber, error) {
+	subscribers, err := m.repo.GetSubscribers(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get event subscribers")
+	}
+
+	return subscribers, nil
+}
+
+func (m *subscriberManager) DeactivateSubscriber(ctx context.Context, id string) error {
+	subscriber, err := m.repo.GetSubscriber(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, "failed to get event subscriber")
+	}
+	if subscriber == nil {
+		return ErrSubscriberNotFound
+	}
+
+	if err := m.repo.SetSubscriberActive(ctx, id, false); err != nil {
+		return errors.Wrap(err, "failed to deactivate event subscriber")
+	}
+
+	m.logger.Info("deactivated event subscriber", zap.String("subscriberID", id))
+
+	return nil
+}
+
+func (m *subscriberManager) GetDeliveries(ctx context.Context, filter DeliveryFilter) ([]Delivery, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultDeliveryLimit
+	}
+	if filter.Limit > maxDeliveryLimit {
+		filter.Limit = maxDeliveryLimit
+	}
+
+	deliveries, err := m.repo.GetDeliveries(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get event deliveries")
+	}
+
+	return deliveries, nil
+}
+
+func (m *subscriberManager) GetDeliveryAttempts(ctx context.Context, deliveryID string) ([]DeliveryAttempt, error) {
+	delivery, err := m.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get event delivery")
+	}
+	if delivery == nil {
+		return nil, ErrDeliveryNotFound
+	}
+
+	attempts, err := m.repo.GetDeliveryAttempts(ctx, deliveryID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get event delivery attempts")
+	}
+
+	return attempts, nil
+}
+
+func (m *subscriberManager) Redeliver(ctx context.Context, deliveryID string) error {
+	delivery, err := m.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get event delivery")
+	}
+	if delivery == nil {
+		return ErrDeliveryNotFound
+	}
+	if delivery.Status != DeliveryStatusFailed {
+		return ErrDeliveryNotFailed
+	}
+
+	subscriber, err := m.repo.GetSubscriber(ctx, delivery.SubscriberID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get event subscriber")
+	}
+	if subscriber == nil || !subscriber.Active {
+		return ErrSubscriberNotFound.WithMessagef("event subscriber %s is not active", delivery.SubscriberID)
+	}
+
+	// Claiming the delivery keeps a concurrent redelivery from queueing it
+	// twice
+	claimed, err := m.repo.ClaimDelivery(ctx, delivery.ID, DeliveryStatusFailed, DeliveryStatusRetrying)
+	if err != nil {
+		return errors.Wrap(err, "failed to claim event delivery")
+	}
+	if !claimed {
+		return ErrDeliveryNotFailed
+	}
+	delivery.Status = DeliveryStatusRetrying
+
+	// Retries resume from the delivery's attempts, so its budget is extended
+	// rather than reset
+	policy := subscriber.retryPolicy()
+	policy.MaxRetries += delivery.Attempts
+
+	if err := m.dispatcher.Dispatch(ctx, *delivery, policy, m.hub); err != nil {
+		// Failing the delivery again lets it be redelivered later
+		delivery.Status = DeliveryStatusFailed
+		if updateErr := m.repo.UpdateDeliveryStatus(ctx, *delivery); updateErr != nil {
+			m.logger.Error("failed to restore failed event delivery",
+				zap.Error(updateErr),
+				zap.String("deliveryID", delivery.ID))
+		}
+		return errors.Wrap(err, "failed to dispatch event delivery")
+	}
+
+	m.logger.Info("redelivering event",
+		zap.String("deliveryID", delivery.ID),
+		zap.String("subscriberID", delivery.SubscriberID),
+		zap.Int("attempts", delivery.Attempts))
+
+	return nil
+}
+
+func validateSubscriberRequest(request *SubscriberRequest) error {
+	if request.Name == "" {
+		return ErrInvalidSubscriber.WithMessage("name is required")
+	}
+
+	endpoint, err := url.Parse(request.URL)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return ErrInvalidSubscriber.WithMessage("url must be an absolute http(s) URL")
+	}
+
+	if len(request.EventTypes) == 0 {
+		return ErrInvalidSubscriber.WithMessage("at least one event type is required")
+	}
+	for _, eventType := range request.EventTypes {
+		if !isEventType(eventType) {
+			return ErrInvalidSubscriber.WithMessagef("unknown event type: %s", eventType)
+		}
+	}
+
+	if request.MaxRetries == 0 {
+		request.MaxRetries = DefaultMaxRetries
+	}
+	if request.MaxRetries < 0 || request.MaxRetries > maxSubscriberRetries {
+		return ErrInvalidSubscriber.WithMessagef("maxRetries must be between 0 and %d", maxSubscriberRetries)
+	}
+
+	if request.BaseRetryDelay == 0 {
+		request.BaseRetryDelay = DefaultBaseRetryDelay
+	}
+	if request.MaxRetryDelay == 0 {
+		request.MaxRetryDelay = max(DefaultMaxRetryDelay, request.BaseRetryDelay)
+	}
+	if request.BaseRetryDelay < minRetryDelay || request.MaxRetryDelay > maxRetryDelay {
+		return ErrInvalidSubscriber.WithMessagef("retry delays must be between %s and %s", minRetryDelay, maxRetryDelay)
+	}
+	if request.MaxRetryDelay < request.BaseRetryDelay {
+		return ErrInvalidSubscriber.WithMessage("maxRetryDelay must not be less than baseRetryDelay")
+	}
+
+	return nil
+}
+
+func isEventType(eventType EventType) bool {
+	for _, known := range EventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,88 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Internal types
+type eventType string
+
+type deliveryStatus string
+
+// event is a normalized domain event republished to internal subscribers.
+// Data is the event's JSON payload.
+type event struct {
+	ID            string
+	Type          eventType
+	Source        string
+	SourceEventID string
+	OccurredAt    time.Time
+	Data          json.RawMessage
+}
+
+// subscriber is an internal service that receives the events of EventTypes
+// at URL. MaxRetries bounds the retries of each delivery, which back off
+// exponentially from BaseRetryDelay up to MaxRetryDelay.
+type subscriber struct {
+	ID             string
+	Name           string
+	URL            string
+	EventTypes     []eventType
+	MaxRetries     int
+	BaseRetryDelay time.Duration
+	MaxRetryDelay  time.Duration
+	Active         bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// retryPolicy is how a delivery is retried once an attempt fails.
+type retryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// delivery is an event sent to one subscriber.
+type delivery struct {
+	ID           string
+	SubscriberID string
+	Event        event
+	Status       deliveryStatus
+	Attempts     int
+	// LastStatusCode is 0 when the last attempt got no response.
+	LastStatusCode int
+	LastError      *string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// deliveryAttempt logs a single attempt of a delivery.
+type deliveryAttempt struct {
+	DeliveryID  string
+	Attempt     int
+	StatusCode  int
+	Error       *string
+	Duration    time.Duration
+	AttemptedAt time.Time
+}
+
+type deliveryFilter struct {
+	SubscriberID string
+	Status       deliveryStatus
+	Limit        int
+}
+
+type subscriberRequest struct {
+	Name       string
+	URL        string
+	EventTypes []eventType
+	// MaxRetries defaults to DefaultMaxRetries when zero.
+	MaxRetries int
+	// BaseRetryDelay and MaxRetryDelay default to DefaultBaseRetryDelay and
+	// DefaultMaxRetryDelay when zero.
+	BaseRetryDelay time.Duration
+	MaxRetryDelay  time.Duration
+}
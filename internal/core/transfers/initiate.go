@@ -0,0 +1,323 @@
+package transfers
+
+import (
+	"context"
+	"net/http"
+	banking "template/internal/core/banking"
+	"template/packages/common-go"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidTransfer = common.AppError{
+		Code:   "INVALID_INPUT",
+		Status: http.StatusBadRequest,
+	}
+	ErrConsumerNotOwned = common.AppError{
+		Code:    "FORBIDDEN",
+		Message: "consumer does not belong to the user",
+		Status:  http.StatusForbidden,
+	}
+	ErrCounterpartyNotOwned = common.AppError{
+		Code:    "FORBIDDEN",
+		Message: "counterparty account does not belong to the consumer",
+		Status:  http.StatusForbidden,
+	}
+	ErrConsumerNotEligible = common.AppError{
+		Code:    "CONSUMER_NOT_ELIGIBLE",
+		Message: "consumer must be active and KYC approved to move money",
+		Status:  http.StatusUnprocessableEntity,
+	}
+	ErrIdempotencyKeyReused = common.AppError{
+		Code:    "IDEMPOTENCY_KEY_REUSED",
+		Message: "idempotency key was already used for a different transfer",
+		Status:  http.StatusConflict,
+	}
+	ErrTransferRejected = common.AppError{
+		Code:    "TRANSFER_REJECTED",
+		Message: "transfer was rejected by the banking provider",
+		Status:  http.StatusUnprocessableEntity,
+	}
+)
+
+const (
+	// resubmitAfter leaves time for in-flight submissions to finish before
+	// their transfers are resubmitted
+	resubmitAfter      = 5 * time.Minute
+	resubmitBatchLimit = 100
+)
+
+func (s *service) InitiateTransfer(ctx context.Context, request InitiateRequest) (*Transfer, error) {
+	if err := s.validateRequest(request); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkConsumer(ctx, request.UserID, request.ConsumerID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCounterparty(ctx, request); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetTransferByIdempotencyKey(ctx, request.UserID, request.ConsumerID, request.IdempotencyKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get transfer")
+	}
+	if existing != nil {
+		return s.replay(ctx, request, *existing)
+	}
+
+	transfer, err := s.newTransfer(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store the transfer before calling out so a crash or timeout leaves a
+	// record that a retry with the same idempotency key resubmits.
+	transition := StatusTransition{
+		TransferID:    transfer.ID,
+		From:          StatusNone,
+		To:            StatusPending,
+		SourceEventID: request.IdempotencyKey,
+		Accepted:      true,
+		OccurredAt:    transfer.StatusUpdatedAt,
+	}
+	created, err := s.repo.CreateTransfer(ctx, transfer, transition)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to save pending transfer")
+	}
+
+	// A concurrent request with the same idempotency key stored its transfer
+	// first
+	if !created {
+		existing, err := s.repo.GetTransferByIdempotencyKey(ctx, request.UserID, request.ConsumerID, request.IdempotencyKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get transfer")
+		}
+		if existing == nil {
+			return nil, errors.Errorf("transfer with idempotency key %s not found", request.IdempotencyKey)
+		}
+		return s.replay(ctx, request, *existing)
+	}
+
+	return s.submit(ctx, transfer)
+}
+
+// replay answers a repeated request with the transfer stored for its
+// idempotency key.
+func (s *service) replay(ctx context.Context, request InitiateRequest, existing Transfer) (*Transfer, error) {
+	if existing.Amount != request.Amount || existing.Type != request.Type || existing.Direction != request.Direction {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if existing.Type == TypeACH && counterpartyAccountID(existing) != request.CounterpartyAccountID {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if existing.ProviderTransferID != "" || existing.Status != StatusPending {
+		return &existing, nil
+	}
+
+	return s.submit(ctx, existing)
+}
+
+func (s *service) ResubmitTransfers(ctx context.Context) (int, error) {
+	pending, err := s.repo.ListUnsubmittedTransfers(ctx, time.Now().Add(-resubmitAfter), resubmitBatchLimit)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list unsubmitted transfers")
+	}
+
+	submitted := 0
+	for _, transfer := range pending {
+		// submit logs its failures, the transfer is tried again next time
+		if _, err := s.submit(ctx, transfer); err == nil {
+			submitted++
+		}
+	}
+
+	return submitted, nil
+}
+
+func (s *service) validateRequest(request InitiateRequest) error {
+	if request.IdempotencyKey == "" {
+		return ErrInvalidTransfer.WithMessage("idempotency key is required")
+	}
+	if request.UserID == "" || request.ConsumerID == "" {
+		return ErrInvalidTransfer.WithMessage("user ID and consumer ID are required")
+	}
+	if request.Amount <= 0 {
+		return ErrInvalidTransfer.WithMessage("amount must be positive")
+	}
+	if request.Amount > MaxTransferAmount {
+		return ErrInvalidTransfer.WithMessagef("amount must not exceed %d cents", MaxTransferAmount)
+	}
+	if request.Currency != "USD" {
+		return ErrInvalidTransfer.WithMessage("only USD transfers are supported")
+	}
+	if request.Direction != DirectionDebit && request.Direction != DirectionCredit {
+		return ErrInvalidTransfer.WithMessagef("invalid direction: %s", request.Direction)
+	}
+
+	switch request.Type {
+	case TypeACH:
+		if request.CounterpartyAccountID == "" {
+			return ErrInvalidTransfer.WithMessage("counterparty account is required for ACH transfers")
+		}
+	case TypePayment:
+		if request.CounterpartyAccountID != "" {
+			return ErrInvalidTransfer.WithMessage("payments settle against the FBO account and take no counterparty account")
+		}
+	default:
+		return ErrInvalidTransfer.WithMessagef("invalid transfer type: %s", request.Type)
+	}
+
+	return nil
+}
+
+// checkConsumer makes sure the consumer whose account is moved belongs to the
+// requesting user and is allowed to move money.
+func (s *service) checkConsumer(ctx context.Context, userID, consumerID string) error {
+	consumer, err := s.consumers.GetBankingConsumer(ctx, consumerID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get consumer")
+	}
+
+	if consumer == nil || consumer.ExternalID != userID {
+		return ErrConsumerNotOwned
+	}
+
+	if !consumer.IsActive || consumer.Deleted || consumer.KYCStatus != banking.KYCStatusApproved {
+		return ErrConsumerNotEligible
+	}
+
+	return nil
+}
+
+// checkCounterparty makes sure an ACH transfer only moves money to or from an
+// account the consumer linked.
+func (s *service) checkCounterparty(ctx context.Context, request InitiateRequest) error {
+	if request.Type != TypeACH {
+		return nil
+	}
+
+	account, err := s.client.GetExternalAccount(ctx, request.CounterpartyAccountID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get counterparty account")
+	}
+
+	if account == nil || account.ConsumerID != request.ConsumerID {
+		return ErrCounterpartyNotOwned
+	}
+
+	return nil
+}
+
+// counterpartyAccountID returns the account on the other side of the
+// consumer's own account.
+func counterpartyAccountID(transfer Transfer) string {
+	if transfer.Direction == DirectionDebit {
+		return transfer.DestinationAccountID
+	}
+	return transfer.SourceAccountID
+}
+
+func (s *service) newTransfer(request InitiateRequest) (Transfer, error) {
+	counterparty := request.CounterpartyAccountID
+	if request.Type == TypePayment {
+		if s.fboAccountNumber == "" {
+			return Transfer{}, errors.New("FBO account number is not configured")
+		}
+		counterparty = s.fboAccountNumber
+	}
+
+	transfer := Transfer{
+		ID:              uuid.New().String(),
+		IdempotencyKey:  request.IdempotencyKey,
+		ConsumerID:      request.ConsumerID,
+		Type:            request.Type,
+		Direction:       request.Direction,
+		Status:          StatusPending,
+		Amount:          request.Amount,
+		Currency:        request.Currency,
+		Description:     request.Description,
+		StatusUpdatedAt: time.Now(),
+	}
+
+	// The consumer's own account is identified by the consumer ID
+	if request.Direction == DirectionDebit {
+		transfer.SourceAccountID = request.ConsumerID
+		transfer.DestinationAccountID = counterparty
+	} else {
+		transfer.SourceAccountID = counterparty
+		transfer.DestinationAccountID = request.ConsumerID
+	}
+
+	return transfer, nil
+}
+
+func (s *service) submit(ctx context.Context, transfer Transfer) (*Transfer, error) {
+	var (
+		created *Transfer
+		err     error
+	)
+	switch transfer.Type {
+	case TypeACH:
+		created, err = s.client.CreateACHTransfer(ctx, transfer)
+	case TypePayment:
+		created, err = s.client.CreatePaymentTransfer(ctx, transfer)
+	default:
+		return nil, errors.Errorf("invalid transfer type: %s", transfer.Type)
+	}
+	if err != nil {
+		s.logger.Error("failed to submit transfer",
+			zap.Error(err),
+			zap.String("transferID", transfer.ID),
+			zap.String("idempotencyKey", transfer.IdempotencyKey))
+
+		if errors.Is(err, ErrTransferRejected) {
+			return nil, s.reject(ctx, transfer, err)
+		}
+		// The transfer stays pending, a retry or ResubmitTransfers submits
+		// it again
+		return nil, errors.Wrap(err, "failed to submit transfer")
+	}
+
+	transfer.ProviderTransferID = created.ProviderTransferID
+	if err := s.repo.SetProviderTransferID(ctx, transfer.ID, transfer.ProviderTransferID); err != nil {
+		return nil, errors.Wrap(err, "failed to save submitted transfer")
+	}
+
+	s.logger.Info("submitted transfer",
+		zap.String("transferID", transfer.ID),
+		zap.String("providerTransferID", transfer.ProviderTransferID),
+		zap.String("type", string(transfer.Type)),
+		zap.Int64("amount", transfer.Amount))
+
+	return s.GetTransfer(ctx, transfer.ID)
+}
+
+// reject marks a transfer the provider refused as failed, so it isn't
+// resubmitted.
+func (s *service) reject(ctx context.Context, transfer Transfer, cause error) error {
+	now := time.Now()
+	transfer.Status = StatusFailed
+	transfer.FailureReason = cause.Error()
+	transfer.StatusUpdatedAt = now
+
+	transition := StatusTransition{
+		TransferID:    transfer.ID,
+		From:          StatusPending,
+		To:            StatusFailed,
+		SourceEventID: transfer.IdempotencyKey,
+		Accepted:      true,
+		OccurredAt:    now,
+	}
+	if err := s.repo.SaveTransferTransition(ctx, transfer, transition); err != nil {
+		return errors.Wrap(err, "failed to save rejected transfer")
+	}
+
+	return ErrTransferRejected
+}
@@ -0,0 +1,411 @@
+package transfers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	banking "template/internal/core/banking"
+	transfers "template/internal/core/transfers"
+	"template/internal/logger"
+)
+
+// fakeRepository stores transfers and their transitions in memory.
+// Idempotency keys are unique per consumer, as in the database.
+type fakeRepository struct {
+	transfers   map[string]transfers.Transfer
+	transitions []transfers.StatusTransition
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{transfers: map[string]transfers.Transfer{}}
+}
+
+func (r *fakeRepository) SetProviderTransferID(ctx context.Context, id string, providerTransferID string) error {
+	transfer := r.transfers[id]
+	transfer.ProviderTransferID = providerTransferID
+	r.transfers[id] = transfer
+	return nil
+}
+
+func (r *fakeRepository) GetTransfer(ctx context.Context, id string) (*transfers.Transfer, error) {
+	transfer, ok := r.transfers[id]
+	if !ok {
+		return nil, nil
+	}
+	return &transfer, nil
+}
+
+func (r *fakeRepository) GetTransferByProviderID(ctx context.Context, providerTransferID string) (*transfers.Transfer, error) {
+	for _, transfer := range r.transfers {
+		if transfer.ProviderTransferID == providerTransferID {
+			return &transfer, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRepository) GetTransferByIdempotencyKey(ctx context.Context, userID, consumerID, idempotencyKey string) (*transfers.Transfer, error) {
+	for _, transfer := range r.transfers {
+		if transfer.ConsumerID == consumerID && transfer.IdempotencyKey == idempotencyKey {
+			return &transfer, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRepository) ListUnsubmittedTransfers(ctx context.Context, createdBefore time.Time, limit int) ([]transfers.Transfer, error) {
+	var unsubmitted []transfers.Transfer
+	for _, transfer := range r.transfers {
+		if transfer.Status == transfers.StatusPending && transfer.ProviderTransferID == "" {
+			unsubmitted = append(unsubmitted, transfer)
+		}
+	}
+	return unsubmitted, nil
+}
+
+func (r *fakeRepository) CreateTransfer(ctx context.Context, transfer transfers.Transfer, transition transfers.StatusTransition) (bool, error) {
+	if existing, _ := r.GetTransferByIdempotencyKey(ctx, "", transfer.ConsumerID, transfer.IdempotencyKey); existing != nil {
+		return false, nil
+	}
+
+	r.transfers[transfer.ID] = transfer
+	r.transitions = append(r.transitions, transition)
+	return true, nil
+}
+
+func (r *fakeRepository) GetTransfersByConsumer(ctx context.Context, consumerID string) ([]transfers.Transfer, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) GetTransfersByUser(ctx context.Context, userID string) ([]transfers.Transfer, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) SaveTransferTransition(ctx context.Context, transfer transfers.Transfer, transition transfers.StatusTransition) error {
+	if stored, ok := r.transfers[transfer.ID]; ok && stored.Status != transition.From {
+		return transfers.ErrTransferStatusChanged
+	}
+
+	r.transitions = append(r.transitions, transition)
+	if transition.Accepted {
+		r.transfers[transfer.ID] = transfer
+	}
+	return nil
+}
+
+func (r *fakeRepository) GetTransferTransitions(ctx context.Context, transferID string) ([]transfers.StatusTransition, error) {
+	var transitions []transfers.StatusTransition
+	for _, transition := range r.transitions {
+		if transition.TransferID == transferID {
+			transitions = append(transitions, transition)
+		}
+	}
+	return transitions, nil
+}
+
+// fakeConsumers serves the given consumers.
+type fakeConsumers struct {
+	consumers []banking.Consumer
+}
+
+func (c *fakeConsumers) SaveBankingConsumer(ctx context.Context, consumer banking.Consumer) error {
+	return nil
+}
+
+func (c *fakeConsumers) GetBankingConsumer(ctx context.Context, id string) (*banking.Consumer, error) {
+	for _, consumer := range c.consumers {
+		if consumer.ID == id {
+			return &consumer, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *fakeConsumers) GetBankingConsumerByExternalID(ctx context.Context, externalID string) (*banking.Consumer, error) {
+	return nil, nil
+}
+
+func (c *fakeConsumers) SaveKYCTransition(ctx context.Context, consumer banking.Consumer, transition banking.KYCTransition) error {
+	return nil
+}
+
+func (c *fakeConsumers) GetKYCTransitions(ctx context.Context, consumerID string) ([]banking.KYCTransition, error) {
+	return nil, nil
+}
+
+// fakeClient accepts every transfer unless err is set and records what it
+// was sent.
+type fakeClient struct {
+	accounts  []transfers.ExternalAccount
+	err       error
+	submitted []transfers.Transfer
+}
+
+func (c *fakeClient) CreateACHTransfer(ctx context.Context, transfer transfers.Transfer) (*transfers.Transfer, error) {
+	return c.create(transfer)
+}
+
+func (c *fakeClient) CreatePaymentTransfer(ctx context.Context, transfer transfers.Transfer) (*transfers.Transfer, error) {
+	return c.create(transfer)
+}
+
+func (c *fakeClient) create(transfer transfers.Transfer) (*transfers.Transfer, error) {
+	c.submitted = append(c.submitted, transfer)
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	transfer.ProviderTransferID = "prov_" + transfer.ID
+	return &transfer, nil
+}
+
+func (c *fakeClient) GetExternalAccount(ctx context.Context, id string) (*transfers.ExternalAccount, error) {
+	for _, account := range c.accounts {
+		if account.ID == id {
+			return &account, nil
+		}
+	}
+	return nil, nil
+}
+
+const (
+	testUserID     = "user_1"
+	testConsumerID = "con_1"
+	testAccountID  = "acct_1"
+)
+
+func newTestService(repo *fakeRepository, client *fakeClient) transfers.Service {
+	consumers := &fakeConsumers{consumers: []banking.Consumer{
+		{ID: testConsumerID, ExternalID: testUserID, IsActive: true, KYCStatus: banking.KYCStatusApproved},
+		{ID: "con_2", ExternalID: "user_2", IsActive: true, KYCStatus: banking.KYCStatusApproved},
+	}}
+	client.accounts = append(client.accounts,
+		transfers.ExternalAccount{ID: testAccountID, ConsumerID: testConsumerID},
+		transfers.ExternalAccount{ID: "acct_2", ConsumerID: "con_2"},
+	)
+
+	return transfers.NewService(&logger.NoOpLogger{}, repo, consumers, client, "fbo_1")
+}
+
+func achRequest() transfers.InitiateRequest {
+	return transfers.InitiateRequest{
+		UserID:                testUserID,
+		ConsumerID:            testConsumerID,
+		Type:                  transfers.TypeACH,
+		Direction:             transfers.DirectionDebit,
+		Amount:                10_00,
+		Currency:              "USD",
+		CounterpartyAccountID: testAccountID,
+		IdempotencyKey:        "key_1",
+	}
+}
+
+func TestInitiateTransferChecksOwnership(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(request *transfers.InitiateRequest)
+		wantErr error
+	}{
+		{
+			name:   "own account",
+			modify: func(request *transfers.InitiateRequest) {},
+		},
+		{
+			name:    "other user's consumer",
+			modify:  func(request *transfers.InitiateRequest) { request.ConsumerID = "con_2" },
+			wantErr: transfers.ErrConsumerNotOwned,
+		},
+		{
+			name:    "other consumer's counterparty account",
+			modify:  func(request *transfers.InitiateRequest) { request.CounterpartyAccountID = "acct_2" },
+			wantErr: transfers.ErrCounterpartyNotOwned,
+		},
+		{
+			name:    "unknown counterparty account",
+			modify:  func(request *transfers.InitiateRequest) { request.CounterpartyAccountID = "acct_unknown" },
+			wantErr: transfers.ErrCounterpartyNotOwned,
+		},
+		{
+			name: "payment without counterparty",
+			modify: func(request *transfers.InitiateRequest) {
+				request.Type = transfers.TypePayment
+				request.CounterpartyAccountID = ""
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository()
+			client := &fakeClient{}
+			service := newTestService(repo, client)
+
+			request := achRequest()
+			tt.modify(&request)
+
+			_, err := service.InitiateTransfer(context.Background(), request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && (len(repo.transfers) != 0 || len(client.submitted) != 0) {
+				t.Errorf("stored %d and submitted %d transfers, want none", len(repo.transfers), len(client.submitted))
+			}
+		})
+	}
+}
+
+func TestInitiateTransferReplaysIdempotencyKey(t *testing.T) {
+	repo := newFakeRepository()
+	client := &fakeClient{}
+	service := newTestService(repo, client)
+
+	first, err := service.InitiateTransfer(context.Background(), achRequest())
+	if err != nil {
+		t.Fatalf("first InitiateTransfer() error = %v", err)
+	}
+
+	replayed, err := service.InitiateTransfer(context.Background(), achRequest())
+	if err != nil {
+		t.Fatalf("replayed InitiateTransfer() error = %v", err)
+	}
+	if replayed.ID != first.ID || replayed.ProviderTransferID != first.ProviderTransferID {
+		t.Errorf("replayed transfer = %s/%s, want %s/%s", replayed.ID, replayed.ProviderTransferID, first.ID, first.ProviderTransferID)
+	}
+	if len(client.submitted) != 1 {
+		t.Errorf("submitted %d transfers, want 1", len(client.submitted))
+	}
+}
+
+func TestInitiateTransferRejectsReusedIdempotencyKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(request *transfers.InitiateRequest)
+	}{
+		{
+			name:   "different amount",
+			modify: func(request *transfers.InitiateRequest) { request.Amount = 20_00 },
+		},
+		{
+			name:   "different direction",
+			modify: func(request *transfers.InitiateRequest) { request.Direction = transfers.DirectionCredit },
+		},
+		{
+			name: "different counterparty",
+			modify: func(request *transfers.InitiateRequest) {
+				request.CounterpartyAccountID = "acct_3"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository()
+			client := &fakeClient{accounts: []transfers.ExternalAccount{{ID: "acct_3", ConsumerID: testConsumerID}}}
+			service := newTestService(repo, client)
+
+			if _, err := service.InitiateTransfer(context.Background(), achRequest()); err != nil {
+				t.Fatalf("first InitiateTransfer() error = %v", err)
+			}
+
+			request := achRequest()
+			tt.modify(&request)
+
+			_, err := service.InitiateTransfer(context.Background(), request)
+			if !errors.Is(err, transfers.ErrIdempotencyKeyReused) {
+				t.Fatalf("err = %v, want %v", err, transfers.ErrIdempotencyKeyReused)
+			}
+			if len(repo.transfers) != 1 || len(client.submitted) != 1 {
+				t.Errorf("stored %d and submitted %d transfers, want 1 each", len(repo.transfers), len(client.submitted))
+			}
+		})
+	}
+}
+
+func TestInitiateTransferResubmitsUnacknowledgedTransfers(t *testing.T) {
+	repo := newFakeRepository()
+	client := &fakeClient{err: errors.New("timeout")}
+	service := newTestService(repo, client)
+
+	if _, err := service.InitiateTransfer(context.Background(), achRequest()); err == nil {
+		t.Fatal("InitiateTransfer() error = nil, want the submission error")
+	}
+	for _, transfer := range repo.transfers {
+		if transfer.Status != transfers.StatusPending || transfer.ProviderTransferID != "" {
+			t.Fatalf("transfer = %s/%q, want pending without a provider ID", transfer.Status, transfer.ProviderTransferID)
+		}
+	}
+
+	// The client's retry with the same idempotency key submits the stored
+	// transfer again
+	client.err = nil
+	transfer, err := service.InitiateTransfer(context.Background(), achRequest())
+	if err != nil {
+		t.Fatalf("retried InitiateTransfer() error = %v", err)
+	}
+	if transfer.ProviderTransferID == "" {
+		t.Error("retried transfer has no provider ID")
+	}
+	if len(repo.transfers) != 1 || len(client.submitted) != 2 {
+		t.Errorf("stored %d and submitted %d transfers, want 1 and 2", len(repo.transfers), len(client.submitted))
+	}
+}
+
+func TestResubmitTransfers(t *testing.T) {
+	repo := newFakeRepository()
+	client := &fakeClient{err: errors.New("timeout")}
+	service := newTestService(repo, client)
+
+	if _, err := service.InitiateTransfer(context.Background(), achRequest()); err == nil {
+		t.Fatal("InitiateTransfer() error = nil, want the submission error")
+	}
+
+	client.err = nil
+	submitted, err := service.ResubmitTransfers(context.Background())
+	if err != nil {
+		t.Fatalf("ResubmitTransfers() error = %v", err)
+	}
+	if submitted != 1 {
+		t.Errorf("submitted = %d, want 1", submitted)
+	}
+
+	// Submitted transfers are not picked up again
+	submitted, err = service.ResubmitTransfers(context.Background())
+	if err != nil {
+		t.Fatalf("ResubmitTransfers() error = %v", err)
+	}
+	if submitted != 0 {
+		t.Errorf("submitted = %d on the second run, want 0", submitted)
+	}
+}
+
+func TestInitiateTransferDoesNotResubmitRejectedTransfers(t *testing.T) {
+	repo := newFakeRepository()
+	client := &fakeClient{err: transfers.ErrTransferRejected}
+	service := newTestService(repo, client)
+
+	if _, err := service.InitiateTransfer(context.Background(), achRequest()); !errors.Is(err, transfers.ErrTransferRejected) {
+		t.Fatalf("err = %v, want %v", err, transfers.ErrTransferRejected)
+	}
+
+	client.err = nil
+	transfer, err := service.InitiateTransfer(context.Background(), achRequest())
+	if err != nil {
+		t.Fatalf("retried InitiateTransfer() error = %v", err)
+	}
+	if transfer.Status != transfers.StatusFailed {
+		t.Errorf("status = %q, want %q", transfer.Status, transfers.StatusFailed)
+	}
+	if len(client.submitted) != 1 {
+		t.Errorf("submitted %d transfers, want 1", len(client.submitted))
+	}
+
+	submitted, err := service.ResubmitTransfers(context.Background())
+	if err != nil {
+		t.Fatalf("ResubmitTransfers() error = %v", err)
+	}
+	if submitted != 0 {
+		t.Errorf("resubmitted = %d, want 0", submitted)
+	}
+}
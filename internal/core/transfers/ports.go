@@ -0,0 +1,99 @@
+package transfers
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// SetProviderTransferID links a transfer to the provider's transfer
+	// without touching its status, which events may already have advanced.
+	SetProviderTransferID(ctx context.Context, id string, providerTransferID string) error
+	// GetTransfer returns nil when the transfer does not exist.
+	GetTransfer(ctx context.Context, id string) (*Transfer, error)
+	// GetTransferByProviderID returns nil when the transfer does not exist.
+	GetTransferByProviderID(ctx context.Context, providerTransferID string) (*Transfer, error)
+	// GetTransferByIdempotencyKey returns the transfer the user initiated for
+	// the consumer with the given key, or nil when there is none.
+	GetTransferByIdempotencyKey(ctx context.Context, userID, consumerID, idempotencyKey string) (*Transfer, error)
+	// ListUnsubmittedTransfers lists initiated transfers created before the
+	// given time that the provider never acknowledged, oldest first.
+	ListUnsubmittedTransfers(ctx context.Context, createdBefore time.Time, limit int) ([]Transfer, error)
+	// CreateTransfer stores a new transfer along with its first transition.
+	// It returns false, storing nothing, when the consumer already has a
+	// transfer with the same idempotency key.
+	CreateTransfer(ctx context.Context, transfer Transfer, transition StatusTransition) (bool, error)
+	GetTransfersByConsumer(ctx context.Context, consumerID string) ([]Transfer, error)
+	// GetTransfersByUser lists the transfers of the consumer whose external ID
+	// is the given user ID.
+	GetTransfersByUser(ctx context.Context, userID string) ([]Transfer, error)
+	// SaveTransferTransition records the transition and, if it was accepted,
+	// saves the transfer with its new status. It returns
+	// ErrTransferStatusChanged when the stored status is no longer the
+	// transition's From.
+	SaveTransferTransition(ctx context.Context, transfer Transfer, transition StatusTransition) error
+	GetTransferTransitions(ctx context.Context, transferID string) ([]StatusTransition, error)
+}
+
+// PaymentClient moves money through the banking provider. Transfers are sent
+// with their ID as the idempotency key, so resubmitting one never creates a
+// second transfer. Errors matching ErrTransferRejected mean the provider
+// refused the transfer for good.
+type PaymentClient interface {
+	CreateACHTransfer(ctx context.Context, transfer Transfer) (*Transfer, error)
+	CreatePaymentTransfer(ctx context.Context, transfer Transfer) (*Transfer, error)
+	// GetExternalAccount returns nil when the account does not exist.
+	GetExternalAccount(ctx context.Context, id string) (*ExternalAccount, error)
+}
+
+type Service interface {
+	// InitiateTransfer stores a pending transfer and submits it to the
+	// provider. Repeating a request with the same user, consumer and
+	// idempotency key returns the stored transfer, resubmitting it if the
+	// provider never acknowledged it. A transfer the provider rejects is
+	// marked failed.
+	InitiateTransfer(ctx context.Context, request InitiateRequest) (*Transfer, error)
+	// ResubmitTransfers resubmits the initiated transfers the provider never
+	// acknowledged, e.g. because submitting them timed out, and returns how
+	// many were submitted.
+	ResubmitTransfers(ctx context.Context) (int, error)
+	// TransitionTransfer moves the transfer identified by its provider ID to
+	// the given status if the state machine allows it, creating it if it is
+	// not known yet. Disallowed transitions are recorded as rejected.
+	TransitionTransfer(ctx context.Context, transfer Transfer, to Status, sourceEventID string, occurredAt time.Time) (StatusTransition, error)
+	GetTransfer(ctx context.Context, id string) (*Transfer, error)
+	GetTransferHistory(ctx context.Context, id string) ([]StatusTransition, error)
+	GetConsumerTransfers(ctx context.Context, consumerID string) ([]Transfer, error)
+	GetUserTransfers(ctx context.Context, userID string) ([]Transfer, error)
+}
+
+type Transfer = transfer
+type Status = transferStatus
+type Type = transferType
+type Direction = transferDirection
+type StatusTransition = statusTransition
+type InitiateRequest = initiateRequest
+type ExternalAccount = externalAccount
+
+const (
+	StatusNone      transferStatus = ""
+	StatusPending   transferStatus = "pending"
+	StatusSent      transferStatus = "sent"
+	StatusCompleted transferStatus = "completed"
+	StatusFailed    transferStatus = "failed"
+)
+
+const (
+	TypeACH     transferType = "ach"
+	TypePayment transferType = "payment"
+)
+
+const (
+	// DirectionDebit moves money out of the consumer's account.
+	DirectionDebit transferDirection = "debit"
+	// DirectionCredit moves money into the consumer's account.
+	DirectionCredit transferDirection = "credit"
+)
+
+// MaxTransferAmount caps a single initiated transfer, in cents.
+const MaxTransferAmount int64 = 2_500_000
@@ -0,0 +1,238 @@
+package transfers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	banking "template/internal/core/banking"
+	"template/internal/logger"
+	"template/packages/common-go"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var ErrTransferNotFound = common.AppError{
+	Code:    "NOT_FOUND",
+	Message: "transfer not found",
+	Status:  http.StatusNotFound,
+}
+
+var ErrTransferStatusChanged = errors.New("transfer status changed concurrently")
+
+// transitionAttempts bounds how often a transition is decided again after
+// losing a race with another one.
+const transitionAttempts = 3
+
+type service struct {
+	logger           logger.Logger
+	repo             Repository
+	consumers        banking.Repository
+	client           PaymentClient
+	fboAccountNumber string
+}
+
+func NewService(
+	logger logger.Logger,
+	repo Repository,
+	consumers banking.Repository,
+	client PaymentClient,
+	fboAccountNumber string,
+) Service {
+	if logger == nil {
+		return nil
+	}
+
+	return &service{
+		logger:           logger,
+		repo:             repo,
+		consumers:        consumers,
+		client:           client,
+		fboAccountNumber: fboAccountNumber,
+	}
+}
+
+func (s *service) TransitionTransfer(
+	ctx context.Context,
+	transfer Transfer,
+	to Status,
+	sourceEventID string,
+	occurredAt time.Time,
+) (StatusTransition, error) {
+	if transfer.ProviderTransferID == "" {
+		return StatusTransition{}, errors.New("provider transfer ID is required")
+	}
+	if to == StatusNone || !IsValidStatus(to) {
+		return StatusTransition{}, errors.Errorf("invalid transfer status: %s", to)
+	}
+
+	// The status is checked again when the transition is saved, so a
+	// concurrent transition makes us decide again on the new status
+	for attempt := 1; ; attempt++ {
+		transition, err := s.transitionTransfer(ctx, transfer, to, sourceEventID, occurredAt)
+		if errors.Is(err, ErrTransferStatusChanged) && attempt < transitionAttempts {
+			continue
+		}
+
+		return transition, err
+	}
+}
+
+func (s *service) transitionTransfer(
+	ctx context.Context,
+	transfer Transfer,
+	to Status,
+	sourceEventID string,
+	occurredAt time.Time,
+) (StatusTransition, error) {
+	stored, err := s.repo.GetTransferByProviderID(ctx, transfer.ProviderTransferID)
+	if err != nil {
+		return StatusTransition{}, errors.Wrap(err, "failed to get transfer")
+	}
+
+	// Transfers we initiated carry our ID, and their events may arrive before
+	// the provider's ID has been stored.
+	if stored == nil && transfer.ID != "" {
+		stored, err = s.repo.GetTransfer(ctx, transfer.ID)
+		if err != nil {
+			return StatusTransition{}, errors.Wrap(err, "failed to get transfer")
+		}
+	}
+
+	from := StatusNone
+	if stored != nil {
+		from = stored.Status
+		transfer = mergeTransfer(*stored, transfer)
+	} else {
+		if transfer.ConsumerID == "" {
+			return StatusTransition{}, errors.New("consumer ID is required")
+		}
+		transfer.ID = uuid.New().String()
+	}
+
+	transition := StatusTransition{
+		TransferID:    transfer.ID,
+		From:          from,
+		To:            to,
+		SourceEventID: sourceEventID,
+		Accepted:      true,
+		OccurredAt:    occurredAt,
+	}
+
+	if from == to {
+		s.logger.Debug("ignoring repeated transfer status",
+			zap.String("transferID", transfer.ID),
+			zap.String("status", string(to)),
+			zap.String("eventID", sourceEventID))
+		return transition, nil
+	}
+
+	if !CanTransition(from, to) {
+		transition.Accepted = false
+		transition.Reason = fmt.Sprintf("transition from %q to %q is not allowed", from, to)
+
+		s.logger.Warn("rejected out-of-order transfer transition",
+			zap.String("transferID", transfer.ID),
+			zap.String("from", string(from)),
+			zap.String("to", string(to)),
+			zap.String("eventID", sourceEventID))
+	}
+
+	transfer.Status = to
+	transfer.StatusUpdatedAt = occurredAt
+	if to != StatusFailed {
+		transfer.FailureReason = ""
+	}
+
+	if err := s.repo.SaveTransferTransition(ctx, transfer, transition); err != nil {
+		return StatusTransition{}, errors.Wrap(err, "failed to save transfer transition")
+	}
+
+	return transition, nil
+}
+
+// mergeTransfer applies the details carried by an event to the stored
+// transfer. Identity fields always come from the stored transfer.
+func mergeTransfer(stored, update Transfer) Transfer {
+	if stored.ProviderTransferID == "" {
+		stored.ProviderTransferID = update.ProviderTransferID
+	}
+	if update.Amount != 0 {
+		stored.Amount = update.Amount
+	}
+	if update.Currency != "" {
+		stored.Currency = update.Currency
+	}
+	if update.SourceAccountID != "" {
+		stored.SourceAccountID = update.SourceAccountID
+	}
+	if update.DestinationAccountID != "" {
+		stored.DestinationAccountID = update.DestinationAccountID
+	}
+	if update.Description != "" {
+		stored.Description = update.Description
+	}
+	if update.FailureReason != "" {
+		stored.FailureReason = update.FailureReason
+	}
+
+	return stored
+}
+
+func (s *service) GetTransfer(ctx context.Context, id string) (*Transfer, error) {
+	if id == "" {
+		return nil, errors.New("transfer ID is required")
+	}
+
+	transfer, err := s.repo.GetTransfer(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get transfer")
+	}
+
+	if transfer == nil {
+		return nil, ErrTransferNotFound
+	}
+
+	return transfer, nil
+}
+
+func (s *service) GetTransferHistory(ctx context.Context, id string) ([]StatusTransition, error) {
+	if id == "" {
+		return nil, errors.New("transfer ID is required")
+	}
+
+	transitions, err := s.repo.GetTransferTransitions(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get transfer transitions")
+	}
+
+	return transitions, nil
+}
+
+func (s *service) GetConsumerTransfers(ctx context.Context, consumerID string) ([]Transfer, error) {
+	if consumerID == "" {
+		return nil, errors.New("consumer ID is required")
+	}
+
+	transfers, err := s.repo.GetTransfersByConsumer(ctx, consumerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get transfers")
+	}
+
+	return transfers, nil
+}
+
+func (s *service) GetUserTransfers(ctx context.Context, userID string) ([]Transfer, error) {
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	transfers, err := s.repo.GetTransfersByUser(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get transfers")
+	}
+
+	return transfers, nil
+}
@@ -0,0 +1,150 @@
+package transfers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	transfers "template/internal/core/transfers"
+)
+
+const testProviderTransferID = "prov_1"
+
+func storedTransfer(status transfers.Status) transfers.Transfer {
+	return transfers.Transfer{
+		ID:                 "tr_1",
+		ProviderTransferID: testProviderTransferID,
+		ConsumerID:         testConsumerID,
+		Type:               transfers.TypeACH,
+		Direction:          transfers.DirectionDebit,
+		Status:             status,
+		Amount:             10_00,
+	}
+}
+
+func TestTransitionTransfer(t *testing.T) {
+	tests := []struct {
+		name            string
+		stored          transfers.Status
+		to              transfers.Status
+		failureReason   string
+		wantAccepted    bool
+		wantStatus      transfers.Status
+		wantFailure     string
+		wantTransitions int
+	}{
+		{
+			name:            "sent transfer completes",
+			stored:          transfers.StatusSent,
+			to:              transfers.StatusCompleted,
+			wantAccepted:    true,
+			wantStatus:      transfers.StatusCompleted,
+			wantTransitions: 1,
+		},
+		{
+			name:            "ACH return after completion",
+			stored:          transfers.StatusCompleted,
+			to:              transfers.StatusFailed,
+			failureReason:   "R01 insufficient funds",
+			wantAccepted:    true,
+			wantStatus:      transfers.StatusFailed,
+			wantFailure:     "R01 insufficient funds",
+			wantTransitions: 1,
+		},
+		{
+			name:            "completion after failure",
+			stored:          transfers.StatusFailed,
+			to:              transfers.StatusCompleted,
+			wantAccepted:    false,
+			wantStatus:      transfers.StatusFailed,
+			wantTransitions: 1,
+		},
+		{
+			name:            "out-of-order sent after completion",
+			stored:          transfers.StatusCompleted,
+			to:              transfers.StatusSent,
+			wantAccepted:    false,
+			wantStatus:      transfers.StatusCompleted,
+			wantTransitions: 1,
+		},
+		{
+			name:            "duplicate webhook",
+			stored:          transfers.StatusCompleted,
+			to:              transfers.StatusCompleted,
+			wantAccepted:    true,
+			wantStatus:      transfers.StatusCompleted,
+			wantTransitions: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository()
+			repo.transfers["tr_1"] = storedTransfer(tt.stored)
+			service := newTestService(repo, &fakeClient{})
+
+			event := transfers.Transfer{ProviderTransferID: testProviderTransferID, FailureReason: tt.failureReason}
+			transition, err := service.TransitionTransfer(context.Background(), event, tt.to, "evt_1", time.Now())
+			if err != nil {
+				t.Fatalf("TransitionTransfer() error = %v", err)
+			}
+			if transition.Accepted != tt.wantAccepted {
+				t.Errorf("accepted = %v, want %v", transition.Accepted, tt.wantAccepted)
+			}
+
+			stored := repo.transfers["tr_1"]
+			if stored.Status != tt.wantStatus {
+				t.Errorf("stored status = %q, want %q", stored.Status, tt.wantStatus)
+			}
+			if stored.FailureReason != tt.wantFailure {
+				t.Errorf("failure reason = %q, want %q", stored.FailureReason, tt.wantFailure)
+			}
+			if len(repo.transitions) != tt.wantTransitions {
+				t.Errorf("recorded %d transitions, want %d", len(repo.transitions), tt.wantTransitions)
+			}
+		})
+	}
+}
+
+func TestTransitionTransferDuplicateEvents(t *testing.T) {
+	repo := newFakeRepository()
+	repo.transfers["tr_1"] = storedTransfer(transfers.StatusSent)
+	service := newTestService(repo, &fakeClient{})
+	event := transfers.Transfer{ProviderTransferID: testProviderTransferID}
+
+	// The provider retries the completion, then sends a late failure
+	for _, to := range []transfers.Status{transfers.StatusCompleted, transfers.StatusCompleted, transfers.StatusFailed, transfers.StatusFailed} {
+		if _, err := service.TransitionTransfer(context.Background(), event, to, "evt_"+string(to), time.Now()); err != nil {
+			t.Fatalf("TransitionTransfer(%q) error = %v", to, err)
+		}
+	}
+
+	if got := repo.transfers["tr_1"].Status; got != transfers.StatusFailed {
+		t.Errorf("stored status = %q, want %q", got, transfers.StatusFailed)
+	}
+	if len(repo.transitions) != 2 {
+		t.Errorf("recorded %d transitions, want 2", len(repo.transitions))
+	}
+}
+
+func TestTransitionTransferCreatesUnknownTransfers(t *testing.T) {
+	repo := newFakeRepository()
+	service := newTestService(repo, &fakeClient{})
+
+	event := transfers.Transfer{ProviderTransferID: testProviderTransferID, ConsumerID: testConsumerID, Amount: 5_00}
+	transition, err := service.TransitionTransfer(context.Background(), event, transfers.StatusCompleted, "evt_1", time.Now())
+	if err != nil {
+		t.Fatalf("TransitionTransfer() error = %v", err)
+	}
+	if transition.From != transfers.StatusNone || !transition.Accepted {
+		t.Errorf("transition = %+v, want an accepted transition from no status", transition)
+	}
+
+	stored, err := repo.GetTransferByProviderID(context.Background(), testProviderTransferID)
+	if err != nil || stored == nil {
+		t.Fatalf("GetTransferByProviderID() = %v, %v, want the created transfer", stored, err)
+	}
+	if stored.Status != transfers.StatusCompleted {
+		t.Errorf("stored status = %q, want %q", stored.Status, transfers.StatusCompleted)
+	}
+}
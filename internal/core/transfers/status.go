@@ -0,0 +1,28 @@
+package transfers
+
+// statusTransitions lists the statuses a transfer may move to from each
+// status. Completed transfers may still fail because ACH returns can arrive
+// after settlement; failed is terminal.
+var statusTransitions = map[transferStatus][]transferStatus{
+	StatusNone:      {StatusPending, StatusSent, StatusCompleted, StatusFailed},
+	StatusPending:   {StatusSent, StatusCompleted, StatusFailed},
+	StatusSent:      {StatusCompleted, StatusFailed},
+	StatusCompleted: {StatusFailed},
+	StatusFailed:    {},
+}
+
+// CanTransition reports whether a transfer may move between the two statuses.
+func CanTransition(from, to Status) bool {
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidStatus reports whether the status is part of the transfer state machine.
+func IsValidStatus(status Status) bool {
+	_, ok := statusTransitions[status]
+	return ok
+}
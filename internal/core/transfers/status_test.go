@@ -0,0 +1,37 @@
+package transfers_test
+
+import (
+	"testing"
+
+	transfers "template/internal/core/transfers"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from transfers.Status
+		to   transfers.Status
+		want bool
+	}{
+		{from: transfers.StatusNone, to: transfers.StatusPending, want: true},
+		{from: transfers.StatusNone, to: transfers.StatusCompleted, want: true},
+		{from: transfers.StatusPending, to: transfers.StatusSent, want: true},
+		{from: transfers.StatusPending, to: transfers.StatusFailed, want: true},
+		{from: transfers.StatusSent, to: transfers.StatusCompleted, want: true},
+		// ACH returns arrive after settlement
+		{from: transfers.StatusCompleted, to: transfers.StatusFailed, want: true},
+		{from: transfers.StatusSent, to: transfers.StatusPending, want: false},
+		{from: transfers.StatusCompleted, to: transfers.StatusSent, want: false},
+		{from: transfers.StatusCompleted, to: transfers.StatusPending, want: false},
+		{from: transfers.StatusFailed, to: transfers.StatusCompleted, want: false},
+		{from: transfers.StatusFailed, to: transfers.StatusPending, want: false},
+		{from: transfers.StatusCompleted, to: transfers.StatusCompleted, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			if got := transfers.CanTransition(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
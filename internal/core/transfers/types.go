@@ -0,0 +1,67 @@
+package transfers
+
+import "time"
+
+// Internal types
+type transferStatus string
+
+type transferType string
+
+type transferDirection string
+
+// transfer is money moving into or out of a consumer's account. Amounts are
+// in minor units (cents) of Currency.
+type transfer struct {
+	ID                   string
+	ProviderTransferID   string
+	IdempotencyKey       string
+	ConsumerID           string
+	Type                 transferType
+	Direction            transferDirection
+	Status               transferStatus
+	Amount               int64
+	Currency             string
+	SourceAccountID      string
+	DestinationAccountID string
+	Description          string
+	FailureReason        string
+	StatusUpdatedAt      time.Time
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+type statusTransition struct {
+	TransferID    string
+	From          transferStatus
+	To            transferStatus
+	SourceEventID string
+	// Accepted is false for out-of-order transitions, which are recorded but
+	// not applied to the transfer.
+	Accepted   bool
+	Reason     string
+	OccurredAt time.Time
+	CreatedAt  time.Time
+}
+
+// initiateRequest asks to move Amount (in cents) between the consumer's
+// account and a counterparty. ACH transfers need the counterparty's linked
+// account; payments always settle against the partner FBO account.
+type initiateRequest struct {
+	UserID                string
+	ConsumerID            string
+	Type                  transferType
+	Direction             transferDirection
+	Amount                int64
+	Currency              string
+	CounterpartyAccountID string
+	Description           string
+	IdempotencyKey        string
+}
+
+// externalAccount is a bank account a consumer linked at the provider, the
+// counterparty of the consumer's ACH transfers.
+type externalAccount struct {
+	ID         string
+	ConsumerID string
+	Status     string
+}
@@ -0,0 +1,28 @@
+package webhooks
+
+import (
+	webhookSDK "template/packages/webhook-go"
+	"time"
+)
+
+const (
+	ReplayStoreMemory = "memory"
+	ReplayStoreMySQL  = "mysql"
+
+	DefaultMaxBodyBytes int64 = 1 << 20
+)
+
+// Config is how deliveries from one provider are received.
+type Config struct {
+	// Secrets are the active signing secrets, newest first. Several are
+	// configured while a secret is being rotated.
+	Secrets []webhookSDK.Secret
+	// Tolerance bounds the age of a signed webhook timestamp.
+	Tolerance time.Duration
+	// ReplayStore is where accepted signatures are remembered:
+	// ReplayStoreMemory for a single instance, ReplayStoreMySQL when several
+	// instances receive webhooks.
+	ReplayStore string
+	// MaxBodyBytes caps the size of a delivery.
+	MaxBodyBytes int64
+}
@@ -0,0 +1,180 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"template/internal/logger"
+	"template/packages/common-go"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDeadLetterLimit = 100
+	maxDeadLetterLimit     = 500
+)
+
+var (
+	ErrDeadLetterNotFound = common.AppError{
+		Code:    "NOT_FOUND",
+		Message: "dead-lettered webhook event not found",
+		Status:  http.StatusNotFound,
+	}
+	ErrDeadLetterResolved = common.AppError{
+		Code:    "ALREADY_RESOLVED",
+		Message: "dead-lettered webhook event has already been replayed successfully",
+		Status:  http.StatusConflict,
+	}
+	ErrDeadLetterReplaying = common.AppError{
+		Code:    "REPLAY_IN_PROGRESS",
+		Message: "dead-lettered webhook event is already being replayed",
+		Status:  http.StatusConflict,
+	}
+)
+
+type deadLetterManager struct {
+	logger   logger.Logger
+	repo     InboxRepository
+	handler  EventHandler
+	provider provider
+}
+
+func NewDeadLetterManager(
+	logger logger.Logger,
+	repo InboxRepository,
+	handler EventHandler,
+	provider provider,
+) DeadLetterManager {
+	if logger == nil {
+		return nil
+	}
+
+	return &deadLetterManager{
+		logger:   logger,
+		repo:     repo,
+		handler:  handler,
+		provider: provider,
+	}
+}
+
+func (m *deadLetterManager) GetDeadLetters(ctx context.Context, filter DeadLetterFilter) ([]DeadLetter, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultDeadLetterLimit
+	}
+	if filter.Limit > maxDeadLetterLimit {
+		filter.Limit = maxDeadLetterLimit
+	}
+
+	deadLetters, err := m.repo.ListDeadLetters(ctx, m.provider, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get dead-lettered webhook events")
+	}
+
+	return deadLetters, nil
+}
+
+func (m *deadLetterManager) GetDeadLetter(ctx context.Context, eventID string) (*DeadLetter, error) {
+	if eventID == "" {
+		return nil, errors.New("event ID is required")
+	}
+
+	deadLetter, err := m.repo.GetDeadLetter(ctx, m.provider, eventID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get dead-lettered webhook event")
+	}
+
+	if deadLetter == nil {
+		return nil, ErrDeadLetterNotFound
+	}
+
+	return deadLetter, nil
+}
+
+func (m *deadLetterManager) ReplayDeadLetter(ctx context.Context, eventID string) error {
+	deadLetter, err := m.GetDeadLetter(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	if deadLetter.ResolvedAt != nil {
+		return ErrDeadLetterResolved
+	}
+
+	return m.replay(ctx, *deadLetter)
+}
+
+func (m *deadLetterManager) ReplayDeadLetters(ctx context.Context, filter DeadLetterFilter) (ReplayResult, error) {
+	result := ReplayResult{
+		Replayed: []string{},
+		Failed:   map[string]string{},
+	}
+
+	deadLetters, err := m.GetDeadLetters(ctx, filter)
+	if err != nil {
+		return result, err
+	}
+
+	for _, deadLetter := range deadLetters {
+		if err := m.replay(ctx, deadLetter); err != nil {
+			result.Failed[deadLetter.Event.ID] = err.Error()
+			continue
+		}
+		result.Replayed = append(result.Replayed, deadLetter.Event.ID)
+	}
+
+	m.logger.Info("replayed dead-lettered webhook events",
+		zap.String("provider", string(m.provider)),
+		zap.Int("replayed", len(result.Replayed)),
+		zap.Int("failed", len(result.Failed)))
+
+	return result, nil
+}
+
+func (m *deadLetterManager) replay(ctx context.Context, deadLetter DeadLetter) error {
+	// Claiming the event back from the dead-letter store keeps concurrent
+	// replays from processing it twice
+	claimed, err := m.repo.ClaimWebhookEvent(ctx, m.provider, deadLetter.Event.ID, EventStatusFailed, EventStatusRetrying)
+	if err != nil {
+		return errors.Wrap(err, "failed to claim webhook event")
+	}
+	if !claimed {
+		return ErrDeadLetterReplaying
+	}
+	deadLetter.Event.Status = EventStatusRetrying
+
+	now := time.Now()
+	deadLetter.ReplayCount++
+	deadLetter.LastReplayedAt = &now
+
+	processErr := m.handler.Process(ctx, deadLetter.Event)
+	if processErr != nil {
+		// Keep the event dead-lettered with the latest attempt count and error
+		event := deadLetter.Event
+		event.Attempts++
+		if err := m.handler.Fail(ctx, event, processErr); err != nil {
+			return errors.Wrap(err, "failed to re-dead-letter webhook event")
+		}
+
+		lastError := processErr.Error()
+		deadLetter.Event.LastError = &lastError
+	} else {
+		deadLetter.ResolvedAt = &now
+	}
+
+	if err := m.repo.UpdateDeadLetterReplay(ctx, deadLetter); err != nil {
+		return errors.Wrap(err, "failed to record webhook event replay")
+	}
+
+	if processErr != nil {
+		return errors.Wrap(processErr, "failed to replay webhook event")
+	}
+
+	m.logger.Info("replayed dead-lettered webhook event",
+		zap.String("eventID", deadLetter.Event.ID),
+		zap.String("topic", string(deadLetter.Event.Topic)),
+		zap.Int("replayCount", deadLetter.ReplayCount))
+
+	return nil
+}
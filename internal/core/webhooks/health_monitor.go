@@ -0,0 +1,271 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"template/internal/logger"
+	"template/packages/common-go"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var ErrHealthCheckLocked = common.AppError{
+	Code:    "CONFLICT",
+	Message: "webhook subscription health is being checked by another instance",
+	Status:  http.StatusConflict,
+}
+
+type healthMonitor struct {
+	logger   logger.Logger
+	client   SubscriptionClient
+	repo     Repository
+	provider provider
+
+	// mu only guards the state below, the check itself runs under the shared
+	// health lock.
+	mu sync.Mutex
+	// failures holds the failure count per registration seen by the previous
+	// check, so growth is flagged once rather than on every check.
+	failures map[string]int64
+	// unhealthy holds the registrations the previous check found issues
+	// with, so alerts go out when a registration turns unhealthy or recovers
+	// rather than on every check.
+	unhealthy map[string]bool
+	last      *HealthReport
+}
+
+// healthChange is a registration that turned unhealthy or recovered since
+// the previous check.
+type healthChange struct {
+	health    SubscriptionHealth
+	recovered bool
+}
+
+func NewHealthMonitor(
+	logger logger.Logger,
+	client SubscriptionClient,
+	repo Repository,
+	provider provider,
+) HealthMonitor {
+	if logger == nil {
+		return nil
+	}
+
+	return &healthMonitor{
+		logger:   logger,
+		client:   client,
+		repo:     repo,
+		provider: provider,
+	}
+}
+
+// Check runs under a lock shared by every instance, so a change in health is
+// alerted on by one instance only.
+func (m *healthMonitor) Check(ctx context.Context) (HealthReport, error) {
+	report := HealthReport{
+		Provider:      m.provider,
+		CheckedAt:     time.Now(),
+		Subscriptions: []SubscriptionHealth{},
+	}
+
+	unlock, acquired, err := m.repo.TryLock(ctx, fmt.Sprintf("webhooks.%s.health", m.provider))
+	if err != nil {
+		return report, errors.Wrap(err, "failed to lock webhook health check")
+	}
+	if !acquired {
+		return report, ErrHealthCheckLocked
+	}
+	defer unlock()
+
+	local, err := m.repo.GetAllWebhooksByProvider(ctx, m.provider)
+	if err != nil {
+		return report, errors.Wrap(err, "failed to get webhooks from database")
+	}
+
+	remote, err := m.client.GetAllWebhooks(ctx)
+	if err != nil {
+		return report, errors.Wrap(err, "failed to get webhooks from provider")
+	}
+
+	// The previous maps are replaced rather than changed, so they can be
+	// read without holding mu
+	m.mu.Lock()
+	previousFailures, previouslyUnhealthy := m.failures, m.unhealthy
+	m.mu.Unlock()
+
+	remoteByID := make(map[string]Webhook, len(remote))
+	for _, webhook := range remote {
+		remoteByID[webhook.ID] = webhook
+	}
+
+	failures := make(map[string]int64, len(remote))
+	unhealthy := map[string]bool{}
+	var changes []healthChange
+	record := func(health SubscriptionHealth) {
+		id := health.Webhook.ID
+		report.Total++
+		report.Subscriptions = append(report.Subscriptions, health)
+
+		if len(health.Issues) == 0 {
+			if previouslyUnhealthy[id] {
+				changes = append(changes, healthChange{health: health, recovered: true})
+			}
+			return
+		}
+
+		report.Unhealthy++
+		unhealthy[id] = true
+		if !previouslyUnhealthy[id] {
+			changes = append(changes, healthChange{health: health})
+		}
+	}
+
+	localByID := make(map[string]bool, len(local))
+	for _, stored := range local {
+		localByID[stored.ID] = true
+
+		previous, seen := previousFailures[stored.ID]
+		if !seen {
+			// The stored count is the best baseline before the first check
+			previous = stored.Failures
+		}
+
+		health := SubscriptionHealth{
+			Webhook:          stored,
+			Issues:           []HealthIssue{},
+			PreviousFailures: previous,
+		}
+
+		current, ok := remoteByID[stored.ID]
+		if !ok {
+			health.Issues = append(health.Issues, HealthIssueMissingRemotely)
+			failures[stored.ID] = previous
+		} else {
+			current.Provider = m.provider
+			health.Webhook = current
+			health.Issues = append(health.Issues, remoteIssues(current, previous)...)
+			failures[stored.ID] = current.Failures
+		}
+
+		record(health)
+	}
+
+	// Registrations only the provider knows about still deliver events, but
+	// nothing here tracks or reconciles them
+	for _, current := range remote {
+		if localByID[current.ID] {
+			continue
+		}
+
+		previous, seen := previousFailures[current.ID]
+		if !seen {
+			previous = current.Failures
+		}
+
+		current.Provider = m.provider
+		health := SubscriptionHealth{
+			Webhook:          current,
+			Issues:           []HealthIssue{HealthIssueMissingLocally},
+			PreviousFailures: previous,
+		}
+		health.Issues = append(health.Issues, remoteIssues(current, previous)...)
+		failures[current.ID] = current.Failures
+
+		record(health)
+	}
+
+	m.mu.Lock()
+	m.failures = failures
+	m.unhealthy = unhealthy
+	m.last = &report
+	m.mu.Unlock()
+
+	for _, change := range changes {
+		if change.recovered {
+			m.recovered(change.health)
+		} else {
+			m.alert(change.health)
+		}
+	}
+
+	m.logger.Info("checked webhook subscription health",
+		zap.String("provider", string(m.provider)),
+		zap.Int("total", report.Total),
+		zap.Int("unhealthy", report.Unhealthy))
+
+	return report, nil
+}
+
+func remoteIssues(current Webhook, previousFailures int64) []HealthIssue {
+	var issues []HealthIssue
+	if !strings.EqualFold(current.Status, WebhookStatusActive) {
+		issues = append(issues, HealthIssueInactive)
+	}
+	if current.Failures > previousFailures {
+		issues = append(issues, HealthIssueFailuresGrew)
+	}
+
+	return issues
+}
+
+func (m *healthMonitor) LastReport() *HealthReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.last == nil {
+		return nil
+	}
+
+	report := *m.last
+	return &report
+}
+
+func (m *healthMonitor) alert(health SubscriptionHealth) {
+	// Failures alone may be transient, but an inactive or missing
+	// registration drops every event for its topic
+	level := sentry.LevelWarning
+	issues := make([]string, len(health.Issues))
+	for i, issue := range health.Issues {
+		issues[i] = string(issue)
+		if issue != HealthIssueFailuresGrew {
+			level = sentry.LevelError
+		}
+	}
+
+	fields := []zap.Field{
+		zap.String("provider", string(m.provider)),
+		zap.String("webhookID", health.Webhook.ID),
+		zap.String("topic", string(health.Webhook.WebhookName)),
+		zap.String("status", health.Webhook.Status),
+		zap.Int64("failures", health.Webhook.Failures),
+		zap.Int64("previousFailures", health.PreviousFailures),
+		zap.Strings("issues", issues),
+	}
+	if health.Webhook.LastFailure != nil {
+		fields = append(fields, zap.Time("lastFailure", *health.Webhook.LastFailure))
+	}
+	m.logger.Warn("unhealthy webhook subscription", fields...)
+
+	m.logger.CaptureMessage(fmt.Sprintf("unhealthy %s webhook subscription %s (%s): %s",
+		m.provider, health.Webhook.ID, health.Webhook.WebhookName, strings.Join(issues, ", ")),
+		level)
+}
+
+func (m *healthMonitor) recovered(health SubscriptionHealth) {
+	m.logger.Info("webhook subscription recovered",
+		zap.String("provider", string(m.provider)),
+		zap.String("webhookID", health.Webhook.ID),
+		zap.String("topic", string(health.Webhook.WebhookName)),
+		zap.String("status", health.Webhook.Status),
+		zap.Int64("failures", health.Webhook.Failures))
+
+	m.logger.CaptureMessage(fmt.Sprintf("%s webhook subscription %s (%s) recovered",
+		m.provider, health.Webhook.ID, health.Webhook.WebhookName),
+		sentry.LevelInfo)
+}
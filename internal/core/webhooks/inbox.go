@@ -0,0 +1,257 @@
+package webhooks
+
+import (
+	"context"
+	"template/internal/logger"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// resumeAfter leaves events another instance updated recently to that
+// instance when resuming
+const resumeAfter = 5 * time.Minute
+
+type inbox struct {
+	logger     logger.Logger
+	processor  Processor
+	repo       InboxRepository
+	dispatcher Dispatcher
+	provider   provider
+}
+
+func NewInbox(
+	logger logger.Logger,
+	processor Processor,
+	repo InboxRepository,
+	dispatcher Dispatcher,
+	provider provider,
+) Inbox {
+	if logger == nil {
+		return nil
+	}
+
+	return &inbox{
+		logger:     logger,
+		processor:  processor,
+		repo:       repo,
+		dispatcher: dispatcher,
+		provider:   provider,
+	}
+}
+
+func (i *inbox) Receive(ctx context.Context, body []byte, headers map[string]string) (bool, error) {
+	event, err := i.processor.ParseEvent(body)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to parse webhook event")
+	}
+
+	if event.ID == "" {
+		return false, errors.New("webhook event ID is required")
+	}
+
+	event.Provider = i.provider
+	event.Payload = body
+	event.Headers = headers
+	event.Status = EventStatusPending
+
+	created, err := i.repo.SaveWebhookEvent(ctx, event)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to save webhook event")
+	}
+
+	if !created {
+		i.logger.Info("skipping duplicate webhook event",
+			zap.String("eventID", event.ID),
+			zap.String("topic", string(event.Topic)),
+			zap.String("provider", string(i.provider)))
+		return false, nil
+	}
+
+	// The event is stored, so a dispatch failure is deferred to the next sweep
+	// and must not fail the delivery.
+	if err := i.dispatcher.Dispatch(ctx, event, i); err != nil {
+		i.deferEvent(ctx, event, err)
+	}
+
+	return true, nil
+}
+
+func (i *inbox) Resume(ctx context.Context) error {
+	events, err := i.repo.ListWebhookEventsByStatus(ctx, i.provider, []EventStatus{EventStatusPending, EventStatusRetrying})
+	if err != nil {
+		return errors.Wrap(err, "failed to list unfinished webhook events")
+	}
+
+	updatedBefore := time.Now().Add(-resumeAfter)
+	resumed, failed := 0, 0
+	for _, event := range events {
+		// Claiming the event first keeps other instances from resuming it too
+		claimed, err := i.repo.ClaimStaleWebhookEvent(ctx, i.provider, event.ID, event.Status, updatedBefore)
+		if err != nil {
+			return errors.Wrapf(err, "failed to claim webhook event %s", event.ID)
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := i.dispatcher.Dispatch(ctx, event, i); err != nil {
+			i.deferEvent(ctx, event, err)
+			failed++
+			continue
+		}
+		resumed++
+	}
+
+	i.logger.Info("resumed unfinished webhook events",
+		zap.String("provider", string(i.provider)),
+		zap.Int("count", resumed),
+		zap.Int("deferred", failed))
+
+	if failed > 0 {
+		return errors.Errorf("failed to dispatch %d of %d webhook events", failed, resumed+failed)
+	}
+
+	return nil
+}
+
+func (i *inbox) Sweep(ctx context.Context) (int, error) {
+	events, err := i.repo.ListWebhookEventsByStatus(ctx, i.provider, []EventStatus{EventStatusDeferred})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list deferred webhook events")
+	}
+
+	dispatched := 0
+	for _, event := range events {
+		event.Status = EventStatusPending
+		if event.Attempts > 0 {
+			event.Status = EventStatusRetrying
+		}
+
+		// Claiming the event first keeps other instances from dispatching it
+		// too
+		claimed, err := i.repo.ClaimWebhookEvent(ctx, i.provider, event.ID, EventStatusDeferred, event.Status)
+		if err != nil {
+			return dispatched, errors.Wrapf(err, "failed to claim webhook event %s", event.ID)
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := i.dispatcher.Dispatch(ctx, event, i); err != nil {
+			i.deferEvent(ctx, event, err)
+			continue
+		}
+		dispatched++
+	}
+
+	return dispatched, nil
+}
+
+func (i *inbox) Process(ctx context.Context, event Event) error {
+	// Claiming the attempt keeps an event that is queued twice, e.g. by a
+	// resume during a rolling deploy, from being processed twice
+	claimed, err := i.repo.ClaimWebhookEventAttempt(ctx, i.provider, event.ID, event.Attempts)
+	if err != nil {
+		return errors.Wrap(err, "failed to claim webhook event attempt")
+	}
+	if !claimed {
+		i.logger.Debug("skipping webhook event finished or attempted elsewhere",
+			zap.String("eventID", event.ID),
+			zap.Int("attempts", event.Attempts))
+		return nil
+	}
+
+	event.Attempts++
+
+	err = i.processor.Process(ctx, event.Payload, event.Headers)
+	if errors.Is(err, ErrSkipEvent) {
+		i.logger.Warn("skipping webhook event",
+			zap.Error(err),
+			zap.String("eventID", event.ID),
+			zap.String("topic", string(event.Topic)))
+
+		now := time.Now()
+		lastError := err.Error()
+		event.Status = EventStatusSkipped
+		event.LastError = &lastError
+		event.ProcessedAt = &now
+		err = nil
+	} else if err != nil {
+		i.logger.Warn("failed to process webhook event",
+			zap.Error(err),
+			zap.String("eventID", event.ID),
+			zap.String("topic", string(event.Topic)),
+			zap.Int("attempts", event.Attempts))
+
+		lastError := err.Error()
+		event.Status = EventStatusRetrying
+		event.LastError = &lastError
+	} else {
+		now := time.Now()
+		event.Status = EventStatusProcessed
+		event.LastError = nil
+		event.ProcessedAt = &now
+	}
+
+	if updateErr := i.repo.UpdateWebhookEventStatus(ctx, event); updateErr != nil {
+		i.logger.Error("failed to update webhook event status",
+			zap.Error(updateErr),
+			zap.String("eventID", event.ID),
+			zap.String("status", string(event.Status)))
+		if err == nil {
+			return errors.Wrap(updateErr, "failed to update webhook event status")
+		}
+	}
+
+	return err
+}
+
+func (i *inbox) Fail(ctx context.Context, event Event, cause error) error {
+	lastError := cause.Error()
+	event.Status = EventStatusFailed
+	event.LastError = &lastError
+
+	if err := i.repo.DeadLetterWebhookEvent(ctx, event); err != nil {
+		return errors.Wrap(err, "failed to dead-letter webhook event")
+	}
+
+	i.logger.Error("webhook event moved to dead-letter store",
+		zap.Error(cause),
+		zap.String("eventID", event.ID),
+		zap.String("topic", string(event.Topic)),
+		zap.Int("attempts", event.Attempts))
+
+	return nil
+}
+
+func (i *inbox) Defer(ctx context.Context, event Event, cause error) error {
+	lastError := cause.Error()
+	event.Status = EventStatusDeferred
+	event.LastError = &lastError
+
+	if err := i.repo.UpdateWebhookEventStatus(ctx, event); err != nil {
+		return errors.Wrap(err, "failed to defer webhook event")
+	}
+
+	i.logger.Warn("webhook event deferred to the next sweep",
+		zap.Error(cause),
+		zap.String("eventID", event.ID),
+		zap.String("topic", string(event.Topic)),
+		zap.Int("attempts", event.Attempts))
+
+	return nil
+}
+
+// deferEvent defers an event that could not be dispatched, logging when even
+// that fails. The event then stays pending until the next Resume.
+func (i *inbox) deferEvent(ctx context.Context, event Event, cause error) {
+	if err := i.Defer(ctx, event, cause); err != nil {
+		i.logger.Error("failed to dispatch webhook event",
+			zap.Error(cause),
+			zap.NamedError("deferError", err),
+			zap.String("eventID", event.ID),
+			zap.String("topic", string(event.Topic)))
+	}
+}
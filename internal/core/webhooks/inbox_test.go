@@ -0,0 +1,444 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	webhooks "template/internal/core/webhooks"
+	"template/internal/logger"
+)
+
+// fakeInboxRepository stores events and dead letters in memory. Events are
+// unique per ID, as in the database.
+type fakeInboxRepository struct {
+	events      map[string]webhooks.Event
+	order       []string
+	deadLetters map[string]webhooks.DeadLetter
+}
+
+func newFakeInboxRepository() *fakeInboxRepository {
+	return &fakeInboxRepository{
+		events:      map[string]webhooks.Event{},
+		deadLetters: map[string]webhooks.DeadLetter{},
+	}
+}
+
+func (r *fakeInboxRepository) SaveWebhookEvent(ctx context.Context, event webhooks.Event) (bool, error) {
+	if _, ok := r.events[event.ID]; ok {
+		return false, nil
+	}
+
+	r.events[event.ID] = event
+	r.order = append(r.order, event.ID)
+	return true, nil
+}
+
+func (r *fakeInboxRepository) GetWebhookEvent(ctx context.Context, provider webhooks.Provider, id string) (*webhooks.Event, error) {
+	event, ok := r.events[id]
+	if !ok {
+		return nil, nil
+	}
+	return &event, nil
+}
+
+func (r *fakeInboxRepository) ListWebhookEventsByStatus(ctx context.Context, provider webhooks.Provider, statuses []webhooks.EventStatus) ([]webhooks.Event, error) {
+	var events []webhooks.Event
+	for _, status := range statuses {
+		for _, id := range r.order {
+			if r.events[id].Status == status {
+				events = append(events, r.events[id])
+			}
+		}
+	}
+	return events, nil
+}
+
+func (r *fakeInboxRepository) UpdateWebhookEventStatus(ctx context.Context, event webhooks.Event) error {
+	r.events[event.ID] = event
+	return nil
+}
+
+func (r *fakeInboxRepository) ClaimWebhookEvent(ctx context.Context, provider webhooks.Provider, id string, from, to webhooks.EventStatus) (bool, error) {
+	event, ok := r.events[id]
+	if !ok || event.Status != from {
+		return false, nil
+	}
+
+	event.Status = to
+	r.events[id] = event
+	return true, nil
+}
+
+func (r *fakeInboxRepository) ClaimStaleWebhookEvent(ctx context.Context, provider webhooks.Provider, id string, status webhooks.EventStatus, updatedBefore time.Time) (bool, error) {
+	event, ok := r.events[id]
+	if !ok || event.Status != status || !event.UpdatedAt.Before(updatedBefore) {
+		return false, nil
+	}
+
+	event.UpdatedAt = time.Now()
+	r.events[id] = event
+	return true, nil
+}
+
+func (r *fakeInboxRepository) ClaimWebhookEventAttempt(ctx context.Context, provider webhooks.Provider, id string, attempts int) (bool, error) {
+	event, ok := r.events[id]
+	if !ok || event.Attempts != attempts {
+		return false, nil
+	}
+	if event.Status != webhooks.EventStatusPending && event.Status != webhooks.EventStatusRetrying {
+		return false, nil
+	}
+
+	event.Attempts++
+	r.events[id] = event
+	return true, nil
+}
+
+func (r *fakeInboxRepository) DeadLetterWebhookEvent(ctx context.Context, event webhooks.Event) error {
+	r.events[event.ID] = event
+
+	deadLetter := r.deadLetters[event.ID]
+	deadLetter.Event = event
+	deadLetter.FailedAt = time.Now()
+	r.deadLetters[event.ID] = deadLetter
+	return nil
+}
+
+func (r *fakeInboxRepository) GetDeadLetter(ctx context.Context, provider webhooks.Provider, eventID string) (*webhooks.DeadLetter, error) {
+	deadLetter, ok := r.deadLetters[eventID]
+	if !ok {
+		return nil, nil
+	}
+	return &deadLetter, nil
+}
+
+func (r *fakeInboxRepository) ListDeadLetters(ctx context.Context, provider webhooks.Provider, filter webhooks.DeadLetterFilter) ([]webhooks.DeadLetter, error) {
+	var deadLetters []webhooks.DeadLetter
+	for _, id := range r.order {
+		if deadLetter, ok := r.deadLetters[id]; ok && deadLetter.ResolvedAt == nil {
+			deadLetters = append(deadLetters, deadLetter)
+		}
+	}
+	return deadLetters, nil
+}
+
+func (r *fakeInboxRepository) UpdateDeadLetterReplay(ctx context.Context, deadLetter webhooks.DeadLetter) error {
+	r.deadLetters[deadLetter.Event.ID] = deadLetter
+	return nil
+}
+
+// fakeProcessor parses {"id", "topic"} bodies and returns the errors in
+// order, then nil.
+type fakeProcessor struct {
+	errs      []error
+	processed []string
+}
+
+func (p *fakeProcessor) ParseEvent(body []byte) (webhooks.Event, error) {
+	var payload struct {
+		ID    string `json:"id"`
+		Topic string `json:"topic"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return webhooks.Event{}, err
+	}
+
+	return webhooks.Event{ID: payload.ID, Topic: webhooks.SubscriptionTopic(payload.Topic)}, nil
+}
+
+func (p *fakeProcessor) Process(ctx context.Context, body []byte, headers map[string]string) error {
+	p.processed = append(p.processed, string(body))
+	if len(p.errs) == 0 {
+		return nil
+	}
+
+	err := p.errs[0]
+	p.errs = p.errs[1:]
+	return err
+}
+
+type dispatched struct {
+	event   webhooks.Event
+	handler webhooks.EventHandler
+}
+
+// fakeDispatcher queues events until run and fails the first failures
+// dispatches.
+type fakeDispatcher struct {
+	maxRetries int
+	failures   int
+	queued     []dispatched
+}
+
+func (d *fakeDispatcher) Dispatch(ctx context.Context, event webhooks.Event, handler webhooks.EventHandler) error {
+	if d.failures > 0 {
+		d.failures--
+		return errors.New("job queue is full")
+	}
+
+	d.queued = append(d.queued, dispatched{event: event, handler: handler})
+	return nil
+}
+
+// run processes the queued events the way the scheduler does, retrying each
+// one from its stored attempts until maxRetries is used up.
+func (d *fakeDispatcher) run(t *testing.T) {
+	t.Helper()
+	ctx := context.Background()
+
+	for _, job := range d.queued {
+		for retryCount := job.event.Attempts; ; retryCount++ {
+			event := job.event
+			event.Attempts = retryCount
+
+			err := job.handler.Process(ctx, event)
+			if err == nil {
+				break
+			}
+			if retryCount < d.maxRetries {
+				continue
+			}
+
+			event.Attempts++
+			if err := job.handler.Fail(ctx, event, err); err != nil {
+				t.Fatalf("fail: %v", err)
+			}
+			break
+		}
+	}
+	d.queued = nil
+}
+
+func newTestInbox(repo *fakeInboxRepository, processor *fakeProcessor, dispatcher *fakeDispatcher) webhooks.Inbox {
+	return webhooks.NewInbox(&logger.NoOpLogger{}, processor, repo, dispatcher, webhooks.ProviderUpwardli)
+}
+
+func body(id string) []byte {
+	return []byte(`{"id":"` + id + `","topic":"Consumer.Created"}`)
+}
+
+func receive(t *testing.T, inbox webhooks.Inbox, ids ...string) {
+	t.Helper()
+
+	for _, id := range ids {
+		if _, err := inbox.Receive(context.Background(), body(id), map[string]string{}); err != nil {
+			t.Fatalf("receive %s: %v", id, err)
+		}
+	}
+}
+
+func TestReceiveSkipsDuplicateEvents(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeInboxRepository()
+	processor := &fakeProcessor{}
+	dispatcher := &fakeDispatcher{maxRetries: 3}
+	inbox := newTestInbox(repo, processor, dispatcher)
+
+	created, err := inbox.Receive(ctx, body("evt_1"), map[string]string{})
+	if err != nil || !created {
+		t.Fatalf("receive = %v, %v, want a new event", created, err)
+	}
+
+	// The provider redelivers the event
+	created, err = inbox.Receive(ctx, body("evt_1"), map[string]string{})
+	if err != nil || created {
+		t.Fatalf("receive duplicate = %v, %v, want a duplicate", created, err)
+	}
+
+	dispatcher.run(t)
+
+	if len(processor.processed) != 1 {
+		t.Errorf("processed %d times, want once", len(processor.processed))
+	}
+	event := repo.events["evt_1"]
+	if event.Status != webhooks.EventStatusProcessed || event.Attempts != 1 {
+		t.Errorf("event = %s after %d attempts, want processed after 1", event.Status, event.Attempts)
+	}
+}
+
+func TestProcessOutcomes(t *testing.T) {
+	tests := []struct {
+		name           string
+		errs           []error
+		wantStatus     webhooks.EventStatus
+		wantAttempts   int
+		wantDeadLetter bool
+	}{
+		{
+			name:         "processed",
+			wantStatus:   webhooks.EventStatusProcessed,
+			wantAttempts: 1,
+		},
+		{
+			name:         "processed after retries",
+			errs:         []error{errors.New("database unavailable"), errors.New("database unavailable")},
+			wantStatus:   webhooks.EventStatusProcessed,
+			wantAttempts: 3,
+		},
+		{
+			name:           "retries exhausted",
+			errs:           []error{errors.New("a"), errors.New("b"), errors.New("c"), errors.New("d")},
+			wantStatus:     webhooks.EventStatusFailed,
+			wantAttempts:   3,
+			wantDeadLetter: true,
+		},
+		{
+			name:         "skipped",
+			errs:         []error{webhooks.Skip(errors.New("event has no resources"))},
+			wantStatus:   webhooks.EventStatusSkipped,
+			wantAttempts: 1,
+		},
+		{
+			name:         "unknown topic",
+			errs:         []error{webhooks.Skip(webhooks.ErrUnknownTopic)},
+			wantStatus:   webhooks.EventStatusSkipped,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeInboxRepository()
+			processor := &fakeProcessor{errs: tt.errs}
+			dispatcher := &fakeDispatcher{maxRetries: 2}
+			inbox := newTestInbox(repo, processor, dispatcher)
+
+			receive(t, inbox, "evt_1")
+			dispatcher.run(t)
+
+			event := repo.events["evt_1"]
+			if event.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", event.Status, tt.wantStatus)
+			}
+			if event.Attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", event.Attempts, tt.wantAttempts)
+			}
+			if _, ok := repo.deadLetters["evt_1"]; ok != tt.wantDeadLetter {
+				t.Errorf("dead-lettered = %v, want %v", ok, tt.wantDeadLetter)
+			}
+		})
+	}
+}
+
+func TestReplayDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeInboxRepository()
+	processor := &fakeProcessor{errs: []error{errors.New("a"), errors.New("b")}}
+	dispatcher := &fakeDispatcher{maxRetries: 1}
+	inbox := newTestInbox(repo, processor, dispatcher)
+	deadLetters := webhooks.NewDeadLetterManager(&logger.NoOpLogger{}, repo, inbox, webhooks.ProviderUpwardli)
+
+	receive(t, inbox, "evt_1")
+	dispatcher.run(t)
+	if status := repo.events["evt_1"].Status; status != webhooks.EventStatusFailed {
+		t.Fatalf("status = %s, want %s", status, webhooks.EventStatusFailed)
+	}
+
+	if err := deadLetters.ReplayDeadLetter(ctx, "evt_1"); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+
+	event := repo.events["evt_1"]
+	if event.Status != webhooks.EventStatusProcessed || event.Attempts != 3 {
+		t.Errorf("event = %s after %d attempts, want processed after 3", event.Status, event.Attempts)
+	}
+	if repo.deadLetters["evt_1"].ResolvedAt == nil {
+		t.Error("dead letter is not resolved")
+	}
+	if err := deadLetters.ReplayDeadLetter(ctx, "evt_1"); !errors.Is(err, webhooks.ErrDeadLetterResolved) {
+		t.Errorf("second replay: err = %v, want %v", err, webhooks.ErrDeadLetterResolved)
+	}
+}
+
+func TestUndispatchedEventsAreSwept(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeInboxRepository()
+	processor := &fakeProcessor{}
+	dispatcher := &fakeDispatcher{maxRetries: 3, failures: 1}
+	inbox := newTestInbox(repo, processor, dispatcher)
+
+	// The webhook is still acknowledged, since the event is stored
+	receive(t, inbox, "evt_1")
+	if status := repo.events["evt_1"].Status; status != webhooks.EventStatusDeferred {
+		t.Fatalf("status = %s, want %s", status, webhooks.EventStatusDeferred)
+	}
+
+	swept, err := inbox.Sweep(ctx)
+	if err != nil {
+		t.Fatalf("sweep: %v", err)
+	}
+	if swept != 1 {
+		t.Errorf("swept %d events, want 1", swept)
+	}
+	if swept, _ := inbox.Sweep(ctx); swept != 0 {
+		t.Errorf("second sweep dispatched %d events, want 0", swept)
+	}
+
+	dispatcher.run(t)
+
+	if status := repo.events["evt_1"].Status; status != webhooks.EventStatusProcessed {
+		t.Errorf("status = %s, want %s", status, webhooks.EventStatusProcessed)
+	}
+}
+
+func TestResumeClaimsEvents(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeInboxRepository()
+	processor := &fakeProcessor{}
+	dispatcher := &fakeDispatcher{maxRetries: 3}
+	inbox := newTestInbox(repo, processor, dispatcher)
+
+	receive(t, inbox, "evt_1", "evt_2")
+	dispatcher.queued = nil
+
+	// The second event was just updated by the instance working on it
+	recent := repo.events["evt_2"]
+	recent.UpdatedAt = time.Now()
+	repo.events["evt_2"] = recent
+
+	// Two instances start at the same time during a rolling deploy
+	other := &fakeDispatcher{maxRetries: 3}
+	otherInbox := newTestInbox(repo, processor, other)
+	if err := inbox.Resume(ctx); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if err := otherInbox.Resume(ctx); err != nil {
+		t.Fatalf("resume on the other instance: %v", err)
+	}
+
+	if len(dispatcher.queued) != 1 || dispatcher.queued[0].event.ID != "evt_1" {
+		t.Errorf("resumed %d events, want only evt_1", len(dispatcher.queued))
+	}
+	if len(other.queued) != 0 {
+		t.Errorf("other instance resumed %d events, want 0", len(other.queued))
+	}
+}
+
+func TestProcessSkipsUnclaimableEvents(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeInboxRepository()
+	processor := &fakeProcessor{}
+	dispatcher := &fakeDispatcher{maxRetries: 3}
+	inbox := newTestInbox(repo, processor, dispatcher)
+
+	receive(t, inbox, "evt_1")
+
+	// The event ends up queued twice, e.g. on two instances
+	dispatcher.queued = append(dispatcher.queued, dispatcher.queued[0])
+	dispatcher.run(t)
+
+	if len(processor.processed) != 1 {
+		t.Errorf("processed %d times, want once", len(processor.processed))
+	}
+
+	// A processed event is not processed again
+	if err := inbox.Process(ctx, repo.events["evt_1"]); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	if len(processor.processed) != 1 {
+		t.Errorf("processed %d times after processing again, want once", len(processor.processed))
+	}
+}
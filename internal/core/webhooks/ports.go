@@ -2,25 +2,118 @@ package webhooks
 
 import (
 	"context"
+	"time"
 )
 
 // external types
 type Processor interface {
+	// ParseEvent extracts the provider event identity from a raw webhook body.
+	ParseEvent(body []byte) (Event, error)
 	Process(ctx context.Context, body []byte, headers map[string]string) error
 }
 
+type TopicHandler interface {
+	// Handle processes an event whose topic the handler was registered for.
+	// payload is the provider entity the event refers to.
+	Handle(ctx context.Context, event Event, payload []byte) error
+}
+
+type TopicRegistry interface {
+	// Register adds the handler for a topic. Each topic has at most one handler.
+	Register(topic SubscriptionTopic, handler TopicHandler) error
+	// Lookup returns the handler for a topic, or a skip error matching
+	// ErrUnknownTopic when none is registered.
+	Lookup(topic SubscriptionTopic) (TopicHandler, error)
+	// Topics returns the topics with a registered handler.
+	Topics() []SubscriptionTopic
+}
+
+type ProviderRegistry interface {
+	// Register adds the implementations for a provider. Each provider is
+	// registered at most once.
+	Register(provider Provider, registration ProviderRegistration) error
+	// Lookup returns the implementations for a provider, or ErrUnknownProvider
+	// when none are registered.
+	Lookup(provider Provider) (ProviderRegistration, error)
+	Providers() []Provider
+}
+
 type Verifier interface {
-	Verify(body []byte, headers map[string]string) error
+	// Verify returns an error for a delivery that was not signed by the
+	// provider, or that was already received.
+	Verify(ctx context.Context, body []byte, headers map[string]string) error
+	// Release forgets a verified delivery, so the provider's retry is
+	// accepted when the delivery could not be stored.
+	Release(ctx context.Context, body []byte, headers map[string]string) error
+}
+
+type Locker interface {
+	// TryLock takes a lock shared by every instance of the service. It
+	// returns false when another instance holds it. unlock releases it.
+	TryLock(ctx context.Context, name string) (unlock func(), acquired bool, err error)
 }
 
 type Repository interface {
+	Locker
+
 	GetAllWebhooksByProvider(ctx context.Context, provider provider) ([]Webhook, error)
 	CreateWebhook(ctx context.Context, webhook Webhook) error
+	// UpdateWebhookStatus stores the status, failures and last failure the
+	// provider reports for a registration.
+	UpdateWebhookStatus(ctx context.Context, webhook Webhook) error
 	SoftDeleteWebhook(ctx context.Context, provider Provider, id string) error
 }
 
+type NonceRepository interface {
+	// SaveWebhookNonce records a nonce until expiresAt. It returns false when
+	// an unexpired record for the nonce already exists.
+	SaveWebhookNonce(ctx context.Context, provider Provider, nonce string, expiresAt time.Time) (bool, error)
+	DeleteWebhookNonce(ctx context.Context, provider Provider, nonce string) error
+	DeleteExpiredWebhookNonces(ctx context.Context, provider Provider) (int64, error)
+}
+
+type EventRepository interface {
+	// SaveWebhookEvent stores a received event. It returns false when an event
+	// with the same provider and ID has already been stored.
+	SaveWebhookEvent(ctx context.Context, event Event) (bool, error)
+	GetWebhookEvent(ctx context.Context, provider Provider, id string) (*Event, error)
+	ListWebhookEventsByStatus(ctx context.Context, provider Provider, statuses []EventStatus) ([]Event, error)
+	UpdateWebhookEventStatus(ctx context.Context, event Event) error
+	// ClaimWebhookEvent moves an event from one status to another. It returns
+	// false when the event is no longer in the from status, e.g. because
+	// another instance claimed it first.
+	ClaimWebhookEvent(ctx context.Context, provider Provider, id string, from, to EventStatus) (bool, error)
+	// ClaimStaleWebhookEvent claims an event in the given status that was not
+	// updated since updatedBefore by touching it. It returns false when the
+	// event changed in the meantime.
+	ClaimStaleWebhookEvent(ctx context.Context, provider Provider, id string, status EventStatus, updatedBefore time.Time) (bool, error)
+	// ClaimWebhookEventAttempt counts an attempt of a pending or retrying
+	// event that has made the given number of attempts. It returns false
+	// when the event was finished, deferred or attempted elsewhere in the
+	// meantime.
+	ClaimWebhookEventAttempt(ctx context.Context, provider Provider, id string, attempts int) (bool, error)
+}
+
+type DeadLetterRepository interface {
+	// DeadLetterWebhookEvent marks the event as failed and copies it into the
+	// dead-letter store.
+	DeadLetterWebhookEvent(ctx context.Context, event Event) error
+	// GetDeadLetter returns nil when no dead letter exists for the event ID.
+	GetDeadLetter(ctx context.Context, provider Provider, eventID string) (*DeadLetter, error)
+	ListDeadLetters(ctx context.Context, provider Provider, filter DeadLetterFilter) ([]DeadLetter, error)
+	UpdateDeadLetterReplay(ctx context.Context, deadLetter DeadLetter) error
+}
+
+type InboxRepository interface {
+	EventRepository
+	DeadLetterRepository
+}
+
 type SubscriptionClient interface {
 	GetAllWebhooks(ctx context.Context) ([]Webhook, error)
+	// GetWebhook returns nil when the provider has no registration with the
+	// given ID.
+	GetWebhook(ctx context.Context, webhookID string) (*Webhook, error)
 	CreateWebhook(ctx context.Context, endpoint string, topic string) (*Webhook, error)
 	DeleteWebhook(ctx context.Context, webhookID string) error
 }
@@ -30,6 +123,64 @@ type WebhookManager interface {
 	CreateWebhook(ctx context.Context, endpoint string, topicName SubscriptionTopic) error
 	GetWebhooks(ctx context.Context) ([]Webhook, error)
 	DeleteWebhook(ctx context.Context, id string) error
+	// Reconcile brings the local registrations in line with the provider's.
+	// With DryRun set it only reports the differences.
+	Reconcile(ctx context.Context, options ReconcileOptions) (ReconcileReport, error)
+	// Sync converges the provider's registrations at the desired endpoint on
+	// the desired topics, creating and deleting only what differs. With dryRun
+	// set it only returns the plan.
+	Sync(ctx context.Context, desired DesiredState, dryRun bool) (SyncResult, error)
+}
+
+type Dispatcher interface {
+	// Dispatch queues an event to be run through the handler asynchronously.
+	Dispatch(ctx context.Context, event Event, handler EventHandler) error
+}
+
+type EventHandler interface {
+	// Process runs a single processing attempt. event.Attempts holds the
+	// number of attempts made before this one. It does nothing when the
+	// event was finished or attempted elsewhere in the meantime.
+	Process(ctx context.Context, event Event) error
+	// Fail records that an event will not be retried any further.
+	Fail(ctx context.Context, event Event, cause error) error
+	// Defer records that an event could not be queued, so Sweep dispatches
+	// it again later.
+	Defer(ctx context.Context, event Event, cause error) error
+}
+
+type Inbox interface {
+	EventHandler
+
+	// Receive persists an inbound webhook and hands it off for asynchronous
+	// processing. It returns false when the event is a duplicate delivery.
+	Receive(ctx context.Context, body []byte, headers map[string]string) (bool, error)
+	// Resume re-dispatches events that were stored but never finished, e.g.
+	// because the service restarted while they were queued. Only events left
+	// untouched for a while are claimed, so those another instance is still
+	// working on are left alone. An event that can't be dispatched is
+	// deferred and the others are still resumed.
+	Resume(ctx context.Context) error
+	// Sweep re-dispatches the deferred events and returns how many were
+	// queued.
+	Sweep(ctx context.Context) (int, error)
+}
+
+type DeadLetterManager interface {
+	GetDeadLetters(ctx context.Context, filter DeadLetterFilter) ([]DeadLetter, error)
+	GetDeadLetter(ctx context.Context, eventID string) (*DeadLetter, error)
+	ReplayDeadLetter(ctx context.Context, eventID string) error
+	ReplayDeadLetters(ctx context.Context, filter DeadLetterFilter) (ReplayResult, error)
+}
+
+type HealthMonitor interface {
+	// Check compares the stored registrations with the provider's, alerting
+	// when one turns unhealthy and again when it recovers. It returns
+	// ErrHealthCheckLocked while another instance is checking.
+	Check(ctx context.Context) (HealthReport, error)
+	// LastReport returns the report of the latest check, or nil before the
+	// first one.
+	LastReport() *HealthReport
 }
 
 type Service interface {
@@ -39,8 +190,48 @@ type Service interface {
 type SubscriptionTopic = subscriptionTopic
 type Webhook = webhook
 type Provider = provider
+type Event = event
+type EventStatus = eventStatus
+type DeadLetter = deadLetter
+type DeadLetterFilter = deadLetterFilter
+type ReplayResult = replayResult
+type ReconcileOptions = reconcileOptions
+type ReconcileReport = reconcileReport
+type WebhookChange = webhookChange
+type DesiredState = desiredState
+type SyncPlan = syncPlan
+type SyncResult = syncResult
+type HealthIssue = healthIssue
+type SubscriptionHealth = subscriptionHealth
+type HealthReport = healthReport
+type ProviderRegistration = providerRegistration
+type VerifiedDelivery = verifiedDelivery
 
 const (
 	ProviderApril    provider = "april"
 	ProviderUpwardli provider = "upwardli"
 )
+
+// WebhookStatusActive is the status the provider reports for a registration
+// that is delivering events.
+const WebhookStatusActive = "active"
+
+const (
+	HealthIssueInactive        healthIssue = "inactive"
+	HealthIssueFailuresGrew    healthIssue = "failures_grew"
+	HealthIssueMissingRemotely healthIssue = "missing_remotely"
+	HealthIssueMissingLocally  healthIssue = "missing_locally"
+)
+
+const (
+	EventStatusPending   eventStatus = "pending"
+	EventStatusRetrying  eventStatus = "retrying"
+	EventStatusProcessed eventStatus = "processed"
+	EventStatusFailed    eventStatus = "failed"
+	// EventStatusSkipped marks an event that was acknowledged without being
+	// handled, e.g. because its topic is unknown.
+	EventStatusSkipped eventStatus = "skipped"
+	// EventStatusDeferred marks an event that could not be queued, e.g.
+	// because the dispatch queue was full. Sweep dispatches it again.
+	EventStatusDeferred eventStatus = "deferred"
+)
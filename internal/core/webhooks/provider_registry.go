@@ -0,0 +1,93 @@
+package webhooks
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"template/packages/common-go"
+
+	"github.com/pkg/errors"
+)
+
+var ErrUnknownProvider = common.AppError{
+	Code:    "NOT_FOUND",
+	Message: "unknown webhook provider",
+	Status:  http.StatusNotFound,
+}
+
+// IsValidProvider reports whether the provider is one of the declared
+// providers.
+func IsValidProvider(provider Provider) bool {
+	switch provider {
+	case ProviderApril, ProviderUpwardli:
+		return true
+	default:
+		return false
+	}
+}
+
+type providerRegistry struct {
+	mu            sync.RWMutex
+	registrations map[provider]ProviderRegistration
+}
+
+func NewProviderRegistry() ProviderRegistry {
+	return &providerRegistry{
+		registrations: make(map[provider]ProviderRegistration),
+	}
+}
+
+func (r *providerRegistry) Register(provider Provider, registration ProviderRegistration) error {
+	if provider == "" {
+		return errors.New("provider is required")
+	}
+	// Deliveries are only accepted once verified, so a provider can't be
+	// registered without a verifier
+	if registration.Verifier == nil {
+		return errors.Errorf("verifier for provider %s is required", provider)
+	}
+	if registration.Processor == nil {
+		return errors.Errorf("processor for provider %s is required", provider)
+	}
+	if registration.Client == nil {
+		return errors.Errorf("subscription client for provider %s is required", provider)
+	}
+	if registration.Inbox == nil {
+		return errors.Errorf("inbox for provider %s is required", provider)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.registrations[provider]; exists {
+		return errors.Errorf("provider %s is already registered", provider)
+	}
+
+	r.registrations[provider] = registration
+	return nil
+}
+
+func (r *providerRegistry) Lookup(provider Provider) (ProviderRegistration, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	registration, ok := r.registrations[provider]
+	if !ok {
+		return ProviderRegistration{}, ErrUnknownProvider.WithMessagef("unknown webhook provider: %s", provider)
+	}
+
+	return registration, nil
+}
+
+func (r *providerRegistry) Providers() []Provider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	providers := make([]Provider, 0, len(r.registrations))
+	for provider := range r.registrations {
+		providers = append(providers, provider)
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i] < providers[j] })
+
+	return providers
+}
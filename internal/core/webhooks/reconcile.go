@@ -0,0 +1,171 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"template/packages/common-go"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var ErrSubscriptionsLocked = common.AppError{
+	Code:    "CONFLICT",
+	Message: "webhook subscriptions are being changed by another instance",
+	Status:  http.StatusConflict,
+}
+
+// lockSubscriptions keeps instances from changing the provider's
+// registrations at the same time, which would create duplicates.
+func (w *webhookManager) lockSubscriptions(ctx context.Context) (func(), error) {
+	unlock, acquired, err := w.repo.TryLock(ctx, fmt.Sprintf("webhooks.%s.subscriptions", w.provider))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to lock webhook subscriptions")
+	}
+	if !acquired {
+		return nil, ErrSubscriptionsLocked
+	}
+
+	return unlock, nil
+}
+
+func (w *webhookManager) Reconcile(ctx context.Context, options ReconcileOptions) (ReconcileReport, error) {
+	report := ReconcileReport{
+		DryRun:    options.DryRun,
+		Updated:   []WebhookChange{},
+		Removed:   []Webhook{},
+		Adopted:   []Webhook{},
+		Missing:   []SubscriptionTopic{},
+		Recreated: []SubscriptionTopic{},
+		Errors:    map[string]string{},
+	}
+
+	if !options.DryRun {
+		unlock, err := w.lockSubscriptions(ctx)
+		if err != nil {
+			return report, err
+		}
+		defer unlock()
+	}
+
+	remote, err := w.client.GetAllWebhooks(ctx)
+	if err != nil {
+		return report, errors.Wrap(err, "failed to get webhooks from provider")
+	}
+
+	local, err := w.repo.GetAllWebhooksByProvider(ctx, w.provider)
+	if err != nil {
+		return report, errors.Wrap(err, "failed to get webhooks from database")
+	}
+
+	remoteByID := make(map[string]Webhook, len(remote))
+	for _, webhook := range remote {
+		webhook.Provider = w.provider
+		remoteByID[webhook.ID] = webhook
+	}
+
+	localByID := make(map[string]Webhook, len(local))
+	for _, stored := range local {
+		localByID[stored.ID] = stored
+
+		current, ok := remoteByID[stored.ID]
+		if !ok {
+			// The list may be missing registrations, e.g. when a page was
+			// skipped, so each removal is confirmed on its own
+			removed, err := w.client.GetWebhook(ctx, stored.ID)
+			if err != nil {
+				report.Errors[stored.ID] = err.Error()
+				continue
+			}
+			if removed != nil {
+				removed.Provider = w.provider
+				remoteByID[stored.ID] = *removed
+				current = *removed
+			} else {
+				report.Removed = append(report.Removed, stored)
+				if !options.DryRun {
+					if err := w.repo.SoftDeleteWebhook(ctx, w.provider, stored.ID); err != nil {
+						report.Errors[stored.ID] = err.Error()
+					}
+				}
+				continue
+			}
+		}
+
+		if !webhookStatusChanged(stored, current) {
+			continue
+		}
+
+		updated := stored
+		updated.Status = current.Status
+		updated.Failures = current.Failures
+		updated.LastFailure = current.LastFailure
+
+		report.Updated = append(report.Updated, WebhookChange{Before: stored, After: updated})
+		if !options.DryRun {
+			if err := w.repo.UpdateWebhookStatus(ctx, updated); err != nil {
+				report.Errors[stored.ID] = err.Error()
+			}
+		}
+	}
+
+	subscribed := map[SubscriptionTopic]bool{}
+	for _, webhook := range remote {
+		if options.Endpoint != "" && webhook.Endpoint != options.Endpoint {
+			continue
+		}
+		subscribed[webhook.WebhookName] = true
+
+		if _, ok := localByID[webhook.ID]; ok {
+			continue
+		}
+
+		webhook = remoteByID[webhook.ID]
+		report.Adopted = append(report.Adopted, webhook)
+		if !options.DryRun {
+			if err := w.repo.CreateWebhook(ctx, webhook); err != nil {
+				report.Errors[webhook.ID] = err.Error()
+			}
+		}
+	}
+
+	for _, topic := range options.RequiredTopics {
+		if subscribed[topic] {
+			continue
+		}
+
+		report.Missing = append(report.Missing, topic)
+		if options.DryRun || !options.RecreateMissing {
+			continue
+		}
+
+		if err := w.CreateWebhook(ctx, options.Endpoint, topic); err != nil {
+			report.Errors[string(topic)] = err.Error()
+			continue
+		}
+		report.Recreated = append(report.Recreated, topic)
+	}
+
+	w.logger.Info("reconciled webhook subscriptions",
+		zap.String("provider", string(w.provider)),
+		zap.Bool("dryRun", options.DryRun),
+		zap.Int("updated", len(report.Updated)),
+		zap.Int("removed", len(report.Removed)),
+		zap.Int("adopted", len(report.Adopted)),
+		zap.Int("missing", len(report.Missing)),
+		zap.Int("recreated", len(report.Recreated)),
+		zap.Int("errors", len(report.Errors)))
+
+	return report, nil
+}
+
+func webhookStatusChanged(stored, current Webhook) bool {
+	if stored.Status != current.Status || stored.Failures != current.Failures {
+		return true
+	}
+	if (stored.LastFailure == nil) != (current.LastFailure == nil) {
+		return true
+	}
+	return stored.LastFailure != nil && !stored.LastFailure.Equal(*current.LastFailure)
+}
@@ -0,0 +1,123 @@
+package webhooks
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+func (w *webhookManager) Sync(ctx context.Context, desired DesiredState, dryRun bool) (SyncResult, error) {
+	result := SyncResult{
+		DryRun:  dryRun,
+		Created: []SubscriptionTopic{},
+		Deleted: []string{},
+		Errors:  map[string]string{},
+	}
+
+	if desired.Provider != w.provider {
+		return result, errors.Errorf("desired state is for provider %s, not %s", desired.Provider, w.provider)
+	}
+	if desired.Endpoint == "" {
+		return result, errors.New("endpoint is required")
+	}
+
+	if !dryRun {
+		unlock, err := w.lockSubscriptions(ctx)
+		if err != nil {
+			return result, err
+		}
+		defer unlock()
+	}
+
+	remote, err := w.client.GetAllWebhooks(ctx)
+	if err != nil {
+		return result, errors.Wrap(err, "failed to get webhooks from provider")
+	}
+
+	result.Plan = planSync(desired, remote)
+
+	if dryRun {
+		w.logSync(result)
+		return result, nil
+	}
+
+	// Delete first so a topic is never delivered twice while converging
+	for _, webhook := range result.Plan.Delete {
+		if err := w.DeleteWebhook(ctx, webhook.ID); err != nil {
+			result.Errors[webhook.ID] = err.Error()
+			continue
+		}
+		result.Deleted = append(result.Deleted, webhook.ID)
+	}
+
+	for _, topic := range result.Plan.Create {
+		if err := w.CreateWebhook(ctx, desired.Endpoint, topic); err != nil {
+			result.Errors[string(topic)] = err.Error()
+			continue
+		}
+		result.Created = append(result.Created, topic)
+	}
+
+	w.logSync(result)
+
+	return result, nil
+}
+
+func (w *webhookManager) logSync(result SyncResult) {
+	w.logger.Info("synced webhook subscriptions",
+		zap.String("provider", string(w.provider)),
+		zap.Bool("dryRun", result.DryRun),
+		zap.Int("keep", len(result.Plan.Keep)),
+		zap.Int("create", len(result.Plan.Create)),
+		zap.Int("delete", len(result.Plan.Delete)),
+		zap.Int("created", len(result.Created)),
+		zap.Int("deleted", len(result.Deleted)),
+		zap.Int("errors", len(result.Errors)))
+}
+
+// planSync diffs the provider's registrations at the desired endpoint against
+// the desired topics. The first active registration for a desired topic is
+// kept and any further ones are deleted as duplicates. Registrations that
+// aren't active don't deliver, so they are deleted and replaced.
+func planSync(desired DesiredState, remote []Webhook) SyncPlan {
+	plan := SyncPlan{
+		Create: []SubscriptionTopic{},
+		Delete: []Webhook{},
+		Keep:   []Webhook{},
+	}
+
+	wanted := make(map[SubscriptionTopic]bool, len(desired.Topics))
+	for _, topic := range desired.Topics {
+		wanted[topic] = true
+	}
+
+	kept := map[SubscriptionTopic]bool{}
+	for _, webhook := range remote {
+		if webhook.Endpoint != desired.Endpoint {
+			continue
+		}
+		webhook.Provider = desired.Provider
+
+		active := strings.EqualFold(webhook.Status, WebhookStatusActive)
+		if !wanted[webhook.WebhookName] || kept[webhook.WebhookName] || !active {
+			plan.Delete = append(plan.Delete, webhook)
+			continue
+		}
+
+		kept[webhook.WebhookName] = true
+		plan.Keep = append(plan.Keep, webhook)
+	}
+
+	for _, topic := range desired.Topics {
+		if kept[topic] {
+			continue
+		}
+		// Guards against a topic listed twice in the desired state
+		kept[topic] = true
+		plan.Create = append(plan.Create, topic)
+	}
+
+	return plan
+}
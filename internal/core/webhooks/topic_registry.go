@@ -0,0 +1,136 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"template/internal/logger"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrUnknownTopic = errors.New("no handler registered for webhook topic")
+	// ErrSkipEvent matches errors that end the processing of an event for
+	// good, e.g. an unknown topic. The event is acknowledged without retries.
+	ErrSkipEvent = errors.New("webhook event skipped")
+)
+
+type skipError struct {
+	err error
+}
+
+func (e skipError) Error() string {
+	return e.err.Error()
+}
+
+func (e skipError) Unwrap() error {
+	return e.err
+}
+
+func (e skipError) Is(target error) bool {
+	return target == ErrSkipEvent
+}
+
+// Skip marks err as terminal, so the event is not retried.
+func Skip(err error) error {
+	if err == nil {
+		return nil
+	}
+	return skipError{err: err}
+}
+
+type topicHandlerFunc func(ctx context.Context, event Event, payload []byte) error
+
+func (f topicHandlerFunc) Handle(ctx context.Context, event Event, payload []byte) error {
+	return f(ctx, event, payload)
+}
+
+// NewTopicHandler wraps a handler that receives the payload decoded as T.
+func NewTopicHandler[T any](handle func(ctx context.Context, event Event, payload T) error) TopicHandler {
+	return topicHandlerFunc(func(ctx context.Context, event Event, payload []byte) error {
+		var decoded T
+		if err := json.Unmarshal(payload, &decoded); err != nil {
+			return errors.Wrapf(err, "failed to decode payload for topic %s", event.Topic)
+		}
+		return handle(ctx, event, decoded)
+	})
+}
+
+type topicRegistry struct {
+	logger   logger.Logger
+	provider provider
+	mu       sync.RWMutex
+	handlers map[subscriptionTopic]TopicHandler
+	// unknownTopics counts the events per unknown topic for the warning
+	unknownTopics map[subscriptionTopic]int64
+}
+
+func NewTopicRegistry(logger logger.Logger, provider provider) TopicRegistry {
+	if logger == nil {
+		return nil
+	}
+
+	return &topicRegistry{
+		logger:        logger,
+		provider:      provider,
+		handlers:      make(map[subscriptionTopic]TopicHandler),
+		unknownTopics: make(map[subscriptionTopic]int64),
+	}
+}
+
+func (r *topicRegistry) Register(topic SubscriptionTopic, handler TopicHandler) error {
+	if topic == "" {
+		return errors.New("topic is required")
+	}
+	if handler == nil {
+		return errors.Errorf("handler for topic %s is required", topic)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.handlers[topic]; exists {
+		return errors.Errorf("handler already registered for topic %s", topic)
+	}
+
+	r.handlers[topic] = handler
+	return nil
+}
+
+func (r *topicRegistry) Lookup(topic SubscriptionTopic) (TopicHandler, error) {
+	r.mu.RLock()
+	handler, ok := r.handlers[topic]
+	r.mu.RUnlock()
+
+	if ok {
+		return handler, nil
+	}
+
+	r.mu.Lock()
+	r.unknownTopics[topic]++
+	count := r.unknownTopics[topic]
+	r.mu.Unlock()
+
+	r.logger.Warn("received webhook for unknown topic",
+		zap.String("topic", string(topic)),
+		zap.String("provider", string(r.provider)),
+		zap.Int64("count", count))
+
+	return nil, Skip(errors.Wrapf(ErrUnknownTopic, "topic %s", topic))
+}
+
+func (r *topicRegistry) Topics() []SubscriptionTopic {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	topics := make([]SubscriptionTopic, 0, len(r.handlers))
+	for topic := range r.handlers {
+		topics = append(topics, topic)
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i] < topics[j] })
+
+	return topics
+}
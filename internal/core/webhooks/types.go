@@ -7,6 +7,8 @@ type subscriptionTopic string
 
 type provider string
 
+type eventStatus string
+
 type webhook struct {
 	ID          string
 	WebhookName subscriptionTopic
@@ -24,3 +26,133 @@ type webhook struct {
 	// only available when registering a webhook
 	RegistrationID string
 }
+
+type event struct {
+	ID          string
+	Topic       subscriptionTopic
+	Payload     []byte
+	Headers     map[string]string
+	Status      eventStatus
+	Attempts    int
+	LastError   *string
+	ProcessedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	Provider provider
+
+	// only available while processing, when reported by the provider
+	OccurredAt *time.Time
+}
+
+type deadLetter struct {
+	Event          Event
+	FailedAt       time.Time
+	ReplayCount    int
+	LastReplayedAt *time.Time
+	ResolvedAt     *time.Time
+}
+
+type deadLetterFilter struct {
+	Topic        subscriptionTopic
+	FailedAfter  *time.Time
+	FailedBefore *time.Time
+	Limit        int
+}
+
+type replayResult struct {
+	Replayed []string
+	Failed   map[string]string
+}
+
+type reconcileOptions struct {
+	DryRun bool
+	// Endpoint is where our registrations deliver to. Provider registrations
+	// for other endpoints are left alone.
+	Endpoint string
+	// RequiredTopics are recreated when RecreateMissing is set and no
+	// registration delivers them to Endpoint.
+	RequiredTopics  []subscriptionTopic
+	RecreateMissing bool
+}
+
+type webhookChange struct {
+	Before Webhook
+	After  Webhook
+}
+
+type reconcileReport struct {
+	DryRun bool
+	// Updated registrations whose status or failures changed at the provider
+	Updated []webhookChange
+	// Removed registrations that no longer exist at the provider
+	Removed []Webhook
+	// Adopted registrations that exist at the provider but were not stored
+	Adopted []Webhook
+	// Missing required topics without a registration
+	Missing   []subscriptionTopic
+	Recreated []subscriptionTopic
+	// Errors maps a registration ID or topic to what went wrong with it
+	Errors map[string]string
+}
+
+type desiredState struct {
+	Provider provider
+	// Endpoint is where every subscription should deliver to. Provider
+	// registrations for other endpoints are never touched.
+	Endpoint string
+	Topics   []subscriptionTopic
+}
+
+type syncPlan struct {
+	// Create holds the desired topics without a registration at the endpoint
+	Create []subscriptionTopic
+	// Delete holds registrations at the endpoint for topics that are not
+	// desired, and duplicate registrations for a desired topic
+	Delete []Webhook
+	// Keep holds the registrations that already match the desired state
+	Keep []Webhook
+}
+
+type syncResult struct {
+	DryRun  bool
+	Plan    syncPlan
+	Created []subscriptionTopic
+	Deleted []string
+	// Errors maps a registration ID or topic to what went wrong with it
+	Errors map[string]string
+}
+
+type healthIssue string
+
+type subscriptionHealth struct {
+	Webhook Webhook
+	Issues  []healthIssue
+	// PreviousFailures is the failure count seen by the previous check
+	PreviousFailures int64
+}
+
+type healthReport struct {
+	Provider      provider
+	CheckedAt     time.Time
+	Total         int
+	Unhealthy     int
+	Subscriptions []subscriptionHealth
+}
+
+type providerRegistration struct {
+	Verifier  Verifier
+	Processor Processor
+	Client    SubscriptionClient
+	// Inbox receives the provider's verified deliveries
+	Inbox Inbox
+	// MaxBodyBytes caps the size of a delivery. DefaultMaxBodyBytes applies
+	// when it is zero.
+	MaxBodyBytes int64
+}
+
+type verifiedDelivery struct {
+	Body []byte
+	// Headers holds the request headers, repeated ones joined by commas
+	Headers map[string]string
+}
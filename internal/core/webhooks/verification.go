@@ -0,0 +1,78 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"template/packages/common-go"
+	webhookSDK "template/packages/webhook-go"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidSignature is returned by a Verifier for a delivery that is
+	// not signed by the provider.
+	ErrInvalidSignature = common.AppError{
+		Code:    "INVALID_SIGNATURE",
+		Message: "invalid webhook signature",
+		Status:  http.StatusUnauthorized,
+	}
+	// ErrReplayedWebhook is returned by a Verifier for a signed delivery that
+	// was already received.
+	ErrReplayedWebhook = common.AppError{
+		Code:    "REPLAYED_WEBHOOK",
+		Message: "webhook signature has already been used",
+		Status:  http.StatusConflict,
+	}
+)
+
+// VerificationError maps a webhook-go verification failure to the error a
+// Verifier returns, keeping the library's error code.
+func VerificationError(err error) error {
+	if errors.Is(err, webhookSDK.ErrReplayed) {
+		return ErrReplayedWebhook
+	}
+
+	var verificationErr *webhookSDK.VerificationError
+	if errors.As(err, &verificationErr) {
+		return common.AppError{
+			Code:    verificationErr.Code,
+			Message: verificationErr.Message,
+			Status:  http.StatusUnauthorized,
+		}
+	}
+
+	return errors.Wrap(err, "failed to verify webhook")
+}
+
+type nonceStore struct {
+	repo     NonceRepository
+	provider provider
+}
+
+// NewNonceStore is a webhook-go NonceStore shared by every instance, backed
+// by the provider's nonce table.
+func NewNonceStore(repo NonceRepository, provider provider) webhookSDK.NonceStore {
+	return &nonceStore{
+		repo:     repo,
+		provider: provider,
+	}
+}
+
+func (s *nonceStore) Remember(ctx context.Context, nonce string, expiresAt time.Time) (bool, error) {
+	saved, err := s.repo.SaveWebhookNonce(ctx, s.provider, nonce, expiresAt)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to save webhook nonce")
+	}
+
+	return saved, nil
+}
+
+func (s *nonceStore) Forget(ctx context.Context, nonce string) error {
+	if err := s.repo.DeleteWebhookNonce(ctx, s.provider, nonce); err != nil {
+		return errors.Wrap(err, "failed to delete webhook nonce")
+	}
+
+	return nil
+}
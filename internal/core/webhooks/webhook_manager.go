@@ -90,6 +90,8 @@ func (w *webhookManager) CreateWebhook(ctx context.Context, endpoint string, top
 				Status:      webhook.Status,
 				Failures:    webhook.Failures,
 				LastFailure: webhook.LastFailure,
+
+				Provider: w.provider,
 			}
 			break
 		}
@@ -133,14 +135,14 @@ func (w *webhookManager) DeleteWebhook(ctx context.Context, id string) error {
 		return errors.Wrap(err, "failed to delete webhook from Upwardli")
 	}
 
-	// Soft delete from database
+	// Soft delete from database. The registration is already gone at
+	// Upwardli, so a failure here is left to reconciliation to clean up
 	err = w.repo.SoftDeleteWebhook(ctx, w.provider, id)
 	if err != nil {
 		w.logger.Error("failed to delete webhook from database, but deleted from Upwardli",
 			zap.Error(err),
 			zap.String("webhookID", id),
 			zap.String("provider", string(w.provider)))
-		return errors.Wrap(err, "failed to delete webhook from database")
 	}
 
 	w.logger.Info("successfully deleted webhook", zap.String("webhookID", id))
@@ -2,67 +2,84 @@ package webhooks
 
 import (
 	"bytes"
-	"encoding/json"
-	"fmt"
+	"context"
 	"io"
 	"net/http"
 	"strings"
+	"template/internal/logger"
+	"template/packages/common-go"
 
-	webhookSDK "template/packages/webhook-go"
-
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
-func writeErrorResponse(w http.ResponseWriter, errorMessage string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusBadRequest)
-	errResp, _ := json.Marshal(map[string]string{
-		"error": errorMessage,
-	})
-	w.Write(errResp)
+var (
+	errUnreadableBody = common.AppError{
+		Code:    "INVALID_INPUT",
+		Message: "error reading body",
+		Status:  http.StatusBadRequest,
+	}
+	errBodyTooLarge = common.AppError{
+		Code:    "PAYLOAD_TOO_LARGE",
+		Message: "webhook body is too large",
+		Status:  http.StatusRequestEntityTooLarge,
+	}
+)
+
+type deliveryContextKey struct{}
+
+// DeliveryFromContext returns the delivery accepted by
+// WithWebhookVerification, so handlers don't read the body again.
+func DeliveryFromContext(ctx context.Context) (VerifiedDelivery, bool) {
+	delivery, ok := ctx.Value(deliveryContextKey{}).(VerifiedDelivery)
+	return delivery, ok
+}
+
+// FlattenHeaders joins repeated headers with commas, the form verifiers and
+// inboxes take them in.
+func FlattenHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for key, values := range header {
+		headers[key] = strings.Join(values, ",")
+	}
+
+	return headers
 }
 
-func WithWebhookVerification(verifier webhookSDK.WebhookVerifier, logger *zap.Logger) func(http.Handler) http.Handler {
+// WithWebhookVerification rejects requests the provider's verifier does not
+// accept, and passes the accepted delivery on in the request context. The
+// body is restored as well for handlers reading it. A body cut off by
+// http.MaxBytesReader is rejected as too large.
+func WithWebhookVerification(verifier Verifier, logger logger.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			body, err := io.ReadAll(r.Body)
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				common.WriteError(w, errBodyTooLarge.WithMessagef("webhook body exceeds %d bytes", maxBytesErr.Limit))
+				return
+			}
 			if err != nil {
-				writeErrorResponse(w, "error reading body")
+				common.WriteError(w, errUnreadableBody)
 				return
 			}
-
-			signature := r.Header.Get("Upwardli-Signature")
-			split := strings.Split(signature, ",")
-
-			logger.Debug("upwardli webhook called")
-
 			r.Body = io.NopCloser(bytes.NewBuffer(body))
 
-			mapping := make(map[string]string)
-			for _, element := range split {
-				keyValue := strings.Split(element, "=")
-				if len(keyValue) != 2 {
-					writeErrorResponse(w, "invalid signature format")
-					return
-				}
-				mapping[keyValue[0]] = keyValue[1]
-			}
-
-			requiredFields := []string{"t", "v1"}
-			for _, field := range requiredFields {
-				if val, ok := mapping[field]; !ok || val == "" {
-					writeErrorResponse(w, fmt.Sprintf("missing required field: %s", field))
-					return
-				}
-			}
+			headers := FlattenHeaders(r.Header)
 
-			compare := mapping["t"] + "." + string(body)
-			if !verifier.Verify([]byte(compare), []byte(mapping["v1"])) {
-				writeErrorResponse(w, "invalid signature")
+			if err := verifier.Verify(r.Context(), body, headers); err != nil {
+				logger.Warn("rejected webhook with invalid signature",
+					zap.Error(err),
+					zap.String("path", r.URL.Path))
+				common.WriteError(w, err)
 				return
 			}
 
-			next.ServeHTTP(w, r)
+			ctx := context.WithValue(r.Context(), deliveryContextKey{}, VerifiedDelivery{
+				Body:    body,
+				Headers: headers,
+			})
+			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
@@ -4,6 +4,7 @@ package logger
 import (
 	"context"
 	"net/http"
+	"template/packages/common-go"
 	"time"
 
 	"github.com/getsentry/sentry-go"
@@ -36,7 +37,10 @@ func Middleware(logger Logger) func(http.Handler) http.Handler {
 				zap.String("path", r.URL.Path),
 				zap.String("remote_addr", r.RemoteAddr),
 				zap.String("user_agent", r.UserAgent()),
-			).WithUserID(r.Context().Value("userID").(string))
+			)
+			if userID := common.UserIDFromContext(r.Context()); userID != "" {
+				requestLogger = requestLogger.WithUserID(userID)
+			}
 
 			// Add to context
 			ctx := context.WithValue(r.Context(), loggerContextKey, requestLogger)
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS upwardli.webhook_dead_letters (
+    event_id VARCHAR(255) NOT NULL PRIMARY KEY,
+    event_name VARCHAR(255) NOT NULL,
+    payload JSON NOT NULL,
+    headers JSON NOT NULL,
+    attempts INT NOT NULL,
+    last_error TEXT NOT NULL,
+    failed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    replay_count INT NOT NULL DEFAULT 0,
+    last_replayed_at TIMESTAMP NULL,
+    resolved_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_webhook_dead_letters_failed_at (failed_at)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS upwardli.webhook_dead_letters;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE upwardli.consumers
+    ADD PRIMARY KEY (id),
+    MODIFY tax_identifier VARCHAR(255) NOT NULL DEFAULT '',
+    MODIFY created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    MODIFY updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE upwardli.consumers
+    DROP PRIMARY KEY,
+    MODIFY tax_identifier VARCHAR(255) NOT NULL,
+    MODIFY created_at DATETIME NOT NULL,
+    MODIFY updated_at DATETIME NOT NULL;
+-- +goose StatementEnd
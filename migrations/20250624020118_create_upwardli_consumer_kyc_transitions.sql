@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS upwardli.consumer_kyc_transitions (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    consumer_id VARCHAR(255) NOT NULL,
+    from_status VARCHAR(32) NOT NULL,
+    to_status VARCHAR(32) NOT NULL,
+    source_event_id VARCHAR(255) NOT NULL,
+    accepted BOOLEAN NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    occurred_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_consumer_kyc_transitions_consumer_id (consumer_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS upwardli.consumer_kyc_transitions;
+-- +goose StatementEnd
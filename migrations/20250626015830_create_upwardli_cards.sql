@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS upwardli.payment_cards (
+    id VARCHAR(255) NOT NULL PRIMARY KEY,
+    consumer_id VARCHAR(255) NOT NULL,
+    last_four VARCHAR(4) NOT NULL,
+    card_type VARCHAR(32) NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    expiration_month INT NOT NULL,
+    expiration_year INT NOT NULL,
+    closed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_payment_cards_consumer_id (consumer_id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS upwardli.card_transactions (
+    id VARCHAR(255) NOT NULL PRIMARY KEY,
+    payment_card_id VARCHAR(255) NOT NULL,
+    consumer_id VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    merchant_name VARCHAR(255) NOT NULL DEFAULT '',
+    merchant_category_code VARCHAR(8) NOT NULL DEFAULT '',
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    settled_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_card_transactions_payment_card_id (payment_card_id, settled_at)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS upwardli.card_transactions;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS upwardli.payment_cards;
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS upwardli.transfers (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    upwardli_transfer_id VARCHAR(255) NULL,
+    consumer_id VARCHAR(255) NOT NULL,
+    transfer_type VARCHAR(32) NOT NULL,
+    direction VARCHAR(16) NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    source_account_id VARCHAR(255) NOT NULL DEFAULT '',
+    destination_account_id VARCHAR(255) NOT NULL DEFAULT '',
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    failure_reason VARCHAR(255) NOT NULL DEFAULT '',
+    status_updated_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_transfers_upwardli_transfer_id (upwardli_transfer_id),
+    INDEX idx_transfers_consumer_id (consumer_id, created_at)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS upwardli.transfer_status_transitions (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    transfer_id VARCHAR(36) NOT NULL,
+    from_status VARCHAR(32) NOT NULL,
+    to_status VARCHAR(32) NOT NULL,
+    source_event_id VARCHAR(255) NOT NULL,
+    accepted BOOLEAN NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    occurred_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_transfer_status_transitions_transfer_id (transfer_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS upwardli.transfer_status_transitions;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS upwardli.transfers;
+-- +goose StatementEnd
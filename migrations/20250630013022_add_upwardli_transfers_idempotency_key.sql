@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE upwardli.transfers
+    ADD COLUMN idempotency_key VARCHAR(255) NULL AFTER upwardli_transfer_id,
+    ADD UNIQUE KEY uq_transfers_idempotency_key (idempotency_key);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE upwardli.transfers
+    DROP KEY uq_transfers_idempotency_key,
+    DROP COLUMN idempotency_key;
+-- +goose StatementEnd
@@ -0,0 +1,66 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE DATABASE IF NOT EXISTS events CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS events.subscribers (
+    id VARCHAR(255) NOT NULL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    url VARCHAR(2048) NOT NULL,
+    event_types JSON NOT NULL,
+    max_retries INT NOT NULL DEFAULT 5,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS events.deliveries (
+    id VARCHAR(255) NOT NULL PRIMARY KEY,
+    subscriber_id VARCHAR(255) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(255) NOT NULL,
+    source VARCHAR(255) NOT NULL,
+    source_event_id VARCHAR(255) NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_status_code INT NULL,
+    last_error TEXT,
+    delivered_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_deliveries_event_subscriber (event_id, subscriber_id),
+    INDEX idx_deliveries_subscriber_id (subscriber_id),
+    INDEX idx_deliveries_status (status)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS events.delivery_attempts (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    delivery_id VARCHAR(255) NOT NULL,
+    attempt INT NOT NULL,
+    status_code INT NULL,
+    error_message TEXT,
+    duration_ms BIGINT NOT NULL,
+    attempted_at TIMESTAMP NOT NULL,
+    INDEX idx_delivery_attempts_delivery_id (delivery_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS events.delivery_attempts;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS events.deliveries;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS events.subscribers;
+-- +goose StatementEnd
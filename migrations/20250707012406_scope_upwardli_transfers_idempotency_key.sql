@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE upwardli.transfers
+    DROP KEY uq_transfers_idempotency_key,
+    ADD UNIQUE KEY uq_transfers_consumer_idempotency_key (consumer_id, idempotency_key);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE upwardli.transfers
+    DROP KEY uq_transfers_consumer_idempotency_key,
+    ADD UNIQUE KEY uq_transfers_idempotency_key (idempotency_key);
+-- +goose StatementEnd
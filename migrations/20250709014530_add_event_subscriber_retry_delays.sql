@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE events.subscribers
+    ADD COLUMN base_retry_delay_seconds INT NOT NULL DEFAULT 5 AFTER max_retries,
+    ADD COLUMN max_retry_delay_seconds INT NOT NULL DEFAULT 1800 AFTER base_retry_delay_seconds;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events.subscribers
+    DROP COLUMN max_retry_delay_seconds,
+    DROP COLUMN base_retry_delay_seconds;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE upwardli.webhooks
+    ADD COLUMN provider VARCHAR(32) NOT NULL DEFAULT 'upwardli',
+    DROP PRIMARY KEY,
+    ADD PRIMARY KEY (provider, id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE upwardli.webhook_nonces
+    ADD COLUMN provider VARCHAR(32) NOT NULL DEFAULT 'upwardli',
+    DROP PRIMARY KEY,
+    ADD PRIMARY KEY (provider, nonce);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM upwardli.webhook_nonces
+WHERE provider != 'upwardli';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE upwardli.webhook_nonces
+    DROP PRIMARY KEY,
+    DROP COLUMN provider,
+    ADD PRIMARY KEY (nonce);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DELETE FROM upwardli.webhooks
+WHERE provider != 'upwardli';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE upwardli.webhooks
+    DROP PRIMARY KEY,
+    DROP COLUMN provider,
+    ADD PRIMARY KEY (id);
+-- +goose StatementEnd
@@ -0,0 +1,277 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultBreakerWindow           = time.Minute
+	DefaultBreakerMinRequests      = 10
+	DefaultBreakerFailureRate      = 0.5
+	DefaultBreakerCooldown         = 30 * time.Second
+	DefaultBreakerHalfOpenRequests = 3
+)
+
+type BreakerState string
+
+const (
+	// BreakerClosed lets requests through and counts their failures.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen rejects requests until the cooldown has passed.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a few trial requests through to decide whether
+	// the host has recovered.
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// BreakerConfig configures the circuit breakers of a client. Each upstream
+// host gets its own breaker. Zero values are replaced by the
+// DefaultBreaker* values.
+type BreakerConfig struct {
+	// Window is how long failures are counted for before the counts reset.
+	Window time.Duration
+	// MinRequests is the number of requests in a window before the failure
+	// rate is considered, so a single failure can't open the circuit.
+	MinRequests int
+	// FailureRate opens the circuit once failures/requests reaches it.
+	FailureRate float64
+	// Cooldown is how long the circuit stays open before trial requests.
+	Cooldown time.Duration
+	// HalfOpenRequests is the number of trial requests that must all succeed
+	// to close the circuit again. Any failure reopens it.
+	HalfOpenRequests int
+	// IsFailure classifies an attempt. resp is nil when no response was
+	// received. Defaults to DefaultIsFailure.
+	IsFailure func(resp *http.Response, err error) bool
+	// OnStateChange is called after a host's breaker changes state.
+	OnStateChange func(host string, from, to BreakerState)
+}
+
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		Window:           DefaultBreakerWindow,
+		MinRequests:      DefaultBreakerMinRequests,
+		FailureRate:      DefaultBreakerFailureRate,
+		Cooldown:         DefaultBreakerCooldown,
+		HalfOpenRequests: DefaultBreakerHalfOpenRequests,
+		IsFailure:        DefaultIsFailure,
+	}
+}
+
+// DefaultIsFailure counts transport errors and 5xx responses as failures.
+// Other error responses mean the host is up and are not counted.
+func DefaultIsFailure(resp *http.Response, err error) bool {
+	if resp != nil {
+		return resp.StatusCode >= http.StatusInternalServerError
+	}
+
+	return err != nil && !errors.Is(err, context.Canceled)
+}
+
+// WithCircuitBreaker fails requests fast with ErrCircuitOpen while a host is
+// failing, instead of letting every request wait for its timeout.
+func WithCircuitBreaker(cfg BreakerConfig) ClientOption {
+	return func(c *Client) {
+		c.breakers = newBreakers(cfg)
+	}
+}
+
+type breakers struct {
+	cfg BreakerConfig
+	// now is the clock of windows and cooldowns
+	now func() time.Time
+	mu  sync.Mutex
+	// hosts is keyed by request host, including the port
+	hosts map[string]*breaker
+}
+
+type breaker struct {
+	state BreakerState
+	// generation changes with every state change, so results of requests
+	// allowed in an earlier state are dropped
+	generation  int
+	windowStart time.Time
+	openedAt    time.Time
+	requests    int
+	failures    int
+	// inFlight and successes track the trial requests while half-open
+	inFlight  int
+	successes int
+}
+
+type breakerResult int
+
+const (
+	breakerSuccess breakerResult = iota
+	breakerFailure
+	// breakerIgnored releases a trial slot without counting the attempt,
+	// e.g. when the caller cancelled it.
+	breakerIgnored
+)
+
+func newBreakers(cfg BreakerConfig) *breakers {
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultBreakerWindow
+	}
+	if cfg.MinRequests < 1 {
+		cfg.MinRequests = DefaultBreakerMinRequests
+	}
+	if cfg.FailureRate <= 0 {
+		cfg.FailureRate = DefaultBreakerFailureRate
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = DefaultBreakerCooldown
+	}
+	if cfg.HalfOpenRequests < 1 {
+		cfg.HalfOpenRequests = DefaultBreakerHalfOpenRequests
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = DefaultIsFailure
+	}
+
+	return &breakers{
+		cfg:   cfg,
+		now:   time.Now,
+		hosts: make(map[string]*breaker),
+	}
+}
+
+// BreakerState returns the state of a host's breaker.
+func (c *Client) BreakerState(host string) BreakerState {
+	if c.breakers == nil {
+		return BreakerClosed
+	}
+
+	c.breakers.mu.Lock()
+	defer c.breakers.mu.Unlock()
+
+	b, ok := c.breakers.hosts[host]
+	if !ok {
+		return BreakerClosed
+	}
+
+	return b.state
+}
+
+// allow reports whether a request to host may be sent. Every allowed request
+// must be followed by a call to record with the returned generation.
+func (bs *breakers) allow(host string) (int, error) {
+	bs.mu.Lock()
+	now := bs.now()
+
+	b, ok := bs.hosts[host]
+	if !ok {
+		b = &breaker{state: BreakerClosed, windowStart: now}
+		bs.hosts[host] = b
+	}
+
+	from := b.state
+	if b.state == BreakerOpen && now.Sub(b.openedAt) >= bs.cfg.Cooldown {
+		b.state = BreakerHalfOpen
+		b.generation++
+		b.inFlight = 0
+		b.successes = 0
+	}
+
+	var err error
+	switch b.state {
+	case BreakerOpen:
+		err = circuitOpenError(host)
+	case BreakerHalfOpen:
+		if b.inFlight+b.successes >= bs.cfg.HalfOpenRequests {
+			err = circuitOpenError(host)
+		} else {
+			b.inFlight++
+		}
+	}
+
+	to, generation := b.state, b.generation
+	bs.mu.Unlock()
+
+	bs.notify(host, from, to)
+	return generation, err
+}
+
+func (bs *breakers) record(host string, generation int, result breakerResult) {
+	bs.mu.Lock()
+
+	b := bs.hosts[host]
+	if b.generation != generation {
+		bs.mu.Unlock()
+		return
+	}
+	from := b.state
+	now := bs.now()
+
+	switch b.state {
+	case BreakerClosed:
+		if now.Sub(b.windowStart) >= bs.cfg.Window {
+			b.windowStart = now
+			b.requests = 0
+			b.failures = 0
+		}
+		if result == breakerIgnored {
+			break
+		}
+
+		b.requests++
+		if result == breakerFailure {
+			b.failures++
+		}
+		if b.requests >= bs.cfg.MinRequests && float64(b.failures)/float64(b.requests) >= bs.cfg.FailureRate {
+			b.open(now)
+		}
+	case BreakerHalfOpen:
+		b.inFlight--
+		switch result {
+		case breakerFailure:
+			b.open(now)
+		case breakerSuccess:
+			b.successes++
+			if b.successes >= bs.cfg.HalfOpenRequests {
+				b.close(now)
+			}
+		}
+	}
+
+	to := b.state
+	bs.mu.Unlock()
+
+	bs.notify(host, from, to)
+}
+
+func (bs *breakers) result(ctx context.Context, resp *http.Response, err error) breakerResult {
+	if ctx.Err() != nil {
+		return breakerIgnored
+	}
+	if bs.cfg.IsFailure(resp, err) {
+		return breakerFailure
+	}
+
+	return breakerSuccess
+}
+
+func (bs *breakers) notify(host string, from, to BreakerState) {
+	if from != to && bs.cfg.OnStateChange != nil {
+		bs.cfg.OnStateChange(host, from, to)
+	}
+}
+
+func (b *breaker) open(now time.Time) {
+	b.state = BreakerOpen
+	b.generation++
+	b.openedAt = now
+}
+
+func (b *breaker) close(now time.Time) {
+	b.state = BreakerClosed
+	b.generation++
+	b.windowStart = now
+	b.requests = 0
+	b.failures = 0
+}
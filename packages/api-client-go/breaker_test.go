@@ -0,0 +1,319 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+type transition struct {
+	host     string
+	from, to BreakerState
+}
+
+func newTestBreakers(cfg BreakerConfig) (*breakers, *fakeClock, *[]transition) {
+	clock := &fakeClock{now: time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)}
+	transitions := &[]transition{}
+	cfg.OnStateChange = func(host string, from, to BreakerState) {
+		*transitions = append(*transitions, transition{host: host, from: from, to: to})
+	}
+
+	bs := newBreakers(cfg)
+	bs.now = clock.Now
+	return bs, clock, transitions
+}
+
+func testBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		Window:           time.Minute,
+		MinRequests:      4,
+		FailureRate:      0.5,
+		Cooldown:         30 * time.Second,
+		HalfOpenRequests: 2,
+	}
+}
+
+// send runs a request to host through the breaker with the given result.
+func send(t *testing.T, bs *breakers, host string, result breakerResult) {
+	t.Helper()
+
+	generation, err := bs.allow(host)
+	if err != nil {
+		t.Fatalf("allow %s: %v", host, err)
+	}
+	bs.record(host, generation, result)
+}
+
+func (bs *breakers) state(host string) BreakerState {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	b, ok := bs.hosts[host]
+	if !ok {
+		return BreakerClosed
+	}
+	return b.state
+}
+
+func TestBreakerTransitions(t *testing.T) {
+	bs, clock, transitions := newTestBreakers(testBreakerConfig())
+	const host = "api.example.com"
+
+	// Half of the minimum number of requests fail
+	send(t, bs, host, breakerSuccess)
+	send(t, bs, host, breakerFailure)
+	send(t, bs, host, breakerSuccess)
+	if got := bs.state(host); got != BreakerClosed {
+		t.Fatalf("state after 3 requests = %s, want %s", got, BreakerClosed)
+	}
+	send(t, bs, host, breakerFailure)
+	if got := bs.state(host); got != BreakerOpen {
+		t.Fatalf("state after 4 requests = %s, want %s", got, BreakerOpen)
+	}
+
+	clock.Advance(29 * time.Second)
+	if _, err := bs.allow(host); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow during cooldown: err = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	// Once the cooldown is over only HalfOpenRequests trials are let through
+	clock.Advance(time.Second)
+	first, err := bs.allow(host)
+	if err != nil {
+		t.Fatalf("first trial: %v", err)
+	}
+	second, err := bs.allow(host)
+	if err != nil {
+		t.Fatalf("second trial: %v", err)
+	}
+	if _, err := bs.allow(host); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("third trial: err = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	bs.record(host, first, breakerSuccess)
+	if got := bs.state(host); got != BreakerHalfOpen {
+		t.Fatalf("state after one trial = %s, want %s", got, BreakerHalfOpen)
+	}
+	bs.record(host, second, breakerSuccess)
+	if got := bs.state(host); got != BreakerClosed {
+		t.Fatalf("state after both trials = %s, want %s", got, BreakerClosed)
+	}
+
+	want := []transition{
+		{host: host, from: BreakerClosed, to: BreakerOpen},
+		{host: host, from: BreakerOpen, to: BreakerHalfOpen},
+		{host: host, from: BreakerHalfOpen, to: BreakerClosed},
+	}
+	if len(*transitions) != len(want) {
+		t.Fatalf("transitions = %v, want %v", *transitions, want)
+	}
+	for i := range want {
+		if (*transitions)[i] != want[i] {
+			t.Errorf("transition %d = %v, want %v", i, (*transitions)[i], want[i])
+		}
+	}
+}
+
+func TestBreakerDefaultsZeroConfig(t *testing.T) {
+	bs, clock, _ := newTestBreakers(BreakerConfig{Cooldown: time.Minute})
+	const host = "api.example.com"
+
+	// Successes alone never open the circuit
+	for i := 0; i < 2*DefaultBreakerMinRequests; i++ {
+		send(t, bs, host, breakerSuccess)
+	}
+	if got := bs.state(host); got != BreakerClosed {
+		t.Fatalf("state after successes = %s, want %s", got, BreakerClosed)
+	}
+
+	// The default minimum number of requests and failure rate apply
+	clock.Advance(DefaultBreakerWindow)
+	for i := 0; i < DefaultBreakerMinRequests-1; i++ {
+		send(t, bs, host, breakerFailure)
+	}
+	if got := bs.state(host); got != BreakerClosed {
+		t.Fatalf("state before the minimum requests = %s, want %s", got, BreakerClosed)
+	}
+	send(t, bs, host, breakerFailure)
+	if got := bs.state(host); got != BreakerOpen {
+		t.Fatalf("state after the minimum requests = %s, want %s", got, BreakerOpen)
+	}
+
+	// The configured cooldown is kept
+	clock.Advance(DefaultBreakerCooldown)
+	if _, err := bs.allow(host); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow after the default cooldown: err = %v, want %v", err, ErrCircuitOpen)
+	}
+	clock.Advance(time.Minute - DefaultBreakerCooldown)
+	if _, err := bs.allow(host); err != nil {
+		t.Errorf("allow after the configured cooldown: %v", err)
+	}
+}
+
+func TestBreakerHalfOpenFailureReopens(t *testing.T) {
+	bs, clock, _ := newTestBreakers(testBreakerConfig())
+	const host = "api.example.com"
+
+	for i := 0; i < 4; i++ {
+		send(t, bs, host, breakerFailure)
+	}
+	clock.Advance(30 * time.Second)
+
+	send(t, bs, host, breakerFailure)
+	if got := bs.state(host); got != BreakerOpen {
+		t.Fatalf("state = %s, want %s", got, BreakerOpen)
+	}
+
+	// The cooldown restarts when the circuit reopens
+	clock.Advance(29 * time.Second)
+	if _, err := bs.allow(host); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow during cooldown: err = %v, want %v", err, ErrCircuitOpen)
+	}
+	clock.Advance(time.Second)
+	if _, err := bs.allow(host); err != nil {
+		t.Errorf("allow after cooldown: %v", err)
+	}
+}
+
+func TestBreakerIgnoredTrialFreesSlot(t *testing.T) {
+	cfg := testBreakerConfig()
+	cfg.HalfOpenRequests = 1
+	bs, clock, _ := newTestBreakers(cfg)
+	const host = "api.example.com"
+
+	for i := 0; i < 4; i++ {
+		send(t, bs, host, breakerFailure)
+	}
+	clock.Advance(30 * time.Second)
+
+	// A cancelled trial neither closes nor reopens the circuit
+	send(t, bs, host, breakerIgnored)
+	if got := bs.state(host); got != BreakerHalfOpen {
+		t.Fatalf("state = %s, want %s", got, BreakerHalfOpen)
+	}
+
+	send(t, bs, host, breakerSuccess)
+	if got := bs.state(host); got != BreakerClosed {
+		t.Errorf("state = %s, want %s", got, BreakerClosed)
+	}
+}
+
+func TestBreakerWindowResetsCounts(t *testing.T) {
+	bs, clock, _ := newTestBreakers(testBreakerConfig())
+	const host = "api.example.com"
+
+	for i := 0; i < 3; i++ {
+		send(t, bs, host, breakerFailure)
+	}
+
+	// The failures of an expired window no longer count
+	clock.Advance(time.Minute)
+	send(t, bs, host, breakerFailure)
+	if got := bs.state(host); got != BreakerClosed {
+		t.Errorf("state = %s, want %s", got, BreakerClosed)
+	}
+}
+
+func TestBreakerDropsStaleGenerations(t *testing.T) {
+	cfg := testBreakerConfig()
+	cfg.MinRequests = 1
+	cfg.FailureRate = 1
+	cfg.HalfOpenRequests = 1
+	bs, clock, _ := newTestBreakers(cfg)
+	const host = "api.example.com"
+
+	// Two requests are in flight when the first one opens the circuit
+	first, _ := bs.allow(host)
+	slow, _ := bs.allow(host)
+	bs.record(host, first, breakerFailure)
+
+	// The slow request's failure does not restart the cooldown
+	clock.Advance(20 * time.Second)
+	bs.record(host, slow, breakerFailure)
+	clock.Advance(10 * time.Second)
+
+	trial, err := bs.allow(host)
+	if err != nil {
+		t.Fatalf("trial: %v", err)
+	}
+
+	// Nor does its success count as the trial
+	bs.record(host, slow, breakerSuccess)
+	if got := bs.state(host); got != BreakerHalfOpen {
+		t.Fatalf("state after stale success = %s, want %s", got, BreakerHalfOpen)
+	}
+
+	bs.record(host, trial, breakerSuccess)
+	if got := bs.state(host); got != BreakerClosed {
+		t.Errorf("state after trial = %s, want %s", got, BreakerClosed)
+	}
+}
+
+func TestBreakerIsolatesHosts(t *testing.T) {
+	bs, _, transitions := newTestBreakers(testBreakerConfig())
+
+	for i := 0; i < 4; i++ {
+		send(t, bs, "a.example.com", breakerFailure)
+	}
+
+	if got := bs.state("a.example.com"); got != BreakerOpen {
+		t.Errorf("a.example.com state = %s, want %s", got, BreakerOpen)
+	}
+	if _, err := bs.allow("b.example.com"); err != nil {
+		t.Errorf("b.example.com: %v", err)
+	}
+	if got := bs.state("b.example.com"); got != BreakerClosed {
+		t.Errorf("b.example.com state = %s, want %s", got, BreakerClosed)
+	}
+	if len(*transitions) != 1 || (*transitions)[0].host != "a.example.com" {
+		t.Errorf("transitions = %v, want only a.example.com opening", *transitions)
+	}
+}
+
+func TestCircuitBreakerFailsFast(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	cfg := testBreakerConfig()
+	c, err := NewClient(server.URL, nil, WithCircuitBreaker(cfg))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	for i := 0; i < cfg.MinRequests; i++ {
+		if _, err := c.Request(context.Background(), "/resources"); err == nil {
+			t.Fatalf("request %d succeeded, want an error", i+1)
+		}
+	}
+
+	_, err = c.Request(context.Background(), "/resources")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("err = %v, want %v", err, ErrCircuitOpen)
+	}
+	if got := requests.Load(); got != int32(cfg.MinRequests) {
+		t.Errorf("server got %d requests, want %d", got, cfg.MinRequests)
+	}
+	if got := c.BreakerState(server.Listener.Addr().String()); got != BreakerOpen {
+		t.Errorf("state = %s, want %s", got, BreakerOpen)
+	}
+}
@@ -25,9 +25,17 @@ const (
 )
 
 type Client struct {
-	httpClient *http.Client
-	baseURL    string
-	auth       Authenticator
+	httpClient  *http.Client
+	baseURL     string
+	auth        Authenticator
+	retryPolicy RetryPolicy
+	breakers    *breakers
+	limiter     *rateLimiter
+	decoder     Decoder
+	// errorDecoder is set by WithErrorBody
+	errorDecoder errorDecoder
+	// inFlight is a semaphore of WithMaxInFlight slots
+	inFlight chan struct{}
 }
 
 type ClientOption func(*Client)
@@ -41,8 +49,9 @@ func NewClient(baseURL string, auth Authenticator, opts ...ClientOption) (*Clien
 		httpClient: &http.Client{
 			Timeout: DefaultTimeout,
 		},
-		baseURL: baseURL,
-		auth:    auth,
+		baseURL:     baseURL,
+		auth:        auth,
+		retryPolicy: NoRetry(),
 	}
 
 	for _, opt := range opts {
@@ -53,36 +62,118 @@ func NewClient(baseURL string, auth Authenticator, opts ...ClientOption) (*Clien
 }
 
 func (c *Client) Request(ctx context.Context, path string, opts ...RequestOption) ([]byte, error) {
-	if ctx == nil {
-		ctx = context.Background()
-	}
-
 	reqOpts := defaultRequestOptions()
 	for _, opt := range opts {
 		opt(reqOpts)
 	}
+
+	_, respBody, err := c.request(ctx, path, reqOpts)
+	return respBody, err
+}
+
+// request sends the request, retrying it as the retry policy allows. The
+// response is returned with its body already read and closed.
+func (c *Client) request(ctx context.Context, path string, reqOpts *RequestOptions) (*http.Response, []byte, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
 	var reqURL = c.baseURL
 	if reqOpts.subURL != "" {
 		reqURL = reqOpts.subURL
 	}
 	fullURL, err := url.JoinPath(reqURL, path)
 	if err != nil {
-		return nil, errors.Wrap(err, "joining URL paths")
+		return nil, nil, errors.Wrap(err, "joining URL paths")
+	}
+	if reqOpts.url != "" {
+		if fullURL, err = resolveURL(reqURL, reqOpts.url); err != nil {
+			return nil, nil, err
+		}
 	}
 
-	var bodyReader io.Reader
-	if reqOpts.rawBody != nil {
-		bodyReader = reqOpts.rawBody
+	policy := c.retryPolicy
+	if reqOpts.retryPolicy != nil {
+		policy = *reqOpts.retryPolicy
+	}
+
+	// Bodies are buffered so they can be replayed on every attempt
+	var body []byte
+	if reqOpts.rawBody != nil && policy.MaxAttempts > 1 {
+		body, err = io.ReadAll(reqOpts.rawBody)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "reading request body")
+		}
+		reqOpts.rawBody = nil
 	}
 	if reqOpts.body != nil {
-		jsonBody, err := json.Marshal(reqOpts.body)
+		body, err = json.Marshal(reqOpts.body)
 		if err != nil {
-			return nil, errors.Wrap(err, "marshaling request body")
+			return nil, nil, errors.Wrap(err, "marshaling request body")
 		}
-		bodyReader = bytes.NewBuffer(jsonBody)
 		reqOpts.contentType = "application/json"
 	}
 
+	for attempt := 1; ; attempt++ {
+		req, err := c.newRequest(ctx, fullURL, reqOpts, body)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// The rate limit is waited for first, so a request doesn't hold a
+		// slot while it is throttled
+		if c.limiter != nil {
+			if err := c.limiter.wait(ctx); err != nil {
+				return nil, nil, errors.Wrap(err, "waiting for rate limit")
+			}
+		}
+		if err := c.acquire(ctx); err != nil {
+			return nil, nil, errors.Wrap(err, "waiting for a request slot")
+		}
+		resp, respBody, err := c.send(ctx, req)
+		c.release()
+		if err == nil {
+			return resp, respBody, nil
+		}
+
+		// Client errors, like an open circuit, are not worth retrying
+		var clientErr *ClientError
+		if errors.As(err, &clientErr) {
+			return nil, nil, err
+		}
+
+		if attempt >= policy.attempts(req) || ctx.Err() != nil || !policy.shouldRetry(resp, err) {
+			return resp, nil, c.decodeError(resp, reqOpts, err)
+		}
+
+		delay, ok := policy.delay(attempt, resp)
+		if !ok {
+			return resp, nil, c.decodeError(resp, reqOpts, err)
+		}
+		if reqOpts.logger != nil {
+			reqOpts.logger.Warn("retrying request",
+				zap.String("method", req.Method),
+				zap.String("url", fullURL),
+				zap.Int("attempt", attempt),
+				zap.Duration("delay", delay),
+				zap.Error(err))
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, nil, errors.Wrap(err, "waiting to retry request")
+		}
+	}
+}
+
+func (c *Client) newRequest(ctx context.Context, fullURL string, reqOpts *RequestOptions, body []byte) (*http.Request, error) {
+	var bodyReader io.Reader
+	if reqOpts.rawBody != nil {
+		bodyReader = reqOpts.rawBody
+	}
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
 	req, err := http.NewRequestWithContext(ctx, reqOpts.method, fullURL, bodyReader)
 	if err != nil {
 		return nil, errors.Wrap(err, "creating request")
@@ -103,25 +194,52 @@ func (c *Client) Request(ctx context.Context, path string, opts ...RequestOption
 		}
 	}
 
+	return req, nil
+}
+
+// send checks the circuit breaker, then executes a single attempt.
+func (c *Client) send(ctx context.Context, req *http.Request) (*http.Response, []byte, error) {
+	var generation int
+	if c.breakers != nil {
+		var err error
+		if generation, err = c.breakers.allow(req.URL.Host); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	resp, respBody, err := c.do(req)
+	if c.breakers != nil {
+		c.breakers.record(req.URL.Host, generation, c.breakers.result(ctx, resp, err))
+	}
+	if c.limiter != nil {
+		c.limiter.observe(resp)
+	}
+
+	return resp, respBody, err
+}
+
+// do executes a single attempt. resp is returned with its body already read
+// and closed, and is nil when no response was received.
+func (c *Client) do(req *http.Request) (*http.Response, []byte, error) {
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "executing request")
+		return nil, nil, errors.Wrap(err, "executing request")
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, errors.Wrap(err, "reading response body")
+		return nil, nil, errors.Wrap(err, "reading response body")
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, &HTTPError{
+		return resp, respBody, &HTTPError{
 			StatusCode: resp.StatusCode,
 			Body:       respBody,
 		}
 	}
 
-	return respBody, nil
+	return resp, respBody, nil
 }
 
 type RequestOptions struct {
@@ -133,6 +251,9 @@ type RequestOptions struct {
 	contentType string
 	queryParams url.Values
 	logger      *zap.Logger
+	retryPolicy *RetryPolicy
+	decoder     Decoder
+	url         string
 }
 
 func defaultRequestOptions() *RequestOptions {
@@ -177,6 +298,8 @@ func WithBody(body interface{}) RequestOption {
 	}
 }
 
+// WithBodyReader sets a raw request body. When the request can be retried
+// the reader is read in full up front so every attempt sends the same body.
 func WithBodyReader(r io.Reader, contentType string) RequestOption {
 	return func(opts *RequestOptions) {
 		opts.rawBody = r
@@ -184,6 +307,16 @@ func WithBodyReader(r io.Reader, contentType string) RequestOption {
 	}
 }
 
+// WithURL sends the request to rawURL as is, e.g. to follow a next link.
+// The path and query parameters are ignored, and rawURL may be relative to
+// the base URL but not on another host, so credentials stay with it.
+func WithURL(rawURL string) RequestOption {
+	return func(opts *RequestOptions) {
+		opts.url = rawURL
+		opts.queryParams = nil
+	}
+}
+
 // WithQueryParams sets the query parameters.
 // It replaces any existing query parameters.
 func WithQueryParams(params url.Values) RequestOption {
@@ -192,6 +325,18 @@ func WithQueryParams(params url.Values) RequestOption {
 	}
 }
 
+// WithQueryParam sets a single query parameter, keeping the others.
+func WithQueryParam(key, value string) RequestOption {
+	return func(opts *RequestOptions) {
+		params := make(url.Values, len(opts.queryParams)+1)
+		for k, v := range opts.queryParams {
+			params[k] = v
+		}
+		params.Set(key, value)
+		opts.queryParams = params
+	}
+}
+
 // WithLogger sets the logger for the request.
 func WithLogger(logger *zap.Logger) RequestOption {
 	return func(opts *RequestOptions) {
@@ -199,6 +344,25 @@ func WithLogger(logger *zap.Logger) RequestOption {
 	}
 }
 
+func resolveURL(baseURL, rawURL string) (string, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing base URL")
+	}
+
+	ref, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing request URL")
+	}
+
+	resolved := base.ResolveReference(ref)
+	if resolved.Host != base.Host {
+		return "", errors.Errorf("request URL host %s does not match %s", resolved.Host, base.Host)
+	}
+
+	return resolved.String(), nil
+}
+
 // HTTPError represents an error response from the server.
 // It includes both the HTTP status code and response body.
 type HTTPError struct {
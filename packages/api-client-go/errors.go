@@ -1,5 +1,9 @@
 package client
 
+import "fmt"
+
+const CodeCircuitOpen = "CIRCUIT_OPEN"
+
 type ClientError struct {
 	Code    string
 	Message string
@@ -10,10 +14,29 @@ func (e *ClientError) Error() string {
 	return e.Message
 }
 
+// Is matches client errors by code, so errors.Is(err, ErrCircuitOpen) holds
+// for the error returned for any host.
+func (e *ClientError) Is(target error) bool {
+	t, ok := target.(*ClientError)
+	return ok && t.Code == e.Code
+}
+
 var (
 	ErrInvalidBaseURL = &ClientError{
 		Code:    "INVALID_BASE_URL",
 		Message: "invalid base url",
 		Details: "base url cannot be empty",
 	}
+	ErrCircuitOpen = &ClientError{
+		Code:    CodeCircuitOpen,
+		Message: "circuit breaker is open",
+	}
 )
+
+func circuitOpenError(host string) error {
+	return &ClientError{
+		Code:    CodeCircuitOpen,
+		Message: fmt.Sprintf("circuit breaker is open for %s", host),
+		Details: "requests are rejected until the host recovers",
+	}
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimit configures a token bucket shared by every request of a client.
+type RateLimit struct {
+	// RequestsPerSecond is the rate tokens are added to the bucket at.
+	RequestsPerSecond float64
+	// Burst is the size of the bucket, i.e. how many requests can be sent
+	// at once after a quiet period. Defaults to 1.
+	Burst int
+	// MinRequestsPerSecond is the lowest rate 429 responses can slow the
+	// client down to. Defaults to a tenth of RequestsPerSecond.
+	MinRequestsPerSecond float64
+}
+
+// WithRateLimit makes requests wait for a token before every attempt. A 429
+// response halves the rate and pauses the bucket for the Retry-After period;
+// successful responses bring the rate back up step by step.
+func WithRateLimit(limit RateLimit) ClientOption {
+	return func(c *Client) {
+		if limit.RequestsPerSecond > 0 {
+			c.limiter = newRateLimiter(limit)
+		}
+	}
+}
+
+// WithMaxInFlight caps the number of requests the client sends at the same
+// time. Other requests wait for a slot.
+func WithMaxInFlight(n int) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.inFlight = make(chan struct{}, n)
+		}
+	}
+}
+
+type rateLimiter struct {
+	limit RateLimit
+	// now is the clock tokens are added by
+	now func() time.Time
+
+	mu          sync.Mutex
+	rate        float64
+	tokens      float64
+	updatedAt   time.Time
+	pausedUntil time.Time
+}
+
+// rateRecoverySteps is the number of successful responses it takes to go
+// from the minimum rate back to the configured one.
+const rateRecoverySteps = 20
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	if limit.MinRequestsPerSecond <= 0 || limit.MinRequestsPerSecond > limit.RequestsPerSecond {
+		limit.MinRequestsPerSecond = limit.RequestsPerSecond / 10
+	}
+
+	return &rateLimiter{
+		limit:     limit,
+		now:       time.Now,
+		rate:      limit.RequestsPerSecond,
+		tokens:    float64(limit.Burst),
+		updatedAt: time.Now(),
+	}
+}
+
+// wait blocks until a token is available or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := l.now()
+		l.refill(now)
+
+		var delay time.Duration
+		switch {
+		case now.Before(l.pausedUntil):
+			delay = l.pausedUntil.Sub(now)
+		case l.tokens >= 1:
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		default:
+			delay = time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		}
+		l.mu.Unlock()
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+func (l *rateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.updatedAt).Seconds()
+	l.tokens = min(l.tokens+elapsed*l.rate, float64(l.limit.Burst))
+	l.updatedAt = now
+}
+
+// observe adapts the rate to a response. resp is nil when no response was
+// received, which leaves the rate unchanged.
+func (l *rateLimiter) observe(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.refill(now)
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		step := (l.limit.RequestsPerSecond - l.limit.MinRequestsPerSecond) / rateRecoverySteps
+		l.rate = min(l.rate+step, l.limit.RequestsPerSecond)
+		return
+	}
+
+	l.rate = max(l.rate/2, l.limit.MinRequestsPerSecond)
+	l.tokens = min(l.tokens, 0)
+	if wait, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+		l.pausedUntil = now.Add(wait)
+	}
+}
+
+func (c *Client) acquire(ctx context.Context) error {
+	if c.inFlight == nil {
+		return nil
+	}
+
+	select {
+	case c.inFlight <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) release() {
+	if c.inFlight != nil {
+		<-c.inFlight
+	}
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(limit RateLimit) (*rateLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)}
+
+	l := newRateLimiter(limit)
+	l.now = clock.Now
+	l.updatedAt = clock.Now()
+	return l, clock
+}
+
+// waitBriefly reports whether a token was taken without waiting on the real
+// clock.
+func waitBriefly(l *rateLimiter) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	return l.wait(ctx)
+}
+
+func response(status int, retryAfter string) *http.Response {
+	resp := &http.Response{StatusCode: status, Header: http.Header{}}
+	if retryAfter != "" {
+		resp.Header.Set("Retry-After", retryAfter)
+	}
+	return resp
+}
+
+func TestRateLimiterTokenBucket(t *testing.T) {
+	l, clock := newTestLimiter(RateLimit{RequestsPerSecond: 10, Burst: 2})
+
+	// The bucket starts full
+	for i := 0; i < 2; i++ {
+		if err := waitBriefly(l); err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+	}
+	if err := waitBriefly(l); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("request beyond burst: err = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	clock.Advance(100 * time.Millisecond)
+	if err := waitBriefly(l); err != nil {
+		t.Errorf("request after refill: %v", err)
+	}
+
+	// A quiet period fills the bucket up to the burst only
+	clock.Advance(time.Minute)
+	for i := 0; i < 2; i++ {
+		if err := waitBriefly(l); err != nil {
+			t.Fatalf("request %d after quiet period: %v", i+1, err)
+		}
+	}
+	if err := waitBriefly(l); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("request beyond burst after quiet period: err = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRateLimiterHalvesOnTooManyRequests(t *testing.T) {
+	l, _ := newTestLimiter(RateLimit{RequestsPerSecond: 10, Burst: 5})
+
+	for _, want := range []float64{5, 2.5, 1.25, 1, 1} {
+		l.observe(response(http.StatusTooManyRequests, ""))
+		if l.rate != want {
+			t.Errorf("rate = %v, want %v", l.rate, want)
+		}
+	}
+
+	// The bucket is drained so the next request waits
+	if err := waitBriefly(l); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRateLimiterPausesForRetryAfter(t *testing.T) {
+	l, clock := newTestLimiter(RateLimit{RequestsPerSecond: 10, Burst: 5})
+
+	l.observe(response(http.StatusTooManyRequests, "2"))
+
+	// Tokens refill at the halved rate, but the pause still holds
+	clock.Advance(time.Second)
+	if err := waitBriefly(l); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("request during pause: err = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	clock.Advance(time.Second)
+	if err := waitBriefly(l); err != nil {
+		t.Errorf("request after pause: %v", err)
+	}
+}
+
+func TestRateLimiterRecovers(t *testing.T) {
+	l, _ := newTestLimiter(RateLimit{RequestsPerSecond: 10, Burst: 5, MinRequestsPerSecond: 2})
+
+	for i := 0; i < 4; i++ {
+		l.observe(response(http.StatusTooManyRequests, ""))
+	}
+	if l.rate != 2 {
+		t.Fatalf("rate = %v, want the minimum 2", l.rate)
+	}
+
+	// Each success raises the rate by a twentieth of the range
+	l.observe(response(http.StatusOK, ""))
+	if math.Abs(l.rate-2.4) > 1e-9 {
+		t.Errorf("rate after a success = %v, want 2.4", l.rate)
+	}
+
+	for i := 1; i < rateRecoverySteps; i++ {
+		l.observe(response(http.StatusOK, ""))
+	}
+	if math.Abs(l.rate-10) > 1e-9 {
+		t.Errorf("rate after %d successes = %v, want 10", rateRecoverySteps, l.rate)
+	}
+
+	l.observe(response(http.StatusOK, ""))
+	if l.rate > 10 {
+		t.Errorf("rate = %v, want at most 10", l.rate)
+	}
+
+	// A missing response leaves the rate unchanged
+	l.observe(nil)
+	if math.Abs(l.rate-10) > 1e-9 {
+		t.Errorf("rate after no response = %v, want 10", l.rate)
+	}
+}
+
+func TestThrottledRequestDoesNotHoldSlot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, nil,
+		WithRateLimit(RateLimit{RequestsPerSecond: 1, Burst: 1}),
+		WithMaxInFlight(1),
+	)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	clock := &fakeClock{now: time.Now()}
+	c.limiter.now = clock.Now
+
+	if _, err := c.Request(context.Background(), "/resources"); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+
+	// The second request waits for a token that the frozen clock never adds
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Request(ctx, "/resources")
+		done <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	if n := len(c.inFlight); n != 0 {
+		t.Errorf("slots held while throttled = %d, want 0", n)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want %v", err, context.Canceled)
+	}
+}
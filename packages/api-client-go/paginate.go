@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"strconv"
+)
+
+// Paginator describes how a list endpoint is paged. P is the decoded page
+// and T the type of its items.
+type Paginator[P, T any] struct {
+	// First holds the options of the first page only, e.g. its offset.
+	First []RequestOption
+	// Items returns the items of a page.
+	Items func(page P) []T
+	// Next returns the options requesting the page after page, given the
+	// number of items fetched so far, or false when page was the last one.
+	Next func(page P, fetched int) ([]RequestOption, bool)
+}
+
+// NextURLPaginator follows the next link of every page until it is empty.
+// Next links are requested as they are, so they carry the filters of the
+// first request.
+func NextURLPaginator[P, T any](items func(page P) []T, next func(page P) string) Paginator[P, T] {
+	return Paginator[P, T]{
+		Items: items,
+		Next: func(page P, _ int) ([]RequestOption, bool) {
+			nextURL := next(page)
+			if nextURL == "" {
+				return nil, false
+			}
+			return []RequestOption{WithURL(nextURL)}, true
+		},
+	}
+}
+
+// CursorPaginator passes the cursor of every page in the param query
+// parameter until the cursor is empty.
+func CursorPaginator[P, T any](param string, items func(page P) []T, cursor func(page P) string) Paginator[P, T] {
+	return Paginator[P, T]{
+		Items: items,
+		Next: func(page P, _ int) ([]RequestOption, bool) {
+			next := cursor(page)
+			if next == "" {
+				return nil, false
+			}
+			return []RequestOption{WithQueryParam(param, next)}, true
+		},
+	}
+}
+
+// OffsetPaginator requests limit items at a time in the offsetParam and
+// limitParam query parameters, until a page has fewer than limit items.
+func OffsetPaginator[P, T any](offsetParam, limitParam string, limit int, items func(page P) []T) Paginator[P, T] {
+	page := func(offset int) []RequestOption {
+		return []RequestOption{
+			WithQueryParam(offsetParam, strconv.Itoa(offset)),
+			WithQueryParam(limitParam, strconv.Itoa(limit)),
+		}
+	}
+
+	return Paginator[P, T]{
+		First: page(0),
+		Items: items,
+		Next: func(p P, fetched int) ([]RequestOption, bool) {
+			if len(items(p)) < limit {
+				return nil, false
+			}
+			return page(fetched), true
+		},
+	}
+}
+
+// Pages fetches the pages of a list endpoint as the loop advances. opts
+// apply to every page. A failed page is yielded with its error and ends the
+// iteration. A page without items ends it too, so a server that keeps
+// returning a next page can't loop forever.
+func Pages[P, T any](ctx context.Context, c *Client, path string, p Paginator[P, T], opts ...RequestOption) iter.Seq2[P, error] {
+	return func(yield func(P, error) bool) {
+		pageOpts := p.First
+		fetched := 0
+
+		for {
+			resp, err := Do[P](ctx, c, path, append(append([]RequestOption{}, opts...), pageOpts...)...)
+			if err != nil {
+				var zero P
+				yield(zero, err)
+				return
+			}
+
+			if !yield(resp.Body, nil) {
+				return
+			}
+
+			count := len(p.Items(resp.Body))
+			if count == 0 {
+				return
+			}
+			fetched += count
+
+			var ok bool
+			if pageOpts, ok = p.Next(resp.Body, fetched); !ok {
+				return
+			}
+		}
+	}
+}
+
+// Paginate iterates over the items of every page of a list endpoint:
+//
+//	for webhook, err := range client.Paginate(ctx, c, "/webhooks", paginator) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func Paginate[P, T any](ctx context.Context, c *Client, path string, p Paginator[P, T], opts ...RequestOption) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page, err := range Pages(ctx, c, path, p, opts...) {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range p.Items(page) {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Collect gathers the items of seq, stopping at the first error.
+func Collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var items []T
+	for item, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,297 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	client "template/packages/api-client-go"
+)
+
+type page struct {
+	Items  []string `json:"items"`
+	Next   string   `json:"next,omitempty"`
+	Cursor string   `json:"cursor,omitempty"`
+}
+
+func pageItems(p page) []string { return p.Items }
+
+// pageServer serves the page returned by pages for every request and records
+// the query of every request.
+type pageServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	queries []url.Values
+}
+
+func newPageServer(t *testing.T, pages func(server *pageServer, query url.Values) (page, int)) *pageServer {
+	t.Helper()
+
+	s := &pageServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.queries = append(s.queries, r.URL.Query())
+		s.mu.Unlock()
+
+		p, status := pages(s, r.URL.Query())
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(p)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *pageServer) requests() []url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNextURLPaginator(t *testing.T) {
+	server := newPageServer(t, func(s *pageServer, query url.Values) (page, int) {
+		switch query.Get("page") {
+		case "":
+			// Relative next link carrying the filters of the first request
+			return page{Items: []string{"a", "b"}, Next: "/webhooks?page=2&topic=" + query.Get("topic")}, http.StatusOK
+		case "2":
+			// Absolute next link on the same host
+			return page{Items: []string{"c"}, Next: s.URL + "/webhooks?page=3&topic=" + query.Get("topic")}, http.StatusOK
+		default:
+			return page{Items: []string{"d"}}, http.StatusOK
+		}
+	})
+	c := newTestClient(t, server.URL)
+	paginator := client.NextURLPaginator(pageItems, func(p page) string { return p.Next })
+
+	items, err := client.Collect(client.Paginate(context.Background(), c, "/webhooks", paginator, client.WithQueryParam("topic", "card.created")))
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+
+	if want := []string{"a", "b", "c", "d"}; !equal(items, want) {
+		t.Errorf("items = %v, want %v", items, want)
+	}
+	requests := server.requests()
+	if len(requests) != 3 {
+		t.Fatalf("requests = %d, want 3", len(requests))
+	}
+	for i, query := range requests {
+		if got := query.Get("topic"); got != "card.created" {
+			t.Errorf("request %d topic = %q, want %q", i+1, got, "card.created")
+		}
+	}
+}
+
+func TestNextURLPaginatorRejectsOtherHosts(t *testing.T) {
+	tests := []struct {
+		name string
+		next string
+	}{
+		{name: "other host", next: "https://attacker.example.com/webhooks?page=2"},
+		{name: "protocol relative", next: "//attacker.example.com/webhooks?page=2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newPageServer(t, func(s *pageServer, query url.Values) (page, int) {
+				return page{Items: []string{"a"}, Next: tt.next}, http.StatusOK
+			})
+			c := newTestClient(t, server.URL)
+			paginator := client.NextURLPaginator(pageItems, func(p page) string { return p.Next })
+
+			var items []string
+			var lastErr error
+			for item, err := range client.Paginate(context.Background(), c, "/webhooks", paginator) {
+				if err != nil {
+					lastErr = err
+					break
+				}
+				items = append(items, item)
+			}
+
+			if lastErr == nil {
+				t.Error("paginate succeeded, want a host mismatch error")
+			}
+			if !equal(items, []string{"a"}) {
+				t.Errorf("items = %v, want [a]", items)
+			}
+			if got := len(server.requests()); got != 1 {
+				t.Errorf("requests = %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestCursorPaginator(t *testing.T) {
+	server := newPageServer(t, func(s *pageServer, query url.Values) (page, int) {
+		switch query.Get("cursor") {
+		case "":
+			return page{Items: []string{"a", "b"}, Cursor: "c1"}, http.StatusOK
+		case "c1":
+			return page{Items: []string{"c", "d"}, Cursor: "c2"}, http.StatusOK
+		default:
+			// The last page has no cursor
+			return page{Items: []string{"e"}}, http.StatusOK
+		}
+	})
+	c := newTestClient(t, server.URL)
+	paginator := client.CursorPaginator("cursor", pageItems, func(p page) string { return p.Cursor })
+
+	items, err := client.Collect(client.Paginate(context.Background(), c, "/cards", paginator, client.WithQueryParam("status", "active")))
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+
+	if want := []string{"a", "b", "c", "d", "e"}; !equal(items, want) {
+		t.Errorf("items = %v, want %v", items, want)
+	}
+	requests := server.requests()
+	wantCursors := []string{"", "c1", "c2"}
+	if len(requests) != len(wantCursors) {
+		t.Fatalf("requests = %d, want %d", len(requests), len(wantCursors))
+	}
+	for i, query := range requests {
+		if got := query.Get("cursor"); got != wantCursors[i] {
+			t.Errorf("request %d cursor = %q, want %q", i+1, got, wantCursors[i])
+		}
+		if got := query.Get("status"); got != "active" {
+			t.Errorf("request %d status = %q, want %q", i+1, got, "active")
+		}
+	}
+}
+
+func TestOffsetPaginator(t *testing.T) {
+	tests := []struct {
+		name        string
+		total       int
+		wantOffsets []string
+	}{
+		{name: "partial last page", total: 5, wantOffsets: []string{"0", "2", "4"}},
+		{name: "full last page", total: 4, wantOffsets: []string{"0", "2", "4"}},
+		{name: "single page", total: 1, wantOffsets: []string{"0"}},
+		{name: "no items", total: 0, wantOffsets: []string{"0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newPageServer(t, func(s *pageServer, query url.Values) (page, int) {
+				offset, _ := strconv.Atoi(query.Get("offset"))
+				limit, _ := strconv.Atoi(query.Get("limit"))
+
+				var p page
+				for i := offset; i < min(offset+limit, tt.total); i++ {
+					p.Items = append(p.Items, strconv.Itoa(i))
+				}
+				return p, http.StatusOK
+			})
+			c := newTestClient(t, server.URL)
+			paginator := client.OffsetPaginator("offset", "limit", 2, pageItems)
+
+			items, err := client.Collect(client.Paginate(context.Background(), c, "/transfers", paginator))
+			if err != nil {
+				t.Fatalf("collect: %v", err)
+			}
+
+			if len(items) != tt.total {
+				t.Errorf("items = %v, want %d", items, tt.total)
+			}
+			requests := server.requests()
+			if len(requests) != len(tt.wantOffsets) {
+				t.Fatalf("requests = %d, want %d", len(requests), len(tt.wantOffsets))
+			}
+			for i, query := range requests {
+				if got := query.Get("offset"); got != tt.wantOffsets[i] {
+					t.Errorf("request %d offset = %q, want %q", i+1, got, tt.wantOffsets[i])
+				}
+				if got := query.Get("limit"); got != "2" {
+					t.Errorf("request %d limit = %q, want %q", i+1, got, "2")
+				}
+			}
+		})
+	}
+}
+
+func TestPaginateStopsOnEmptyPage(t *testing.T) {
+	// A server that keeps returning a next link and cursor without items
+	server := newPageServer(t, func(s *pageServer, query url.Values) (page, int) {
+		return page{Next: "/webhooks?page=2", Cursor: "again"}, http.StatusOK
+	})
+	c := newTestClient(t, server.URL)
+
+	paginators := map[string]client.Paginator[page, string]{
+		"next url": client.NextURLPaginator(pageItems, func(p page) string { return p.Next }),
+		"cursor":   client.CursorPaginator("cursor", pageItems, func(p page) string { return p.Cursor }),
+	}
+	for name, paginator := range paginators {
+		items, err := client.Collect(client.Paginate(context.Background(), c, "/webhooks", paginator))
+		if err != nil {
+			t.Fatalf("%s: collect: %v", name, err)
+		}
+		if len(items) != 0 {
+			t.Errorf("%s: items = %v, want none", name, items)
+		}
+	}
+
+	if got := len(server.requests()); got != len(paginators) {
+		t.Errorf("requests = %d, want %d", got, len(paginators))
+	}
+}
+
+func TestPaginateStopsOnError(t *testing.T) {
+	server := newPageServer(t, func(s *pageServer, query url.Values) (page, int) {
+		if query.Get("cursor") == "" {
+			return page{Items: []string{"a"}, Cursor: "c1"}, http.StatusOK
+		}
+		return page{}, http.StatusInternalServerError
+	})
+	c := newTestClient(t, server.URL)
+	paginator := client.CursorPaginator("cursor", pageItems, func(p page) string { return p.Cursor })
+
+	items, err := client.Collect(client.Paginate(context.Background(), c, "/cards", paginator))
+	if err == nil {
+		t.Error("collect succeeded, want the error of the second page")
+	}
+	if items != nil {
+		t.Errorf("items = %v, want none", items)
+	}
+	if got := len(server.requests()); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestPaginateStopsWhenLoopBreaks(t *testing.T) {
+	server := newPageServer(t, func(s *pageServer, query url.Values) (page, int) {
+		return page{Items: []string{"a", "b"}, Cursor: "next"}, http.StatusOK
+	})
+	c := newTestClient(t, server.URL)
+	paginator := client.CursorPaginator("cursor", pageItems, func(p page) string { return p.Cursor })
+
+	for _, err := range client.Paginate(context.Background(), c, "/cards", paginator) {
+		if err != nil {
+			t.Fatalf("paginate: %v", err)
+		}
+		break
+	}
+
+	if got := len(server.requests()); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
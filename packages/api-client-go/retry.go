@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	DefaultMaxAttempts    = 3
+	DefaultBaseRetryDelay = 200 * time.Millisecond
+	DefaultMaxRetryDelay  = 5 * time.Second
+)
+
+// RetryPolicy controls how a failed request is retried. Requests with a
+// non-idempotent method (POST, PATCH) are only retried when they carry an
+// Idempotency-Key header.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is doubled on every attempt, never exceeding MaxDelay, and
+	// the result is jittered so clients don't retry in lockstep. Zero delays
+	// default to DefaultBaseRetryDelay and DefaultMaxRetryDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// ShouldRetry classifies a failed attempt. resp is nil when the request
+	// failed before a response was received. Defaults to DefaultShouldRetry.
+	ShouldRetry func(resp *http.Response, err error) bool
+}
+
+// DefaultRetryPolicy retries transport errors and transient statuses up to
+// DefaultMaxAttempts times.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: DefaultMaxAttempts,
+		BaseDelay:   DefaultBaseRetryDelay,
+		MaxDelay:    DefaultMaxRetryDelay,
+		ShouldRetry: DefaultShouldRetry,
+	}
+}
+
+// NoRetry makes a single attempt.
+func NoRetry() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+var retryableStatuses = map[int]bool{
+	http.StatusRequestTimeout:      true,
+	http.StatusTooManyRequests:     true,
+	http.StatusInternalServerError: true,
+	http.StatusBadGateway:          true,
+	http.StatusServiceUnavailable:  true,
+	http.StatusGatewayTimeout:      true,
+}
+
+// DefaultShouldRetry retries 408, 429, 500, 502, 503 and 504 responses and
+// any transport error other than a cancelled context.
+func DefaultShouldRetry(resp *http.Response, err error) bool {
+	if resp != nil {
+		return retryableStatuses[resp.StatusCode]
+	}
+
+	return err != nil && !errors.Is(err, context.Canceled)
+}
+
+// WithRetryPolicy sets the retry policy of every request made by the client.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithRequestRetryPolicy overrides the client's retry policy for a single
+// request.
+func WithRequestRetryPolicy(policy RetryPolicy) RequestOption {
+	return func(opts *RequestOptions) {
+		opts.retryPolicy = &policy
+	}
+}
+
+// WithIdempotencyKey sets the Idempotency-Key header, which also allows
+// POST and PATCH requests to be retried.
+func WithIdempotencyKey(key string) RequestOption {
+	return func(opts *RequestOptions) {
+		opts.headers[IdempotencyKeyHeader] = key
+	}
+}
+
+func (p RetryPolicy) attempts(req *http.Request) int {
+	if p.MaxAttempts < 2 {
+		return 1
+	}
+
+	switch req.Method {
+	case http.MethodPost, http.MethodPatch:
+		if req.Header.Get(IdempotencyKeyHeader) == "" {
+			return 1
+		}
+	}
+
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if p.ShouldRetry == nil {
+		return DefaultShouldRetry(resp, err)
+	}
+
+	return p.ShouldRetry(resp, err)
+}
+
+// delay returns how long to wait after the given attempt failed. A
+// Retry-After header takes precedence over the backoff. When it asks for
+// more than MaxDelay the request is not retried and false is returned.
+func (p RetryPolicy) delay(attempt int, resp *http.Response) (time.Duration, bool) {
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxRetryDelay
+	}
+
+	if resp != nil {
+		if wait, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			return wait, wait <= maxDelay
+		}
+	}
+
+	backoff := p.BaseDelay
+	if backoff <= 0 {
+		backoff = DefaultBaseRetryDelay
+	}
+	for i := 1; i < attempt && backoff < maxDelay; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, maxDelay)
+
+	// Equal jitter: wait at least half the backoff
+	half := backoff / 2
+	return half + rand.N(backoff-half+1), true
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an
+// HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,266 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	client "template/packages/api-client-go"
+)
+
+// testServer responds with the statuses in order, repeating the last one,
+// and records the body of every request.
+type testServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	header   http.Header
+	bodies   []string
+}
+
+func newTestServer(t *testing.T, statuses ...int) *testServer {
+	t.Helper()
+
+	s := &testServer{statuses: statuses, header: http.Header{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.mu.Lock()
+		s.bodies = append(s.bodies, string(body))
+		status := s.statuses[min(len(s.bodies), len(s.statuses))-1]
+		for key, values := range s.header {
+			w.Header()[key] = values
+		}
+		s.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *testServer) requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.bodies)
+}
+
+func testRetryPolicy() client.RetryPolicy {
+	return client.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    10 * time.Millisecond,
+	}
+}
+
+func newTestClient(t *testing.T, baseURL string, opts ...client.ClientOption) *client.Client {
+	t.Helper()
+
+	c, err := client.NewClient(baseURL, nil, opts...)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	return c
+}
+
+func TestRetryByMethod(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		key          string
+		wantRequests int
+	}{
+		{name: "GET", method: client.MethodGet, wantRequests: 3},
+		{name: "PUT", method: client.MethodPut, wantRequests: 3},
+		{name: "DELETE", method: client.MethodDelete, wantRequests: 3},
+		{name: "POST without key", method: client.MethodPost, wantRequests: 1},
+		{name: "POST with key", method: client.MethodPost, key: "key-1", wantRequests: 3},
+		{name: "PATCH without key", method: client.MethodPatch, wantRequests: 1},
+		{name: "PATCH with key", method: client.MethodPatch, key: "key-1", wantRequests: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, http.StatusServiceUnavailable)
+			c := newTestClient(t, server.URL, client.WithRetryPolicy(testRetryPolicy()))
+
+			opts := []client.RequestOption{client.WithMethod(tt.method)}
+			if tt.key != "" {
+				opts = append(opts, client.WithIdempotencyKey(tt.key))
+			}
+
+			_, err := c.Request(context.Background(), "/resources", opts...)
+
+			var httpErr *client.HTTPError
+			if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("err = %v, want HTTP 503", err)
+			}
+			if got := server.requests(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestRetryStatuses(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantRequests int
+		wantErr      bool
+	}{
+		{name: "recovers", statuses: []int{http.StatusBadGateway, http.StatusOK}, wantRequests: 2},
+		{name: "rate limited", statuses: []int{http.StatusTooManyRequests, http.StatusOK}, wantRequests: 2},
+		{name: "client error", statuses: []int{http.StatusBadRequest}, wantRequests: 1, wantErr: true},
+		{name: "not found", statuses: []int{http.StatusNotFound}, wantRequests: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.statuses...)
+			c := newTestClient(t, server.URL, client.WithRetryPolicy(testRetryPolicy()))
+
+			_, err := c.Request(context.Background(), "/resources")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := server.requests(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestRetryReplaysBody(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  client.RequestOption
+		want string
+	}{
+		{name: "JSON body", opt: client.WithBody(map[string]string{"amount": "10.00"}), want: `{"amount":"10.00"}`},
+		{name: "body reader", opt: client.WithBodyReader(strings.NewReader("amount=10.00"), "application/x-www-form-urlencoded"), want: "amount=10.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusCreated)
+			c := newTestClient(t, server.URL, client.WithRetryPolicy(testRetryPolicy()))
+
+			_, err := c.Request(context.Background(), "/payments",
+				client.WithMethod(client.MethodPost),
+				client.WithIdempotencyKey("key-1"),
+				tt.opt,
+			)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+
+			if len(server.bodies) != 3 {
+				t.Fatalf("requests = %d, want 3", len(server.bodies))
+			}
+			for i, body := range server.bodies {
+				if body != tt.want {
+					t.Errorf("attempt %d body = %q, want %q", i+1, body, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name         string
+		retryAfter   string
+		wantRequests int
+		wantErr      bool
+	}{
+		{name: "seconds within max delay", retryAfter: "0", wantRequests: 2},
+		{name: "date in the past", retryAfter: time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), wantRequests: 2},
+		{name: "seconds beyond max delay", retryAfter: "120", wantRequests: 1, wantErr: true},
+		{name: "date beyond max delay", retryAfter: time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), wantRequests: 1, wantErr: true},
+		{name: "invalid falls back to backoff", retryAfter: "soon", wantRequests: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, http.StatusTooManyRequests, http.StatusOK)
+			server.header.Set("Retry-After", tt.retryAfter)
+			c := newTestClient(t, server.URL, client.WithRetryPolicy(testRetryPolicy()))
+
+			start := time.Now()
+			_, err := c.Request(context.Background(), "/resources")
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := server.requests(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+			// A Retry-After beyond the max delay is not waited out
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("request took %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestRetryStopsWhenContextIsCancelled(t *testing.T) {
+	server := newTestServer(t, http.StatusServiceUnavailable)
+	policy := testRetryPolicy()
+	policy.BaseDelay = time.Minute
+	policy.MaxDelay = time.Minute
+	c := newTestClient(t, server.URL, client.WithRetryPolicy(policy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := c.Request(ctx, "/resources")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if got := server.requests(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestRetryPolicyDefaultsZeroDelays(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   []int
+		retryAfter string
+		minElapsed time.Duration
+	}{
+		// Two backoffs of at least half of 200ms and 400ms
+		{name: "backs off", statuses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK}, minElapsed: 300 * time.Millisecond},
+		{name: "waits out Retry-After", statuses: []int{http.StatusTooManyRequests, http.StatusOK}, retryAfter: "1", minElapsed: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.statuses...)
+			if tt.retryAfter != "" {
+				server.header.Set("Retry-After", tt.retryAfter)
+			}
+			c := newTestClient(t, server.URL, client.WithRetryPolicy(client.RetryPolicy{MaxAttempts: 3}))
+
+			start := time.Now()
+			if _, err := c.Request(context.Background(), "/resources"); err != nil {
+				t.Fatalf("request: %v", err)
+			}
+
+			if got := server.requests(); got != len(tt.statuses) {
+				t.Errorf("requests = %d, want %d", got, len(tt.statuses))
+			}
+			if elapsed := time.Since(start); elapsed < tt.minElapsed {
+				t.Errorf("request took %v, want at least %v", elapsed, tt.minElapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Decoder decodes a response body into v. json.Unmarshal is the default;
+// common.UnmarshalExternal decodes into structs with `external` tags.
+type Decoder func(data []byte, v interface{}) error
+
+// Response is a decoded response along with its metadata.
+type Response[T any] struct {
+	StatusCode int
+	Header     http.Header
+	Body       T
+}
+
+// APIError is an error response whose body was decoded into E. It unwraps
+// to the *HTTPError of the response.
+type APIError[E any] struct {
+	StatusCode int
+	Header     http.Header
+	Body       E
+	raw        *HTTPError
+}
+
+func (e *APIError[E]) Error() string {
+	return e.raw.Error()
+}
+
+func (e *APIError[E]) Unwrap() error {
+	return e.raw
+}
+
+type errorDecoder func(resp *http.Response, httpErr *HTTPError, decoder Decoder) error
+
+// WithDecoder sets the decoder of the client's typed responses and error
+// bodies.
+func WithDecoder(decoder Decoder) ClientOption {
+	return func(c *Client) {
+		c.decoder = decoder
+	}
+}
+
+// WithErrorBody decodes error responses into an *APIError[E]. Bodies that
+// can't be decoded are returned as a plain *HTTPError.
+func WithErrorBody[E any]() ClientOption {
+	return func(c *Client) {
+		c.errorDecoder = func(resp *http.Response, httpErr *HTTPError, decoder Decoder) error {
+			var body E
+			if err := decoder(httpErr.Body, &body); err != nil {
+				return httpErr
+			}
+
+			return &APIError[E]{
+				StatusCode: resp.StatusCode,
+				Header:     resp.Header,
+				Body:       body,
+				raw:        httpErr,
+			}
+		}
+	}
+}
+
+// WithResponseDecoder overrides the client's decoder for a single request.
+func WithResponseDecoder(decoder Decoder) RequestOption {
+	return func(opts *RequestOptions) {
+		opts.decoder = decoder
+	}
+}
+
+// Do sends a request and decodes the response body into T. An empty body
+// leaves Body as the zero value.
+func Do[T any](ctx context.Context, c *Client, path string, opts ...RequestOption) (*Response[T], error) {
+	reqOpts := defaultRequestOptions()
+	for _, opt := range opts {
+		opt(reqOpts)
+	}
+
+	resp, respBody, err := c.request(ctx, path, reqOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Response[T]{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+	}
+	if len(respBody) == 0 {
+		return result, nil
+	}
+
+	if err := c.responseDecoder(reqOpts)(respBody, &result.Body); err != nil {
+		return nil, errors.Wrap(err, "decoding response body")
+	}
+
+	return result, nil
+}
+
+// Get sends a GET request and decodes the response body into T.
+func Get[T any](ctx context.Context, c *Client, path string, opts ...RequestOption) (*Response[T], error) {
+	return Do[T](ctx, c, path, append([]RequestOption{WithMethod(MethodGet)}, opts...)...)
+}
+
+// Post sends body as JSON and decodes the response body into Resp.
+func Post[Req, Resp any](ctx context.Context, c *Client, path string, body Req, opts ...RequestOption) (*Response[Resp], error) {
+	return Do[Resp](ctx, c, path, append([]RequestOption{WithMethod(MethodPost), WithBody(body)}, opts...)...)
+}
+
+func (c *Client) responseDecoder(reqOpts *RequestOptions) Decoder {
+	if reqOpts.decoder != nil {
+		return reqOpts.decoder
+	}
+	if c.decoder != nil {
+		return c.decoder
+	}
+
+	return json.Unmarshal
+}
+
+// decodeError turns the *HTTPError of a failed request into the client's
+// typed error. Other errors are returned as they are.
+func (c *Client) decodeError(resp *http.Response, reqOpts *RequestOptions, err error) error {
+	var httpErr *HTTPError
+	if c.errorDecoder == nil || resp == nil || !errors.As(err, &httpErr) {
+		return err
+	}
+
+	return c.errorDecoder(resp, httpErr, c.responseDecoder(reqOpts))
+}
@@ -0,0 +1,221 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	client "template/packages/api-client-go"
+)
+
+type webhook struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+type apiErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// respond serves every request with status and body.
+func respond(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req_1")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestDoDecodesBody(t *testing.T) {
+	server := respond(t, http.StatusOK, `{"id":"wh_1","url":"https://example.com/hook"}`)
+	c := newTestClient(t, server.URL)
+
+	resp, err := client.Get[webhook](context.Background(), c, "/webhooks/wh_1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	want := webhook{ID: "wh_1", URL: "https://example.com/hook"}
+	if resp.Body != want {
+		t.Errorf("body = %+v, want %+v", resp.Body, want)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := resp.Header.Get("X-Request-Id"); got != "req_1" {
+		t.Errorf("X-Request-Id = %q, want %q", got, "req_1")
+	}
+}
+
+func TestDoEmptyResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{name: "no content", status: http.StatusNoContent},
+		{name: "empty body", status: http.StatusOK},
+		{name: "accepted", status: http.StatusAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := respond(t, tt.status, "")
+			c := newTestClient(t, server.URL)
+
+			resp, err := client.Do[webhook](context.Background(), c, "/webhooks/wh_1", client.WithMethod(client.MethodDelete))
+			if err != nil {
+				t.Fatalf("do: %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if resp.Body != (webhook{}) {
+				t.Errorf("body = %+v, want the zero value", resp.Body)
+			}
+		})
+	}
+}
+
+func TestDoUndecodableBody(t *testing.T) {
+	server := respond(t, http.StatusOK, `<html>`)
+	c := newTestClient(t, server.URL)
+
+	if _, err := client.Get[webhook](context.Background(), c, "/webhooks/wh_1"); err == nil {
+		t.Error("get succeeded, want a decoding error")
+	}
+}
+
+func TestErrorBody(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []client.ClientOption
+		body      string
+		wantCode  string
+		wantTyped bool
+	}{
+		{
+			name:      "decoded",
+			opts:      []client.ClientOption{client.WithErrorBody[apiErrorBody]()},
+			body:      `{"code":"INVALID_URL","message":"url is invalid"}`,
+			wantCode:  "INVALID_URL",
+			wantTyped: true,
+		},
+		{
+			name: "undecodable",
+			opts: []client.ClientOption{client.WithErrorBody[apiErrorBody]()},
+			body: `upstream unavailable`,
+		},
+		{
+			name: "not configured",
+			body: `{"code":"INVALID_URL","message":"url is invalid"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := respond(t, http.StatusUnprocessableEntity, tt.body)
+			c := newTestClient(t, server.URL, tt.opts...)
+
+			_, err := client.Post[webhook, webhook](context.Background(), c, "/webhooks", webhook{URL: "bad"})
+
+			var apiErr *client.APIError[apiErrorBody]
+			if got := errors.As(err, &apiErr); got != tt.wantTyped {
+				t.Fatalf("err = %v, typed %v, want typed %v", err, got, tt.wantTyped)
+			}
+			if tt.wantTyped {
+				if apiErr.Body.Code != tt.wantCode {
+					t.Errorf("code = %q, want %q", apiErr.Body.Code, tt.wantCode)
+				}
+				if apiErr.StatusCode != http.StatusUnprocessableEntity {
+					t.Errorf("status = %d, want %d", apiErr.StatusCode, http.StatusUnprocessableEntity)
+				}
+				if got := apiErr.Header.Get("X-Request-Id"); got != "req_1" {
+					t.Errorf("X-Request-Id = %q, want %q", got, "req_1")
+				}
+			}
+
+			// Every error response still unwraps to the raw HTTP error
+			var httpErr *client.HTTPError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("err = %v, want an *HTTPError", err)
+			}
+			if httpErr.StatusCode != http.StatusUnprocessableEntity || string(httpErr.Body) != tt.body {
+				t.Errorf("HTTP error = %d %q, want %d %q", httpErr.StatusCode, httpErr.Body, http.StatusUnprocessableEntity, tt.body)
+			}
+		})
+	}
+}
+
+// prefixDecoder decodes JSON from behind a prefix, e.g. a JSON hijacking
+// guard, and counts its calls.
+type prefixDecoder struct {
+	prefix string
+	calls  int
+}
+
+func (d *prefixDecoder) decode(data []byte, v interface{}) error {
+	d.calls++
+	return json.Unmarshal([]byte(strings.TrimPrefix(string(data), d.prefix)), v)
+}
+
+func TestDecoders(t *testing.T) {
+	const body = `)]}'{"id":"wh_1","url":"https://example.com/hook"}`
+
+	t.Run("client decoder", func(t *testing.T) {
+		server := respond(t, http.StatusOK, body)
+		decoder := &prefixDecoder{prefix: ")]}'"}
+		c := newTestClient(t, server.URL, client.WithDecoder(decoder.decode))
+
+		resp, err := client.Get[webhook](context.Background(), c, "/webhooks/wh_1")
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if resp.Body.ID != "wh_1" || decoder.calls != 1 {
+			t.Errorf("id = %q after %d decoder calls, want wh_1 after 1", resp.Body.ID, decoder.calls)
+		}
+	})
+
+	t.Run("request decoder overrides the client's", func(t *testing.T) {
+		server := respond(t, http.StatusOK, body)
+		clientDecoder := &prefixDecoder{}
+		requestDecoder := &prefixDecoder{prefix: ")]}'"}
+		c := newTestClient(t, server.URL, client.WithDecoder(clientDecoder.decode))
+
+		resp, err := client.Get[webhook](context.Background(), c, "/webhooks/wh_1", client.WithResponseDecoder(requestDecoder.decode))
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if resp.Body.ID != "wh_1" {
+			t.Errorf("id = %q, want wh_1", resp.Body.ID)
+		}
+		if clientDecoder.calls != 0 || requestDecoder.calls != 1 {
+			t.Errorf("client decoder calls = %d, request decoder calls = %d, want 0 and 1", clientDecoder.calls, requestDecoder.calls)
+		}
+	})
+
+	t.Run("error bodies", func(t *testing.T) {
+		server := respond(t, http.StatusBadRequest, `)]}'{"code":"INVALID_URL"}`)
+		decoder := &prefixDecoder{prefix: ")]}'"}
+		c := newTestClient(t, server.URL, client.WithDecoder(decoder.decode), client.WithErrorBody[apiErrorBody]())
+
+		_, err := client.Get[webhook](context.Background(), c, "/webhooks/wh_1")
+
+		var apiErr *client.APIError[apiErrorBody]
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("err = %v, want an *APIError", err)
+		}
+		if apiErr.Body.Code != "INVALID_URL" {
+			t.Errorf("code = %q, want %q", apiErr.Body.Code, "INVALID_URL")
+		}
+	})
+}
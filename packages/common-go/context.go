@@ -0,0 +1,20 @@
+package common
+
+import "context"
+
+type contextKey string
+
+const userIDContextKey contextKey = "userID"
+
+// ContextWithUserID stores the authenticated user of a request. The
+// authentication middleware calls it once the caller is verified.
+func ContextWithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDContextKey, userID)
+}
+
+// UserIDFromContext returns the authenticated user, or an empty string for
+// unauthenticated requests.
+func UserIDFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDContextKey).(string)
+	return userID
+}
@@ -18,19 +18,23 @@ type Scheduler interface {
 }
 
 type scheduler struct {
-	jobQueue   chan job
-	workers    int
-	ctx        context.Context
-	cancel     context.CancelFunc
-	wg         sync.WaitGroup
-	retryDelay time.Duration
-	maxRetries int
+	jobQueue      chan job
+	workers       int
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+	retryDelay    time.Duration
+	maxRetryDelay time.Duration
+	exponential   bool
+	maxRetries    int
 }
 
-func NewScheduler(workers int, queueSize int) Scheduler {
+type SchedulerOption func(*scheduler)
+
+func NewScheduler(workers int, queueSize int, opts ...SchedulerOption) Scheduler {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &scheduler{
+	s := &scheduler{
 		jobQueue:   make(chan job, queueSize),
 		workers:    workers,
 		ctx:        ctx,
@@ -38,6 +42,29 @@ func NewScheduler(workers int, queueSize int) Scheduler {
 		retryDelay: time.Second * 5,
 		maxRetries: 3,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithRetryDelay sets the delay before a failed job is retried.
+func WithRetryDelay(delay time.Duration) SchedulerOption {
+	return func(s *scheduler) {
+		s.retryDelay = delay
+	}
+}
+
+// WithExponentialBackoff doubles the retry delay on every attempt,
+// starting from the base delay and never exceeding maxDelay.
+func WithExponentialBackoff(baseDelay, maxDelay time.Duration) SchedulerOption {
+	return func(s *scheduler) {
+		s.retryDelay = baseDelay
+		s.maxRetryDelay = maxDelay
+		s.exponential = true
+	}
 }
 
 func (s *scheduler) Start() {
@@ -78,6 +105,14 @@ func (s *scheduler) ScheduleJobWithDelay(job job, delay time.Duration) {
 		case <-timer.C:
 			if err := s.ScheduleJob(job); err != nil {
 				log.Printf("Failed to schedule delayed job %s: %v", job.GetID(), err)
+
+				// Jobs left behind on shutdown are not dropped, their owner
+				// resumes them on the next start
+				if handler, ok := job.(dropHandler); ok && s.IsRunning() {
+					dropCtx, dropCancel := context.WithTimeout(context.Background(), time.Minute)
+					handler.OnDropped(dropCtx, err)
+					dropCancel()
+				}
 			}
 		case <-s.ctx.Done():
 			return
@@ -123,19 +158,55 @@ func (s *scheduler) executeJob(job job, workerID int) {
 
 		if job.GetRetryCount() < job.GetMaxRetries() {
 			job.IncrementRetry()
+			delay := s.getRetryDelay(job)
 			log.Printf("Worker %d: retrying job %s in %v (attempt %d/%d)",
-				workerID, job.GetID(), s.retryDelay, job.GetRetryCount()+1, job.GetMaxRetries()+1)
+				workerID, job.GetID(), delay, job.GetRetryCount()+1, job.GetMaxRetries()+1)
 
-			s.ScheduleJobWithDelay(job, s.retryDelay)
+			s.ScheduleJobWithDelay(job, delay)
 		} else {
 			log.Printf("Worker %d: job %s exceeded max retries (%d), giving up",
 				workerID, job.GetID(), job.GetMaxRetries())
+
+			// The job context may already be expired if the job timed out
+			if handler, ok := job.(failureHandler); ok {
+				failureCtx, failureCancel := context.WithTimeout(s.ctx, time.Minute)
+				handler.OnFailure(failureCtx, err)
+				failureCancel()
+			}
 		}
 	} else {
 		log.Printf("Worker %d: job %s completed successfully", workerID, job.GetID())
 	}
 }
 
+// getRetryDelay returns the delay before the job's next retry, preferring
+// the job's own backoff
+func (s *scheduler) getRetryDelay(job job) time.Duration {
+	if delayer, ok := job.(retryDelayer); ok {
+		return delayer.RetryDelay(job.GetRetryCount())
+	}
+	if !s.exponential {
+		return s.retryDelay
+	}
+
+	return ExponentialBackoff(s.retryDelay, s.maxRetryDelay, job.GetRetryCount())
+}
+
+// ExponentialBackoff returns the delay before the given retry attempt,
+// doubling baseDelay on every attempt after the first and never exceeding
+// maxDelay. A zero maxDelay leaves the delay unbounded.
+func ExponentialBackoff(baseDelay, maxDelay time.Duration, retryCount int) time.Duration {
+	delay := baseDelay
+	for i := 1; i < retryCount; i++ {
+		delay *= 2
+		if maxDelay > 0 && delay >= maxDelay {
+			return maxDelay
+		}
+	}
+
+	return delay
+}
+
 // GetQueueLength returns the current number of jobs in the queue
 func (s *scheduler) GetQueueLength() int {
 	return len(s.jobQueue)
@@ -1,6 +1,9 @@
 package cronjob
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type job interface {
 	Execute(ctx context.Context) error
@@ -9,3 +12,21 @@ type job interface {
 	IncrementRetry()
 	GetMaxRetries() int
 }
+
+// failureHandler is implemented by jobs that need to react once the
+// scheduler has given up retrying them.
+type failureHandler interface {
+	OnFailure(ctx context.Context, err error)
+}
+
+// dropHandler is implemented by jobs that need to react when a delayed retry
+// could not be queued and was dropped.
+type dropHandler interface {
+	OnDropped(ctx context.Context, err error)
+}
+
+// retryDelayer is implemented by jobs that set their own backoff instead of
+// the scheduler's.
+type retryDelayer interface {
+	RetryDelay(retryCount int) time.Duration
+}
@@ -0,0 +1,41 @@
+package webhook_go
+
+type VerificationError struct {
+	Code    string
+	Message string
+}
+
+func (e *VerificationError) Error() string {
+	return e.Message
+}
+
+var (
+	ErrMissingSignature = &VerificationError{
+		Code:    "MISSING_SIGNATURE",
+		Message: "missing webhook signature",
+	}
+	ErrMissingTimestamp = &VerificationError{
+		Code:    "MISSING_TIMESTAMP",
+		Message: "missing webhook timestamp",
+	}
+	ErrInvalidSignatureFormat = &VerificationError{
+		Code:    "INVALID_SIGNATURE_FORMAT",
+		Message: "invalid webhook signature format",
+	}
+	ErrSignatureMismatch = &VerificationError{
+		Code:    "INVALID_SIGNATURE",
+		Message: "no webhook signature matched",
+	}
+	ErrInvalidTimestamp = &VerificationError{
+		Code:    "INVALID_TIMESTAMP",
+		Message: "invalid webhook timestamp",
+	}
+	ErrTimestampOutsideTolerance = &VerificationError{
+		Code:    "TIMESTAMP_OUTSIDE_TOLERANCE",
+		Message: "webhook timestamp is outside the tolerance window",
+	}
+	ErrReplayed = &VerificationError{
+		Code:    "REPLAYED_WEBHOOK",
+		Message: "webhook signature has already been used",
+	}
+)
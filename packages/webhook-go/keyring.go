@@ -0,0 +1,156 @@
+package webhook_go
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Key is one signing key in a Keyring. A zero NotBefore or ExpiresAt leaves
+// that side of its validity open.
+type Key struct {
+	ID        string
+	Scheme    WebhookVerifier
+	NotBefore time.Time
+	ExpiresAt time.Time
+}
+
+func (k Key) activeAt(now time.Time) bool {
+	if !k.NotBefore.IsZero() && now.Before(k.NotBefore) {
+		return false
+	}
+	if !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt) {
+		return false
+	}
+	return true
+}
+
+// KeyMatcher is implemented by schemes holding several keys, such as
+// Keyring, to report which key a signature was made with.
+type KeyMatcher interface {
+	Match(message, signature []byte) (Key, bool)
+}
+
+// Keyring accepts signatures from any of its active keys, so a secret can be
+// rotated by adding the new key before the provider switches to it and
+// removing the old one afterwards. Keys are listed newest first; the first
+// active one is the primary and is used for signing.
+type Keyring struct {
+	mu   sync.RWMutex
+	keys []Key
+	now  func() time.Time
+}
+
+func NewKeyring(keys ...Key) *Keyring {
+	return &Keyring{
+		keys: keys,
+		now:  time.Now,
+	}
+}
+
+// SetKeys replaces the keys, e.g. after the secrets were reloaded.
+func (k *Keyring) SetKeys(keys ...Key) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.keys = keys
+}
+
+// Active returns the keys valid now, primary first.
+func (k *Keyring) Active() []Key {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	now := k.now()
+	active := make([]Key, 0, len(k.keys))
+	for _, key := range k.keys {
+		if key.activeAt(now) {
+			active = append(active, key)
+		}
+	}
+
+	return active
+}
+
+// Match returns the active key the signature was made with.
+func (k *Keyring) Match(message, signature []byte) (Key, bool) {
+	for _, key := range k.Active() {
+		if key.Scheme.Verify(message, signature) {
+			return key, true
+		}
+	}
+
+	return Key{}, false
+}
+
+func (k *Keyring) Verify(message, signature []byte) bool {
+	_, ok := k.Match(message, signature)
+	return ok
+}
+
+// Sign signs with the primary key. It returns nil when no active key can
+// sign.
+func (k *Keyring) Sign(message []byte) []byte {
+	for _, key := range k.Active() {
+		if signer, ok := key.Scheme.(WebhookSigner); ok {
+			return signer.Sign(message)
+		}
+	}
+
+	return nil
+}
+
+// Secret is a shared signing secret as loaded from configuration.
+type Secret struct {
+	ID        string     `json:"id"`
+	Value     string     `json:"secret"`
+	NotBefore *time.Time `json:"notBefore,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// ParseSecrets reads a JSON array of secrets, e.g.
+// [{"id":"2025-q3","secret":"...","notBefore":"2025-07-01T00:00:00Z"}].
+func ParseSecrets(data string) ([]Secret, error) {
+	var secrets []Secret
+	if err := json.Unmarshal([]byte(data), &secrets); err != nil {
+		return nil, errors.Wrap(err, "parsing webhook secrets")
+	}
+
+	// A repeated ID would make the key a signature matched ambiguous
+	ids := make(map[string]bool, len(secrets))
+	for i, secret := range secrets {
+		if secret.ID == "" {
+			return nil, errors.Errorf("webhook secret %d has no id", i)
+		}
+		if secret.Value == "" {
+			return nil, errors.Errorf("webhook secret %s is empty", secret.ID)
+		}
+		if ids[secret.ID] {
+			return nil, errors.Errorf("webhook secret id %s is used more than once", secret.ID)
+		}
+		ids[secret.ID] = true
+	}
+
+	return secrets, nil
+}
+
+// NewSecretKeyring builds a keyring with one scheme per secret.
+func NewSecretKeyring(secrets []Secret, scheme func(secret string) WebhookVerifier) *Keyring {
+	keys := make([]Key, len(secrets))
+	for i, secret := range secrets {
+		keys[i] = Key{
+			ID:     secret.ID,
+			Scheme: scheme(secret.Value),
+		}
+		if secret.NotBefore != nil {
+			keys[i].NotBefore = *secret.NotBefore
+		}
+		if secret.ExpiresAt != nil {
+			keys[i].ExpiresAt = *secret.ExpiresAt
+		}
+	}
+
+	return NewKeyring(keys...)
+}
@@ -0,0 +1,48 @@
+package webhook_go_test
+
+import (
+	"testing"
+
+	webhookSDK "template/packages/webhook-go"
+)
+
+func TestParseSecrets(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "valid", data: `[{"id":"new","secret":"a"},{"id":"old","secret":"b"}]`},
+		{name: "missing id", data: `[{"secret":"a"}]`, wantErr: true},
+		{name: "empty secret", data: `[{"id":"new","secret":""}]`, wantErr: true},
+		{name: "duplicate id", data: `[{"id":"new","secret":"a"},{"id":"new","secret":"b"}]`, wantErr: true},
+		{name: "not json", data: `new:a`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := webhookSDK.ParseSecrets(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyReportsMatchingKey(t *testing.T) {
+	keyring := webhookSDK.NewSecretKeyring([]webhookSDK.Secret{
+		{ID: "new", Value: "whsec_new"},
+		{ID: "old", Value: testSecret},
+	}, func(secret string) webhookSDK.WebhookVerifier {
+		return webhookSDK.NewHMACSHA256Hex(secret)
+	})
+	verifier := webhookSDK.NewSignatureVerifier(webhookSDK.TimestampedFormat(testHeader), keyring)
+
+	verified, err := verifier.Verify([]byte(testBody), signedHeaders(unix(testNow), testBody))
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if verified.KeyID != "old" {
+		t.Errorf("KeyID = %q, want %q", verified.KeyID, "old")
+	}
+}
@@ -0,0 +1,104 @@
+package webhook_go
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const DefaultTolerance = 5 * time.Minute
+
+// NonceStore remembers the messages already accepted so a captured request
+// can't be replayed within the tolerance window.
+type NonceStore interface {
+	// Remember records nonce until expiresAt. It returns false when the nonce
+	// was already recorded and has not expired yet.
+	Remember(ctx context.Context, nonce string, expiresAt time.Time) (bool, error)
+	// Forget removes a recorded nonce. Forgetting an unknown nonce is not an
+	// error.
+	Forget(ctx context.Context, nonce string) error
+}
+
+// MemoryNonceStore is a NonceStore for a single instance. It holds at most
+// capacity nonces, dropping the oldest once full.
+type MemoryNonceStore struct {
+	mu       sync.Mutex
+	capacity int
+	now      func() time.Time
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type memoryNonce struct {
+	nonce     string
+	expiresAt time.Time
+}
+
+const DefaultNonceCapacity = 10000
+
+func NewMemoryNonceStore(capacity int) *MemoryNonceStore {
+	if capacity <= 0 {
+		capacity = DefaultNonceCapacity
+	}
+
+	return &MemoryNonceStore{
+		capacity: capacity,
+		now:      time.Now,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (s *MemoryNonceStore) Remember(_ context.Context, nonce string, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if element, ok := s.entries[nonce]; ok {
+		if element.Value.(*memoryNonce).expiresAt.After(now) {
+			return false, nil
+		}
+		s.remove(element)
+	}
+
+	s.evict(now)
+
+	s.entries[nonce] = s.order.PushBack(&memoryNonce{nonce: nonce, expiresAt: expiresAt})
+	return true, nil
+}
+
+func (s *MemoryNonceStore) Forget(_ context.Context, nonce string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[nonce]; ok {
+		s.remove(element)
+	}
+
+	return nil
+}
+
+// evict drops expired nonces from the front, then the oldest ones while the
+// store is full. Nonces are mostly added in expiry order, so an expired one
+// behind a live one is left until it reaches the front.
+func (s *MemoryNonceStore) evict(now time.Time) {
+	for front := s.order.Front(); front != nil; front = s.order.Front() {
+		if front.Value.(*memoryNonce).expiresAt.After(now) && s.order.Len() < s.capacity {
+			return
+		}
+		s.remove(front)
+	}
+}
+
+func (s *MemoryNonceStore) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.entries, element.Value.(*memoryNonce).nonce)
+}
+
+func (s *MemoryNonceStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.order.Len()
+}
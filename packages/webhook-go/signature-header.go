@@ -0,0 +1,120 @@
+package webhook_go
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Headers gives case-insensitive access to request headers. http.Header
+// implements it.
+type Headers interface {
+	Get(key string) string
+}
+
+// MapHeaders adapts headers flattened into a map.
+type MapHeaders map[string]string
+
+func (h MapHeaders) Get(key string) string {
+	if value, ok := h[key]; ok {
+		return value
+	}
+	if value, ok := h[http.CanonicalHeaderKey(key)]; ok {
+		return value
+	}
+	for name, value := range h {
+		if strings.EqualFold(name, key) {
+			return value
+		}
+	}
+	return ""
+}
+
+// HeaderFormat describes where a provider puts its signature.
+//
+// With SignatureKey set the header is a list of key/value pairs, e.g.
+// "t=1700000000,v1=abc,v1=def", and every value under SignatureKey is a
+// candidate signature. Without it the whole header value is the signature.
+type HeaderFormat struct {
+	Header string
+
+	PairSeparator     string
+	KeyValueSeparator string
+	SignatureKey      string
+
+	// TimestampKey names the timestamp pair in Header. TimestampHeader is used
+	// instead for providers that send the timestamp in a header of its own.
+	TimestampKey    string
+	TimestampHeader string
+}
+
+// ParsedSignature is what a HeaderFormat extracted from a request.
+type ParsedSignature struct {
+	Timestamp  string
+	Signatures [][]byte
+}
+
+// TimestampedFormat is the Stripe-style format: "t=<unix>,v1=<sig>[,v1=<sig>]".
+func TimestampedFormat(header string) HeaderFormat {
+	return HeaderFormat{
+		Header:            header,
+		PairSeparator:     ",",
+		KeyValueSeparator: "=",
+		SignatureKey:      "v1",
+		TimestampKey:      "t",
+	}
+}
+
+// RawFormat is a header holding only the signature.
+func RawFormat(header string) HeaderFormat {
+	return HeaderFormat{
+		Header: header,
+	}
+}
+
+func (f HeaderFormat) Parse(headers Headers) (ParsedSignature, error) {
+	var parsed ParsedSignature
+
+	value := strings.TrimSpace(headers.Get(f.Header))
+	if value == "" {
+		return parsed, ErrMissingSignature
+	}
+
+	if f.TimestampHeader != "" {
+		parsed.Timestamp = strings.TrimSpace(headers.Get(f.TimestampHeader))
+		if parsed.Timestamp == "" {
+			return parsed, ErrMissingTimestamp
+		}
+	}
+
+	if f.SignatureKey == "" {
+		parsed.Signatures = [][]byte{[]byte(value)}
+		return parsed, nil
+	}
+
+	for _, pair := range strings.Split(value, f.PairSeparator) {
+		key, val, found := strings.Cut(strings.TrimSpace(pair), f.KeyValueSeparator)
+		if !found {
+			return parsed, ErrInvalidSignatureFormat
+		}
+
+		switch key {
+		case f.SignatureKey:
+			if val != "" {
+				parsed.Signatures = append(parsed.Signatures, []byte(val))
+			}
+		case f.TimestampKey:
+			if f.TimestampHeader == "" {
+				parsed.Timestamp = val
+			}
+		}
+	}
+
+	if len(parsed.Signatures) == 0 {
+		return parsed, ErrMissingSignature
+	}
+	if f.TimestampKey != "" && f.TimestampHeader == "" && parsed.Timestamp == "" {
+		return parsed, ErrMissingTimestamp
+	}
+
+	return parsed, nil
+}
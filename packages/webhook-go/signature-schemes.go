@@ -0,0 +1,117 @@
+package webhook_go
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+)
+
+// Encoding is how a signature is written in a header.
+type Encoding int
+
+const (
+	EncodingHex Encoding = iota
+	EncodingBase64
+	EncodingBase64URL
+)
+
+func (e Encoding) encode(raw []byte) []byte {
+	switch e {
+	case EncodingBase64:
+		return []byte(base64.StdEncoding.EncodeToString(raw))
+	case EncodingBase64URL:
+		return []byte(base64.RawURLEncoding.EncodeToString(raw))
+	default:
+		return []byte(hex.EncodeToString(raw))
+	}
+}
+
+func (e Encoding) decode(encoded []byte) ([]byte, error) {
+	switch e {
+	case EncodingBase64:
+		return base64.StdEncoding.DecodeString(string(encoded))
+	case EncodingBase64URL:
+		return base64.RawURLEncoding.DecodeString(string(encoded))
+	default:
+		return hex.DecodeString(string(encoded))
+	}
+}
+
+// HMACScheme signs and verifies with a shared secret.
+type HMACScheme struct {
+	hash     func() hash.Hash
+	secret   []byte
+	encoding Encoding
+}
+
+func NewHMACScheme(hash func() hash.Hash, secret string, encoding Encoding) *HMACScheme {
+	return &HMACScheme{
+		hash:     hash,
+		secret:   []byte(secret),
+		encoding: encoding,
+	}
+}
+
+func NewHMACSHA256Hex(secret string) *HMACScheme {
+	return NewHMACScheme(sha256.New, secret, EncodingHex)
+}
+
+func NewHMACSHA256Base64(secret string) *HMACScheme {
+	return NewHMACScheme(sha256.New, secret, EncodingBase64)
+}
+
+func NewHMACSHA512Base64(secret string) *HMACScheme {
+	return NewHMACScheme(sha512.New, secret, EncodingBase64)
+}
+
+func (s *HMACScheme) Sign(message []byte) []byte {
+	mac := hmac.New(s.hash, s.secret)
+	mac.Write(message)
+
+	return s.encoding.encode(mac.Sum(nil))
+}
+
+func (s *HMACScheme) Verify(message, signature []byte) bool {
+	// Compare the decoded bytes so encodings that allow several spellings of
+	// the same signature (e.g. hex case) are accepted
+	decoded, err := s.encoding.decode(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(s.hash, s.secret)
+	mac.Write(message)
+
+	return hmac.Equal(decoded, mac.Sum(nil))
+}
+
+// Ed25519Scheme verifies signatures made with the provider's private key. It
+// can't sign.
+type Ed25519Scheme struct {
+	publicKey ed25519.PublicKey
+	encoding  Encoding
+}
+
+func NewEd25519Scheme(publicKey ed25519.PublicKey, encoding Encoding) *Ed25519Scheme {
+	return &Ed25519Scheme{
+		publicKey: publicKey,
+		encoding:  encoding,
+	}
+}
+
+func (s *Ed25519Scheme) Verify(message, signature []byte) bool {
+	if len(s.publicKey) != ed25519.PublicKeySize {
+		return false
+	}
+
+	decoded, err := s.encoding.decode(signature)
+	if err != nil || len(decoded) != ed25519.SignatureSize {
+		return false
+	}
+
+	return ed25519.Verify(s.publicKey, message, decoded)
+}
@@ -0,0 +1,229 @@
+package webhook_go
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// PayloadFunc builds the signed message from the timestamp, if any, and the
+// raw body.
+type PayloadFunc func(timestamp string, body []byte) []byte
+
+// TimestampedPayload signs "<timestamp>.<body>", or only the body when there
+// is no timestamp.
+func TimestampedPayload(timestamp string, body []byte) []byte {
+	if timestamp == "" {
+		return body
+	}
+
+	message := make([]byte, 0, len(timestamp)+1+len(body))
+	message = append(message, timestamp...)
+	message = append(message, '.')
+	return append(message, body...)
+}
+
+// BodyPayload signs the body alone.
+func BodyPayload(_ string, body []byte) []byte {
+	return body
+}
+
+// VerifiedSignature describes the signature that matched.
+type VerifiedSignature struct {
+	Timestamp string
+	Signature []byte
+	// KeyID is the matching key when the scheme is a KeyMatcher
+	KeyID string
+	// Nonce identifies the signed message in the NonceStore
+	Nonce string
+}
+
+// SignatureVerifier checks requests signed by one provider: it parses the
+// signature header with its HeaderFormat and accepts the request when any of
+// the signatures matches the scheme.
+type SignatureVerifier struct {
+	format  HeaderFormat
+	scheme  WebhookVerifier
+	payload PayloadFunc
+
+	// tolerance bounds how far the signed timestamp may be from now. Zero
+	// disables the check.
+	tolerance time.Duration
+	nonces    NonceStore
+	now       func() time.Time
+}
+
+type SignatureVerifierOption func(*SignatureVerifier)
+
+// WithPayload overrides how the signed message is built. The default is
+// TimestampedPayload.
+func WithPayload(payload PayloadFunc) SignatureVerifierOption {
+	return func(v *SignatureVerifier) {
+		v.payload = payload
+	}
+}
+
+// WithTolerance rejects requests whose signed timestamp, in Unix seconds, is
+// further than tolerance from now. The format must carry a timestamp.
+func WithTolerance(tolerance time.Duration) SignatureVerifierOption {
+	return func(v *SignatureVerifier) {
+		v.tolerance = tolerance
+	}
+}
+
+// WithNonceStore rejects a message that was already accepted. The nonce is a
+// hash of the signed message, i.e. the timestamp and body, so re-encoding the
+// signature doesn't make a replay look new. Nonces are kept for twice the
+// tolerance, after which the timestamp check rejects the request anyway.
+func WithNonceStore(store NonceStore) SignatureVerifierOption {
+	return func(v *SignatureVerifier) {
+		v.nonces = store
+	}
+}
+
+// WithClock overrides the time source, e.g. in tests.
+func WithClock(now func() time.Time) SignatureVerifierOption {
+	return func(v *SignatureVerifier) {
+		v.now = now
+	}
+}
+
+func NewSignatureVerifier(format HeaderFormat, scheme WebhookVerifier, opts ...SignatureVerifierOption) *SignatureVerifier {
+	v := &SignatureVerifier{
+		format:  format,
+		scheme:  scheme,
+		payload: TimestampedPayload,
+		now:     time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+func (v *SignatureVerifier) Verify(body []byte, headers Headers) (*VerifiedSignature, error) {
+	return v.VerifyContext(context.Background(), body, headers)
+}
+
+// VerifyContext is Verify with a context for the nonce store.
+func (v *SignatureVerifier) VerifyContext(ctx context.Context, body []byte, headers Headers) (*VerifiedSignature, error) {
+	parsed, err := v.format.Parse(headers)
+	if err != nil {
+		return nil, err
+	}
+
+	// The timestamp is only trusted once the signature covering it matched
+	var verified *VerifiedSignature
+	message := v.payload(parsed.Timestamp, body)
+	for _, signature := range parsed.Signatures {
+		if keyID, ok := v.match(message, signature); ok {
+			verified = &VerifiedSignature{
+				Timestamp: parsed.Timestamp,
+				Signature: signature,
+				KeyID:     keyID,
+			}
+			break
+		}
+	}
+	if verified == nil {
+		return nil, ErrSignatureMismatch
+	}
+
+	if v.tolerance > 0 {
+		if err := v.checkTimestamp(parsed.Timestamp); err != nil {
+			return nil, err
+		}
+	}
+
+	if v.nonces != nil {
+		ttl := 2 * v.tolerance
+		if ttl == 0 {
+			ttl = 2 * DefaultTolerance
+		}
+
+		verified.Nonce = nonce(message)
+		fresh, err := v.nonces.Remember(ctx, verified.Nonce, v.now().Add(ttl))
+		if err != nil {
+			return nil, err
+		}
+		if !fresh {
+			return nil, ErrReplayed
+		}
+	}
+
+	return verified, nil
+}
+
+// match verifies a signature, returning the key it was made with when the
+// scheme is a KeyMatcher.
+func (v *SignatureVerifier) match(message, signature []byte) (string, bool) {
+	if matcher, ok := v.scheme.(KeyMatcher); ok {
+		key, ok := matcher.Match(message, signature)
+		return key.ID, ok
+	}
+
+	return "", v.scheme.Verify(message, signature)
+}
+
+// Forget removes the nonce of a request accepted by VerifyContext, so the
+// sender's retry isn't rejected as a replay when the request could not be
+// handled, e.g. its event could not be stored.
+func (v *SignatureVerifier) Forget(ctx context.Context, body []byte, headers Headers) error {
+	if v.nonces == nil {
+		return nil
+	}
+
+	parsed, err := v.format.Parse(headers)
+	if err != nil {
+		return err
+	}
+
+	return v.nonces.Forget(ctx, nonce(v.payload(parsed.Timestamp, body)))
+}
+
+func nonce(message []byte) string {
+	sum := sha256.Sum256(message)
+	return hex.EncodeToString(sum[:])
+}
+
+func (v *SignatureVerifier) checkTimestamp(timestamp string) error {
+	if timestamp == "" {
+		return ErrMissingTimestamp
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+
+	age := v.now().Sub(time.Unix(seconds, 0))
+	if age > v.tolerance || age < -v.tolerance {
+		return ErrTimestampOutsideTolerance
+	}
+
+	return nil
+}
+
+// Sign returns the header value a sender using format would set. The scheme
+// must be able to sign.
+func Sign(format HeaderFormat, signer WebhookSigner, timestamp string, body []byte, payload PayloadFunc) string {
+	if payload == nil {
+		payload = TimestampedPayload
+	}
+
+	signature := string(signer.Sign(payload(timestamp, body)))
+	if format.SignatureKey == "" {
+		return signature
+	}
+
+	value := format.SignatureKey + format.KeyValueSeparator + signature
+	if format.TimestampKey != "" && format.TimestampHeader == "" {
+		value = format.TimestampKey + format.KeyValueSeparator + timestamp + format.PairSeparator + value
+	}
+
+	return value
+}
@@ -0,0 +1,175 @@
+package webhook_go_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	webhookSDK "template/packages/webhook-go"
+)
+
+const (
+	testSecret = "whsec_test"
+	testHeader = "Webhook-Signature"
+	testBody   = `{"id":"evt_1"}`
+)
+
+// testNow is near the real time, since MemoryNonceStore expires nonces by
+// the wall clock
+var testNow = time.Now().Truncate(time.Second)
+
+func newTestVerifier(nonces webhookSDK.NonceStore) *webhookSDK.SignatureVerifier {
+	opts := []webhookSDK.SignatureVerifierOption{
+		webhookSDK.WithTolerance(webhookSDK.DefaultTolerance),
+		webhookSDK.WithClock(func() time.Time { return testNow }),
+	}
+	if nonces != nil {
+		opts = append(opts, webhookSDK.WithNonceStore(nonces))
+	}
+
+	return webhookSDK.NewSignatureVerifier(
+		webhookSDK.TimestampedFormat(testHeader),
+		webhookSDK.NewHMACSHA256Hex(testSecret),
+		opts...,
+	)
+}
+
+func signedHeaders(timestamp, body string) http.Header {
+	headers := http.Header{}
+	headers.Set(testHeader, webhookSDK.Sign(
+		webhookSDK.TimestampedFormat(testHeader),
+		webhookSDK.NewHMACSHA256Hex(testSecret),
+		timestamp,
+		[]byte(body),
+		nil,
+	))
+	return headers
+}
+
+func unix(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+func TestVerifyTimestampTolerance(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp string
+		want      error
+	}{
+		{name: "now", timestamp: unix(testNow)},
+		{name: "within tolerance in the past", timestamp: unix(testNow.Add(-4 * time.Minute))},
+		{name: "within tolerance in the future", timestamp: unix(testNow.Add(4 * time.Minute))},
+		{name: "at the tolerance", timestamp: unix(testNow.Add(-webhookSDK.DefaultTolerance))},
+		{name: "too old", timestamp: unix(testNow.Add(-6 * time.Minute)), want: webhookSDK.ErrTimestampOutsideTolerance},
+		{name: "too far in the future", timestamp: unix(testNow.Add(6 * time.Minute)), want: webhookSDK.ErrTimestampOutsideTolerance},
+		{name: "not a number", timestamp: "yesterday", want: webhookSDK.ErrInvalidTimestamp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTestVerifier(nil).Verify([]byte(testBody), signedHeaders(tt.timestamp, testBody))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifySignatureMismatchBeforeTimestamp(t *testing.T) {
+	// A stale request with a forged signature is reported as a mismatch, so
+	// an unverified timestamp is never trusted
+	headers := signedHeaders(unix(testNow.Add(-time.Hour)), testBody)
+
+	_, err := newTestVerifier(nil).Verify([]byte(`{"id":"evt_2"}`), headers)
+	if !errors.Is(err, webhookSDK.ErrSignatureMismatch) {
+		t.Errorf("err = %v, want %v", err, webhookSDK.ErrSignatureMismatch)
+	}
+}
+
+func TestVerifyReplay(t *testing.T) {
+	timestamp := unix(testNow)
+	headers := signedHeaders(timestamp, testBody)
+
+	// The same signature spelled in upper case hex decodes to the same MAC
+	reencoded := http.Header{}
+	reencoded.Set(testHeader, "t="+timestamp+",v1="+strings.ToUpper(strings.TrimPrefix(headers.Get(testHeader), "t="+timestamp+",v1=")))
+
+	tests := []struct {
+		name    string
+		replay  http.Header
+		body    string
+		wantErr error
+	}{
+		{name: "same request", replay: headers, body: testBody, wantErr: webhookSDK.ErrReplayed},
+		{name: "re-encoded signature", replay: reencoded, body: testBody, wantErr: webhookSDK.ErrReplayed},
+		{name: "new timestamp", replay: signedHeaders(unix(testNow.Add(time.Second)), testBody), body: testBody},
+		{name: "new body", replay: signedHeaders(timestamp, `{"id":"evt_2"}`), body: `{"id":"evt_2"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := newTestVerifier(webhookSDK.NewMemoryNonceStore(0))
+
+			if _, err := verifier.Verify([]byte(testBody), headers); err != nil {
+				t.Fatalf("first request: %v", err)
+			}
+
+			_, err := verifier.Verify([]byte(tt.body), tt.replay)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestForgetAcceptsRetry(t *testing.T) {
+	nonces := webhookSDK.NewMemoryNonceStore(0)
+	verifier := newTestVerifier(nonces)
+	headers := signedHeaders(unix(testNow), testBody)
+
+	if _, err := verifier.Verify([]byte(testBody), headers); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	if err := verifier.Forget(context.Background(), []byte(testBody), headers); err != nil {
+		t.Fatalf("forget: %v", err)
+	}
+	if nonces.Len() != 0 {
+		t.Errorf("store holds %d nonces, want 0", nonces.Len())
+	}
+
+	if _, err := verifier.Verify([]byte(testBody), headers); err != nil {
+		t.Errorf("retried request: %v", err)
+	}
+}
+
+func TestMemoryNonceStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	store := webhookSDK.NewMemoryNonceStore(2)
+
+	fresh, _ := store.Remember(ctx, "a", time.Now().Add(-time.Second))
+	if !fresh {
+		t.Fatal("first nonce was not fresh")
+	}
+
+	// An expired nonce may be reused
+	if fresh, _ := store.Remember(ctx, "a", time.Now().Add(time.Minute)); !fresh {
+		t.Error("expired nonce was rejected")
+	}
+	if fresh, _ := store.Remember(ctx, "a", time.Now().Add(time.Minute)); fresh {
+		t.Error("unexpired nonce was accepted")
+	}
+
+	// The oldest nonce is dropped once the store is full
+	store.Remember(ctx, "b", time.Now().Add(time.Minute))
+	store.Remember(ctx, "c", time.Now().Add(time.Minute))
+	if store.Len() != 2 {
+		t.Errorf("store holds %d nonces, want 2", store.Len())
+	}
+	if fresh, _ := store.Remember(ctx, "a", time.Now().Add(time.Minute)); !fresh {
+		t.Error("evicted nonce was rejected")
+	}
+}
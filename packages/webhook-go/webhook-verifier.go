@@ -10,6 +10,13 @@ type WebhookVerifier interface {
 	Verify(message, signature []byte) bool
 }
 
+// WebhookSigner is implemented by the schemes that can also produce
+// signatures, i.e. the shared-secret ones.
+type WebhookSigner interface {
+	Sign(message []byte) []byte
+}
+
+// Verifier checks hex encoded HMAC-SHA256 signatures.
 type Verifier struct {
 	secret string
 }
@@ -21,9 +28,12 @@ func NewVerifier(secret string) *Verifier {
 }
 
 func (v *Verifier) Verify(body, signature []byte) bool {
+	return hmac.Equal(signature, v.Sign(body))
+}
+
+func (v *Verifier) Sign(body []byte) []byte {
 	mac := hmac.New(crypto.SHA256.New, []byte(v.secret))
 	mac.Write(body)
 
-	expectedMAC := hex.EncodeToString(mac.Sum(nil))
-	return hmac.Equal(signature, []byte(expectedMAC))
+	return []byte(hex.EncodeToString(mac.Sum(nil)))
 }